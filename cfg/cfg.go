@@ -0,0 +1,511 @@
+package cfg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/dlanell/go-rdparser/parser/printer"
+)
+
+// Edge kinds, Normal edges are unconditional, True and False leave the block of a test
+// and Exception edges leave a block that may throw.
+const (
+	Normal    string = ""
+	True             = "true"
+	False            = "false"
+	Exception        = "exception"
+)
+
+// Builder
+// frames holds the enclosing statements a jump may leave.
+type Builder struct {
+	graph   *Graph
+	current *Block
+	frames  []*frame
+}
+
+type Props struct{}
+
+// Graph
+// Entry is the block control starts in and Exit the empty block every path ends in,
+// Blocks holds all blocks in the order they were created.
+type Graph struct {
+	Entry  *Block
+	Exit   *Block
+	Blocks []*Block
+}
+
+// Block is a basic block, its Nodes run one after the other. Nodes are statements,
+// the tests of if and switch statements, which end their block, and the parameter a
+// catch clause starts with. Compound statements are never nodes, their parts are.
+type Block struct {
+	Index        int
+	Nodes        []*parser.Node
+	Successors   []*Edge
+	Predecessors []*Edge
+}
+
+type Edge struct {
+	From *Block
+	To   *Block
+	Kind string
+}
+
+// frame is a statement a jump may leave, a labeled statement or switch that break
+// statements end, or a try statement whose catch clause and finally block jumps out of
+// it go through. breaks, throws and finals hold the blocks jumping to the block after
+// the statement, the catch clause and the finally block, after the jumps to continue
+// once the finally block ends.
+type frame struct {
+	label     string
+	breakable bool
+	breaks    []exit
+	handler   bool
+	finalizer bool
+	throws    []exit
+	finals    []exit
+	after     []jump
+}
+
+type exit struct {
+	block *Block
+	kind  string
+}
+
+// jump leaves for target, or for the end of the graph when target is nil.
+type jump struct {
+	target    *frame
+	exception bool
+}
+
+func New(props Props) *Builder {
+	return &Builder{}
+}
+
+// Run builds the graph of the top-level statements, function bodies are left to
+// Function.
+func (b *Builder) Run(program *parser.Program) *Graph {
+	b.reset()
+	for _, statement := range program.Body {
+		b.statement(statement)
+	}
+	b.connect(b.current, b.graph.Exit, Normal)
+	return b.graph
+}
+
+// Function builds the graph of the function body, an expression body is a single node.
+func (b *Builder) Function(function *parser.FunctionValue) *Graph {
+	b.reset()
+	if function.Expression {
+		b.add(function.Body)
+	} else {
+		for _, statement := range function.Body.Body.([]*parser.Node) {
+			b.statement(statement)
+		}
+	}
+	b.connect(b.current, b.graph.Exit, Normal)
+	return b.graph
+}
+
+func (b *Builder) reset() {
+	b.graph = &Graph{Blocks: make([]*Block, 0)}
+	b.frames = nil
+	b.graph.Entry = b.newBlock()
+	b.graph.Exit = b.newBlock()
+	b.current = b.graph.Entry
+}
+
+func (b *Builder) statement(node *parser.Node) {
+	switch node.NodeType {
+	case parser.BlockStatement:
+		for _, statement := range node.Body.([]*parser.Node) {
+			b.statement(statement)
+		}
+	case parser.IfStatement:
+		b.ifStatement(node.Body.(*parser.IfStatementValue))
+	case parser.SwitchStatement:
+		b.switchStatement(node.Body.(*parser.SwitchStatementValue))
+	case parser.LabeledStatement:
+		value := node.Body.(*parser.LabeledStatementValue)
+		target := b.push(&frame{label: value.Label.Body.(*parser.StringLiteralValue).Value})
+		b.statement(value.Body)
+		b.pop()
+		b.join(target.breaks)
+	case parser.BreakStatement:
+		b.add(node)
+		if target := b.breakTarget(node); target != nil {
+			b.leave(b.current, Normal, &jump{target: target})
+		}
+		b.current = nil
+	case parser.ReturnStatement:
+		b.add(node)
+		b.leave(b.current, Normal, &jump{})
+		b.current = nil
+	case parser.ThrowStatement:
+		b.add(node)
+		b.leave(b.current, Exception, &jump{exception: true})
+		b.current = nil
+	case parser.TryStatement:
+		b.tryStatement(node.Body.(*parser.TryStatementValue))
+	default:
+		b.add(node)
+	}
+}
+
+func (b *Builder) ifStatement(node *parser.IfStatementValue) {
+	b.add(node.Test)
+	test := b.current
+
+	b.current = b.newBlock()
+	b.connect(test, b.current, True)
+	b.statement(node.Consequent)
+	exits := []exit{{block: b.current}}
+
+	if node.Alternate == nil {
+		exits = append(exits, exit{block: test, kind: False})
+	} else {
+		b.current = b.newBlock()
+		b.connect(test, b.current, False)
+		b.statement(node.Alternate)
+		exits = append(exits, exit{block: b.current})
+	}
+
+	b.current = nil
+	b.join(exits)
+}
+
+// switchStatement tests the cases one after the other, a case without a break falls
+// through to the next one.
+func (b *Builder) switchStatement(node *parser.SwitchStatementValue) {
+	b.add(node.Discriminant)
+	test, kind := b.current, Normal
+	bodies := make([]*Block, len(node.Cases))
+	defaultIndex := -1
+	for index, clause := range node.Cases {
+		value := clause.Body.(*parser.SwitchCaseValue)
+		if value.Test == nil {
+			defaultIndex = index
+			continue
+		}
+		if kind == False {
+			next := b.newBlock()
+			b.connect(test, next, False)
+			test = next
+		}
+		test.Nodes = append(test.Nodes, value.Test)
+		bodies[index] = b.newBlock()
+		b.connect(test, bodies[index], True)
+		kind = False
+	}
+
+	target := b.push(&frame{breakable: true})
+	if defaultIndex == -1 {
+		target.breaks = append(target.breaks, exit{block: test, kind: kind})
+	} else {
+		bodies[defaultIndex] = b.newBlock()
+		b.connect(test, bodies[defaultIndex], kind)
+	}
+
+	b.current = nil
+	for index, clause := range node.Cases {
+		b.connect(b.current, bodies[index], Normal)
+		b.current = bodies[index]
+		for _, statement := range clause.Body.(*parser.SwitchCaseValue).Consequent {
+			b.statement(statement)
+		}
+	}
+	b.pop()
+	target.breaks = append(target.breaks, exit{block: b.current})
+	b.current = nil
+	b.join(target.breaks)
+}
+
+// tryStatement gives every block of the try block that may throw an exception edge to
+// the catch clause, or to the finally block, unless a nested try statement already
+// took its exceptions. Jumps out of the try block and the catch clause go through the
+// finally block and continue from its end.
+func (b *Builder) tryStatement(node *parser.TryStatementValue) {
+	block := b.newBlock()
+	b.connect(b.current, block, Normal)
+	b.current = block
+
+	target := b.push(&frame{handler: node.Handler != nil, finalizer: node.Finalizer != nil})
+	b.statement(node.Block)
+	for _, block := range b.graph.Blocks[block.Index:] {
+		if len(block.Nodes) > 0 && !throws(block) {
+			b.leave(block, Exception, &jump{exception: true})
+		}
+	}
+	exits := []exit{{block: b.current}}
+
+	target.handler = false
+	if node.Handler != nil {
+		handler := node.Handler.Body.(*parser.CatchClauseValue)
+		b.current = b.newBlock()
+		for _, throw := range target.throws {
+			b.connect(throw.block, b.current, throw.kind)
+		}
+		if handler.Param != nil {
+			b.add(handler.Param)
+		}
+		b.statement(handler.Body)
+		exits = append(exits, exit{block: b.current})
+	}
+
+	target.finalizer = false
+	if node.Finalizer == nil {
+		b.pop()
+		b.current = nil
+		b.join(exits)
+		return
+	}
+
+	b.current = b.newBlock()
+	completes := false
+	for _, completion := range exits {
+		completes = completes || completion.block != nil
+		b.connect(completion.block, b.current, Normal)
+	}
+	for _, final := range target.finals {
+		b.connect(final.block, b.current, final.kind)
+	}
+	b.statement(node.Finalizer)
+	b.pop()
+	end := b.current
+	for _, after := range target.after {
+		kind := Normal
+		if after.exception {
+			kind = Exception
+		}
+		b.leave(end, kind, &jump{target: after.target, exception: after.exception})
+	}
+	b.current = nil
+	if completes {
+		b.join([]exit{{block: end}})
+	}
+}
+
+func throws(block *Block) bool {
+	for _, edge := range block.Successors {
+		if edge.Kind == Exception {
+			return true
+		}
+	}
+	return false
+}
+
+// breakTarget returns the statement the break ends, nil when there is none.
+func (b *Builder) breakTarget(node *parser.Node) *frame {
+	label, labeled := node.Body.(*parser.Node)
+	for index := len(b.frames) - 1; index >= 0; index-- {
+		current := b.frames[index]
+		if labeled && current.label == label.Body.(*parser.StringLiteralValue).Value {
+			return current
+		}
+		if !labeled && current.breakable {
+			return current
+		}
+	}
+	return nil
+}
+
+// leave jumps from the block to the target of the jump, through the catch clause or
+// finally block of the first try statement on the way that takes it.
+func (b *Builder) leave(from *Block, kind string, to *jump) {
+	if from == nil {
+		return
+	}
+	for index := len(b.frames) - 1; index >= 0; index-- {
+		current := b.frames[index]
+		switch {
+		case current == to.target:
+			current.breaks = append(current.breaks, exit{block: from, kind: kind})
+			return
+		case to.exception && current.handler:
+			current.throws = append(current.throws, exit{block: from, kind: kind})
+			return
+		case current.finalizer:
+			current.finals = append(current.finals, exit{block: from, kind: kind})
+			current.after = append(current.after, *to)
+			return
+		}
+	}
+	b.connect(from, b.graph.Exit, kind)
+}
+
+// join continues in a new block the exits lead to, or in none when there are no exits.
+// A single exit from a block without successors continues in that block.
+func (b *Builder) join(exits []exit) {
+	if b.current != nil {
+		exits = append(exits, exit{block: b.current})
+	}
+	live := make([]exit, 0)
+	for _, completion := range exits {
+		if completion.block != nil {
+			live = append(live, completion)
+		}
+	}
+	if len(live) == 0 {
+		b.current = nil
+		return
+	}
+	if len(live) == 1 && live[0].kind == Normal && len(live[0].block.Successors) == 0 {
+		b.current = live[0].block
+		return
+	}
+	b.current = b.newBlock()
+	for _, completion := range live {
+		b.connect(completion.block, b.current, completion.kind)
+	}
+}
+
+func (b *Builder) push(target *frame) *frame {
+	b.frames = append(b.frames, target)
+	return target
+}
+
+func (b *Builder) pop() {
+	b.frames = b.frames[:len(b.frames)-1]
+}
+
+// add appends the node to the current block, after a jump the node starts a new block
+// no edge leads to.
+func (b *Builder) add(node *parser.Node) {
+	if b.current == nil {
+		b.current = b.newBlock()
+	}
+	b.current.Nodes = append(b.current.Nodes, node)
+}
+
+func (b *Builder) newBlock() *Block {
+	block := &Block{Index: len(b.graph.Blocks), Nodes: make([]*parser.Node, 0)}
+	b.graph.Blocks = append(b.graph.Blocks, block)
+	return block
+}
+
+// connect adds the edge unless it exists, there is nothing to connect from after a jump.
+func (b *Builder) connect(from *Block, to *Block, kind string) {
+	if from == nil {
+		return
+	}
+	for _, edge := range from.Successors {
+		if edge.To == to && edge.Kind == kind {
+			return
+		}
+	}
+	edge := &Edge{From: from, To: to, Kind: kind}
+	from.Successors = append(from.Successors, edge)
+	to.Predecessors = append(to.Predecessors, edge)
+}
+
+// Reachable reports for every block a path from Entry leads to.
+func (g *Graph) Reachable() map[*Block]bool {
+	reachable := map[*Block]bool{}
+	var visit func(block *Block)
+	visit = func(block *Block) {
+		if reachable[block] {
+			return
+		}
+		reachable[block] = true
+		for _, edge := range block.Successors {
+			visit(edge.To)
+		}
+	}
+	visit(g.Entry)
+	return reachable
+}
+
+// Unreachable returns the blocks with nodes no path from Entry leads to, in the order
+// they were created.
+func (g *Graph) Unreachable() []*Block {
+	reachable := g.Reachable()
+	blocks := make([]*Block, 0)
+	for _, block := range g.Blocks {
+		if !reachable[block] && len(block.Nodes) > 0 {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// Complexity is the cyclomatic complexity of the graph, one plus a decision for every
+// reachable test. Expressions that branch, like ?: and &&, do not count.
+func (g *Graph) Complexity() int {
+	complexity := 1
+	for block := range g.Reachable() {
+		for _, edge := range block.Successors {
+			if edge.Kind == True {
+				complexity++
+			}
+		}
+	}
+	return complexity
+}
+
+// DOT renders the graph in the Graphviz DOT language, every block lists its nodes as
+// source and exception edges are dashed.
+func DOT(g *Graph) (string, error) {
+	builder := &strings.Builder{}
+	builder.WriteString("digraph cfg {\n\tnode [shape=box];\n")
+	for _, block := range g.Blocks {
+		lines := []string{fmt.Sprintf("B%d", block.Index)}
+		switch block {
+		case g.Entry:
+			lines[0] += " (entry)"
+		case g.Exit:
+			lines[0] += " (exit)"
+		}
+		for _, node := range block.Nodes {
+			text, err := source(node)
+			if err != nil {
+				return "", err
+			}
+			lines = append(lines, strings.Split(text, "\n")...)
+		}
+		for index, line := range lines {
+			lines[index] = strings.ReplaceAll(strings.ReplaceAll(line, `\`, `\\`), `"`, `\"`)
+		}
+		builder.WriteString(fmt.Sprintf("\tB%d [label=\"%s\\l\"];\n", block.Index, strings.Join(lines, `\l`)))
+	}
+	for _, block := range g.Blocks {
+		for _, edge := range block.Successors {
+			attributes := ""
+			switch edge.Kind {
+			case True, False:
+				attributes = fmt.Sprintf(" [label=\"%s\"]", edge.Kind)
+			case Exception:
+				attributes = " [style=dashed]"
+			}
+			builder.WriteString(fmt.Sprintf("\tB%d -> B%d%s;\n", edge.From.Index, edge.To.Index, attributes))
+		}
+	}
+	builder.WriteString("}\n")
+	return builder.String(), nil
+}
+
+// source prints the node without its comments, an expression without the semicolon.
+func source(node *parser.Node) (string, error) {
+	statement := *node
+	statement.Comments = nil
+	expression := !isStatement(node)
+	if expression {
+		statement = parser.Node{NodeType: parser.ExpressionStatement, Body: node}
+	}
+	text, err := printer.New(printer.Props{}).Run(&parser.Program{
+		NodeType: parser.ProgramEnum,
+		Body:     []*parser.Node{&statement},
+	})
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSuffix(text, "\n")
+	if expression {
+		text = strings.TrimSuffix(text, ";")
+	}
+	return text, nil
+}
+
+func isStatement(node *parser.Node) bool {
+	return strings.HasSuffix(node.NodeType, "Statement") || strings.HasSuffix(node.NodeType, "Declaration")
+}
@@ -0,0 +1,167 @@
+package cfg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	text                string
+	expectedEdges       []string
+	expectedUnreachable []int
+	expectedComplexity  int
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given statements without branches": {
+			text:                `let x = 1; x = 2;`,
+			expectedEdges:       []string{"B0 -> B1"},
+			expectedUnreachable: []int{},
+			expectedComplexity:  1,
+		},
+		"given if without else": {
+			text:                `if (x) { x = 1; } x;`,
+			expectedEdges:       []string{"B0 -> B2 true", "B0 -> B3 false", "B2 -> B3", "B3 -> B1"},
+			expectedUnreachable: []int{},
+			expectedComplexity:  2,
+		},
+		"given if with returning else": {
+			text:                `if (x) x = 1; else return; x;`,
+			expectedEdges:       []string{"B0 -> B2 true", "B0 -> B3 false", "B2 -> B1", "B3 -> B1"},
+			expectedUnreachable: []int{},
+			expectedComplexity:  2,
+		},
+		"given switch falling through cases": {
+			text: `switch (x) { case 1: a; break; case 2: b; default: c; } d;`,
+			expectedEdges: []string{
+				"B0 -> B2 true", "B0 -> B3 false", "B2 -> B6", "B3 -> B4 true", "B3 -> B5 false",
+				"B4 -> B5", "B5 -> B6", "B6 -> B1",
+			},
+			expectedUnreachable: []int{},
+			expectedComplexity:  3,
+		},
+		"given switch without default": {
+			text:                `switch (x) { case 1: return; } d;`,
+			expectedEdges:       []string{"B0 -> B2 true", "B0 -> B3 false", "B2 -> B1", "B3 -> B1"},
+			expectedUnreachable: []int{},
+			expectedComplexity:  2,
+		},
+		"given labeled break": {
+			text:                `outer: { if (x) { break outer; } a; } b;`,
+			expectedEdges:       []string{"B0 -> B2 true", "B0 -> B3 false", "B2 -> B4", "B3 -> B4", "B4 -> B1"},
+			expectedUnreachable: []int{},
+			expectedComplexity:  2,
+		},
+		"given statements after return": {
+			text:                `a; return; b; if (x) c;`,
+			expectedEdges:       []string{"B0 -> B1", "B2 -> B3 true", "B2 -> B4 false", "B3 -> B4", "B4 -> B1"},
+			expectedUnreachable: []int{2, 3},
+			expectedComplexity:  1,
+		},
+		"given try with catch": {
+			text:                `a; try { b; } catch (e) { c; } d;`,
+			expectedEdges:       []string{"B0 -> B2", "B2 -> B3 exception", "B2 -> B4", "B3 -> B4", "B4 -> B1"},
+			expectedUnreachable: []int{},
+			expectedComplexity:  1,
+		},
+		"given return through finally": {
+			text: `try { return; } finally { a; } b;`,
+			expectedEdges: []string{
+				"B0 -> B2", "B2 -> B3", "B2 -> B3 exception", "B3 -> B1", "B3 -> B1 exception", "B4 -> B1",
+			},
+			expectedUnreachable: []int{4},
+			expectedComplexity:  1,
+		},
+		"given throw caught by enclosing try": {
+			text: `try { try { throw 1; } finally { a; } b; } catch { c; }`,
+			expectedEdges: []string{
+				"B0 -> B2", "B2 -> B3", "B3 -> B4 exception", "B4 -> B6 exception", "B5 -> B6 exception",
+				"B5 -> B7", "B6 -> B7", "B7 -> B1",
+			},
+			expectedUnreachable: []int{5},
+			expectedComplexity:  1,
+		},
+		"given empty try block": {
+			text:                `try {} catch (e) { a; }`,
+			expectedEdges:       []string{"B0 -> B2", "B2 -> B4", "B3 -> B4", "B4 -> B1"},
+			expectedUnreachable: []int{3},
+			expectedComplexity:  1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			program, err := parser.New(parser.Props{Text: tc.text}).Run()
+			assert.NoError(t, err)
+			graph := New(Props{}).Run(program)
+			assert.Equal(t, tc.expectedEdges, edges(graph))
+			assert.Equal(t, tc.expectedUnreachable, indexes(graph.Unreachable()))
+			assert.Equal(t, tc.expectedComplexity, graph.Complexity())
+		})
+	}
+}
+
+func TestFunction(t *testing.T) {
+	t.Run("given function with expression body, return single block", func(t *testing.T) {
+		program, err := parser.New(parser.Props{Text: `x => x * 2;`}).Run()
+		assert.NoError(t, err)
+		function := program.Body[0].Body.(*parser.Node).Body.(*parser.FunctionValue)
+		graph := New(Props{}).Function(function)
+		assert.Equal(t, []string{"B0 -> B1"}, edges(graph))
+		assert.Equal(t, []*parser.Node{function.Body}, graph.Entry.Nodes)
+	})
+	t.Run("given function with block body, return graph of the body", func(t *testing.T) {
+		program, err := parser.New(parser.Props{Text: `function (x) { if (x) return 1; return 2; };`}).Run()
+		assert.NoError(t, err)
+		function := program.Body[0].Body.(*parser.Node).Body.(*parser.FunctionValue)
+		graph := New(Props{}).Function(function)
+		assert.Equal(t, []string{"B0 -> B2 true", "B0 -> B3 false", "B2 -> B1", "B3 -> B1"}, edges(graph))
+	})
+}
+
+func TestDOT(t *testing.T) {
+	t.Run("given graph, render nodes as source and label branches", func(t *testing.T) {
+		program, err := parser.New(parser.Props{Text: `let s = "a\\b"; if (s) { throw "no"; }`}).Run()
+		assert.NoError(t, err)
+		output, err := DOT(New(Props{}).Run(program))
+		assert.NoError(t, err)
+		assert.Equal(t, `digraph cfg {
+	node [shape=box];
+	B0 [label="B0 (entry)\llet s = \"a\\\\b\";\ls\l"];
+	B1 [label="B1 (exit)\l"];
+	B2 [label="B2\lthrow \"no\";\l"];
+	B3 [label="B3\l"];
+	B0 -> B2 [label="true"];
+	B0 -> B3 [label="false"];
+	B2 -> B1 [style=dashed];
+	B3 -> B1;
+}
+`, output)
+	})
+}
+
+func edges(graph *Graph) []string {
+	lines := make([]string, 0)
+	for _, block := range graph.Blocks {
+		for _, edge := range block.Successors {
+			line := fmt.Sprintf("B%d -> B%d", edge.From.Index, edge.To.Index)
+			if edge.Kind != Normal {
+				line += " " + edge.Kind
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func indexes(blocks []*Block) []int {
+	result := make([]int, 0)
+	for _, block := range blocks {
+		result = append(result, block.Index)
+	}
+	return result
+}
@@ -0,0 +1,477 @@
+package dataflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/cfg"
+	"github.com/dlanell/go-rdparser/parser"
+)
+
+// Analyzer
+// function is the function being analyzed, nil for the top level, and reporting is set
+// for the final pass over the graph once the data-flow sets are stable.
+type Analyzer struct {
+	resolver  *resolver
+	function  *parser.FunctionValue
+	reporting bool
+	warnings  []*Warning
+}
+
+type Props struct{}
+
+// Warning is a likely bug in a valid program, Loc is only set when the program was
+// parsed with Locations.
+type Warning struct {
+	Message string
+	Loc     *parser.Location
+}
+
+// set holds the variables definitely assigned, or the variables whose value may still
+// be read.
+type set map[*variable]bool
+
+func New(props Props) *Analyzer {
+	return &Analyzer{}
+}
+
+// Run checks the top level and every function of the program on its control-flow
+// graph and returns the warnings in source order. Variables a nested function refers
+// to are not checked, the function may run at any time.
+func (a *Analyzer) Run(program *parser.Program) []*Warning {
+	a.warnings = make([]*Warning, 0)
+	a.resolver = resolve(program)
+
+	builder := cfg.New(cfg.Props{})
+	a.analyze(nil, builder.Run(program))
+	for _, function := range a.resolver.functions {
+		a.analyze(function, builder.Function(function))
+	}
+
+	sort.SliceStable(a.warnings, func(i, j int) bool {
+		return a.warnings[i].Loc != nil && a.warnings[j].Loc != nil && a.warnings[i].Loc.Start < a.warnings[j].Loc.Start
+	})
+	return a.warnings
+}
+
+func (a *Analyzer) analyze(function *parser.FunctionValue, graph *cfg.Graph) {
+	a.function = function
+	reachable := graph.Reachable()
+	a.assignment(graph, reachable)
+	a.liveness(graph, reachable)
+}
+
+// assignment reports the reads of variables not assigned on every path leading to them.
+// Exception edges carry the variables assigned at the start of the block they leave,
+// as any of its nodes may throw.
+func (a *Analyzer) assignment(graph *cfg.Graph, reachable map[*cfg.Block]bool) {
+	universe, entry := set{}, set{}
+	for _, v := range a.resolver.variables {
+		if a.tracked(v) {
+			universe[v] = true
+			if v.param {
+				entry[v] = true
+			}
+		}
+	}
+
+	in, out := map[*cfg.Block]set{}, map[*cfg.Block]set{}
+	for changed := true; changed; {
+		changed = false
+		for _, block := range graph.Blocks {
+			if !reachable[block] {
+				continue
+			}
+			state := entry.clone()
+			if block != graph.Entry {
+				state = universe.clone()
+				for _, edge := range block.Predecessors {
+					from := out[edge.From]
+					if edge.Kind == cfg.Exception {
+						from = in[edge.From]
+					}
+					if from != nil {
+						state.intersect(from)
+					}
+				}
+			}
+			in[block] = state
+
+			after := state.clone()
+			for _, node := range block.Nodes {
+				a.assign(node, after)
+			}
+			if !after.equal(out[block]) {
+				out[block] = after
+				changed = true
+			}
+		}
+	}
+
+	a.reporting = true
+	for _, block := range graph.Blocks {
+		if reachable[block] {
+			state := in[block].clone()
+			for _, node := range block.Nodes {
+				a.assign(node, state)
+			}
+		}
+	}
+	a.reporting = false
+}
+
+// liveness reports the values written to variables that no path reads before the
+// next write. Exception edges keep the variables their target reads alive throughout
+// the block they leave.
+func (a *Analyzer) liveness(graph *cfg.Graph, reachable map[*cfg.Block]bool) {
+	in := map[*cfg.Block]set{}
+	walk := func(block *cfg.Block) set {
+		state, exceptional := set{}, set{}
+		for _, edge := range block.Successors {
+			if edge.Kind == cfg.Exception {
+				exceptional.union(in[edge.To])
+			} else {
+				state.union(in[edge.To])
+			}
+		}
+		state.union(exceptional)
+		for index := len(block.Nodes) - 1; index >= 0; index-- {
+			a.live(block.Nodes[index], state)
+			state.union(exceptional)
+		}
+		return state
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for index := len(graph.Blocks) - 1; index >= 0; index-- {
+			block := graph.Blocks[index]
+			if !reachable[block] {
+				continue
+			}
+			if state := walk(block); !state.equal(in[block]) {
+				in[block] = state
+				changed = true
+			}
+		}
+	}
+
+	a.reporting = true
+	for _, block := range graph.Blocks {
+		if reachable[block] {
+			walk(block)
+		}
+	}
+	a.reporting = false
+}
+
+// assign adds the variables the node assigns to the set, in evaluation order, and
+// checks its reads against it.
+func (a *Analyzer) assign(node *parser.Node, state set) {
+	switch node.NodeType {
+	case parser.ExpressionStatement:
+		a.assignExpression(node.Body.(*parser.Node), state)
+	case parser.VariableStatement:
+		for _, declaration := range parser.Children(node) {
+			value := declaration.Body.(*parser.VariableDeclarationValue)
+			if value.Init != nil {
+				a.assignExpression(value.Init, state)
+				a.assignPattern(value.Id, state)
+			}
+		}
+	case parser.ExportDeclaration:
+		a.assign(node.Body.(*parser.Node), state)
+	case parser.ReturnStatement, parser.ThrowStatement:
+		if argument, ok := node.Body.(*parser.Node); ok {
+			a.assignExpression(argument, state)
+		}
+	case parser.ImportDeclaration, parser.EmptyStatement, parser.BreakStatement:
+	default:
+		if a.resolver.bindings[node] {
+			a.define(node, state)
+			return
+		}
+		a.assignExpression(node, state)
+	}
+}
+
+// assignExpression follows the expression, assignments in an operand that may not be
+// evaluated are left out of the set.
+func (a *Analyzer) assignExpression(node *parser.Node, state set) {
+	switch node.NodeType {
+	case parser.Identifier:
+		v := a.resolver.variables[node]
+		if a.reporting && a.tracked(v) && !state[v] {
+			a.report(node, fmt.Sprintf("%s is used before being assigned", v.name))
+		}
+	case parser.FunctionExpression, parser.ArrowFunctionExpression:
+	case parser.BinaryExpression:
+		value := node.Body.(*parser.BinaryExpressionNode)
+		a.assignExpression(value.Left.(*parser.Node), state)
+		if isShortCircuit(value.Operator) {
+			a.assignExpression(value.Right.(*parser.Node), state.clone())
+		} else {
+			a.assignExpression(value.Right.(*parser.Node), state)
+		}
+	case parser.AssignmentExpression:
+		value := node.Body.(*parser.BinaryExpressionNode)
+		left, right := value.Left.(*parser.Node), value.Right.(*parser.Node)
+		switch {
+		case value.Operator == "=":
+			a.assignExpression(right, state)
+			a.assignPattern(left, state)
+		case isShortCircuit(strings.TrimSuffix(value.Operator, "=")):
+			a.assignExpression(left, state)
+			a.assignExpression(right, state.clone())
+		default:
+			a.assignExpression(left, state)
+			a.assignExpression(right, state)
+			a.define(left, state)
+		}
+	case parser.UpdateExpression:
+		argument := node.Body.(*parser.UpdateExpressionValue).Argument
+		a.assignExpression(argument, state)
+		a.define(argument, state)
+	case parser.ConditionalExpression:
+		value := node.Body.(*parser.ConditionalExpressionValue)
+		a.assignExpression(value.Test, state)
+		consequent := state.clone()
+		a.assignExpression(value.Consequent, consequent)
+		a.assignExpression(value.Alternate, state)
+		state.intersect(consequent)
+	case parser.ChainExpression:
+		a.assignExpression(node.Body.(*parser.Node), state.clone())
+	case parser.MemberExpression:
+		value := node.Body.(*parser.MemberExpressionValue)
+		a.assignExpression(value.Object, state)
+		if value.Computed {
+			a.assignExpression(value.Property, state)
+		}
+	case parser.Property:
+		a.assignExpression(node.Body.(*parser.PropertyValue).Value, state)
+	default:
+		for _, child := range parser.Children(node) {
+			a.assignExpression(child, state)
+		}
+	}
+}
+
+// assignPattern assigns the identifiers of an assignment target after its defaults,
+// which may not be evaluated.
+func (a *Analyzer) assignPattern(target *parser.Node, state set) {
+	switch target.NodeType {
+	case parser.Identifier:
+		a.define(target, state)
+	case parser.MemberExpression:
+		a.assignExpression(target, state)
+	case parser.AssignmentPattern:
+		value := target.Body.(*parser.AssignmentPatternValue)
+		a.assignExpression(value.Right, state.clone())
+		a.assignPattern(value.Left, state)
+	case parser.Property:
+		a.assignPattern(target.Body.(*parser.PropertyValue).Value, state)
+	default:
+		for _, child := range parser.Children(target) {
+			a.assignPattern(child, state)
+		}
+	}
+}
+
+func (a *Analyzer) define(target *parser.Node, state set) {
+	if v := a.resolver.variables[target]; target.NodeType == parser.Identifier && a.tracked(v) {
+		state[v] = true
+	}
+}
+
+// live walks the node backwards, a read makes the variable live and a write that always
+// happens ends its life.
+func (a *Analyzer) live(node *parser.Node, state set) {
+	switch node.NodeType {
+	case parser.ExpressionStatement:
+		a.liveExpression(node.Body.(*parser.Node), state)
+	case parser.VariableStatement:
+		declarations := parser.Children(node)
+		for index := len(declarations) - 1; index >= 0; index-- {
+			value := declarations[index].Body.(*parser.VariableDeclarationValue)
+			if value.Init != nil {
+				a.livePattern(value.Id, state)
+				a.liveExpression(value.Init, state)
+			}
+		}
+	case parser.ExportDeclaration:
+		a.live(node.Body.(*parser.Node), state)
+	case parser.ReturnStatement, parser.ThrowStatement:
+		if argument, ok := node.Body.(*parser.Node); ok {
+			a.liveExpression(argument, state)
+		}
+	case parser.ImportDeclaration, parser.EmptyStatement, parser.BreakStatement:
+	default:
+		// a catch clause parameter need not be used
+		if a.resolver.bindings[node] {
+			delete(state, a.resolver.variables[node])
+			return
+		}
+		a.liveExpression(node, state)
+	}
+}
+
+func (a *Analyzer) liveExpression(node *parser.Node, state set) {
+	switch node.NodeType {
+	case parser.Identifier:
+		if v := a.resolver.variables[node]; a.trackedLive(v) {
+			state[v] = true
+		}
+	case parser.FunctionExpression, parser.ArrowFunctionExpression:
+	case parser.BinaryExpression:
+		value := node.Body.(*parser.BinaryExpressionNode)
+		if isShortCircuit(value.Operator) {
+			right := state.clone()
+			a.liveExpression(value.Right.(*parser.Node), right)
+			state.union(right)
+		} else {
+			a.liveExpression(value.Right.(*parser.Node), state)
+		}
+		a.liveExpression(value.Left.(*parser.Node), state)
+	case parser.AssignmentExpression:
+		value := node.Body.(*parser.BinaryExpressionNode)
+		left, right := value.Left.(*parser.Node), value.Right.(*parser.Node)
+		switch {
+		case value.Operator == "=":
+			a.livePattern(left, state)
+			a.liveExpression(right, state)
+		case isShortCircuit(strings.TrimSuffix(value.Operator, "=")):
+			a.kill(left, state, false)
+			conditional := state.clone()
+			a.liveExpression(right, conditional)
+			state.union(conditional)
+			a.liveExpression(left, state)
+		default:
+			a.kill(left, state, true)
+			a.liveExpression(right, state)
+			a.liveExpression(left, state)
+		}
+	case parser.UpdateExpression:
+		argument := node.Body.(*parser.UpdateExpressionValue).Argument
+		a.kill(argument, state, true)
+		a.liveExpression(argument, state)
+	case parser.ConditionalExpression:
+		value := node.Body.(*parser.ConditionalExpressionValue)
+		consequent := state.clone()
+		a.liveExpression(value.Consequent, consequent)
+		a.liveExpression(value.Alternate, state)
+		state.union(consequent)
+		a.liveExpression(value.Test, state)
+	case parser.ChainExpression:
+		conditional := state.clone()
+		a.liveExpression(node.Body.(*parser.Node), conditional)
+		state.union(conditional)
+	case parser.MemberExpression:
+		value := node.Body.(*parser.MemberExpressionValue)
+		if value.Computed {
+			a.liveExpression(value.Property, state)
+		}
+		a.liveExpression(value.Object, state)
+	case parser.Property:
+		a.liveExpression(node.Body.(*parser.PropertyValue).Value, state)
+	default:
+		children := parser.Children(node)
+		for index := len(children) - 1; index >= 0; index-- {
+			a.liveExpression(children[index], state)
+		}
+	}
+}
+
+func (a *Analyzer) livePattern(target *parser.Node, state set) {
+	switch target.NodeType {
+	case parser.Identifier:
+		a.kill(target, state, true)
+	case parser.MemberExpression:
+		a.liveExpression(target, state)
+	case parser.AssignmentPattern:
+		value := target.Body.(*parser.AssignmentPatternValue)
+		a.livePattern(value.Left, state)
+		conditional := state.clone()
+		a.liveExpression(value.Right, conditional)
+		state.union(conditional)
+	case parser.Property:
+		a.livePattern(target.Body.(*parser.PropertyValue).Value, state)
+	default:
+		children := parser.Children(target)
+		for index := len(children) - 1; index >= 0; index-- {
+			a.livePattern(children[index], state)
+		}
+	}
+}
+
+// kill reports a write to a variable that is not live after it, certain writes end
+// the life of the variable before them.
+func (a *Analyzer) kill(target *parser.Node, state set, certain bool) {
+	v := a.resolver.variables[target]
+	if target.NodeType != parser.Identifier || !a.trackedLive(v) {
+		return
+	}
+	if a.reporting && !state[v] {
+		a.report(target, fmt.Sprintf("value assigned to %s is never read", v.name))
+	}
+	if certain {
+		delete(state, v)
+	}
+}
+
+// tracked reports whether the variable belongs to the function being analyzed and
+// only it refers to the variable.
+func (a *Analyzer) tracked(v *variable) bool {
+	return v != nil && v.owner == a.function && !v.ignored && !v.captured
+}
+
+// trackedLive leaves out exported variables as well, importers may read them.
+func (a *Analyzer) trackedLive(v *variable) bool {
+	return a.tracked(v) && !v.exported
+}
+
+func (a *Analyzer) report(node *parser.Node, message string) {
+	a.warnings = append(a.warnings, &Warning{Message: message, Loc: node.Loc})
+}
+
+func isShortCircuit(operator string) bool {
+	switch operator {
+	case "&&", "||", "??", "AND", "OR":
+		return true
+	}
+	return false
+}
+
+func (s set) clone() set {
+	c := set{}
+	for v := range s {
+		c[v] = true
+	}
+	return c
+}
+
+func (s set) union(other set) {
+	for v := range other {
+		s[v] = true
+	}
+}
+
+func (s set) intersect(other set) {
+	for v := range s {
+		if !other[v] {
+			delete(s, v)
+		}
+	}
+}
+
+func (s set) equal(other set) bool {
+	if other == nil || len(s) != len(other) {
+		return false
+	}
+	for v := range s {
+		if !other[v] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package dataflow
+
+import (
+	"testing"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	text             string
+	expectedWarnings []*Warning
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given variable assigned in one branch of if": {
+			text: `let x; if (c) { x = 1; } f(x);`,
+			expectedWarnings: []*Warning{
+				{Message: "x is used before being assigned", Loc: &parser.Location{Start: 27, End: 28}},
+			},
+		},
+		"given variable assigned in both branches of if": {
+			text:             `let x; if (c) { x = 1; } else { x = 2; } f(x);`,
+			expectedWarnings: []*Warning{},
+		},
+		"given variable assigned in returning branch": {
+			text:             `let f = c => { let x; if (c) { return 0; } else { x = 1; } return x; }; f(1);`,
+			expectedWarnings: []*Warning{},
+		},
+		"given variable read before let and var declarations": {
+			text: `f(x); let x = 1; f(x); f(y); var y = 2; f(y);`,
+			expectedWarnings: []*Warning{
+				{Message: "x is used before being assigned", Loc: &parser.Location{Start: 2, End: 3}},
+				{Message: "y is used before being assigned", Loc: &parser.Location{Start: 25, End: 26}},
+			},
+		},
+		"given assignments in conditional and logical expressions": {
+			text: `let x; c ? (x = 1) : (x = 2); f(x); let y; c && (y = 1); f(y);`,
+			expectedWarnings: []*Warning{
+				{Message: "y is used before being assigned", Loc: &parser.Location{Start: 59, End: 60}},
+			},
+		},
+		"given assignments in switch cases": {
+			text: `let x; switch (c) { case 1: x = 1; break; default: x = 2; } f(x); let y; switch (c) { case 1: y = 1; } f(y);`,
+			expectedWarnings: []*Warning{
+				{Message: "y is used before being assigned", Loc: &parser.Location{Start: 105, End: 106}},
+			},
+		},
+		"given assignments in try and catch": {
+			text: `let x; try { x = g(); } catch (e) { x = 0; } f(x); let y; try { y = g(); } catch {} f(y);`,
+			expectedWarnings: []*Warning{
+				{Message: "y is used before being assigned", Loc: &parser.Location{Start: 86, End: 87}},
+			},
+		},
+		"given values overwritten or never read": {
+			text: `let x = 1; x = 2; f(x); x = 3; let i = 0; i++; let s = ""; s += "a"; f(s);`,
+			expectedWarnings: []*Warning{
+				{Message: "value assigned to x is never read", Loc: &parser.Location{Start: 4, End: 5}},
+				{Message: "value assigned to x is never read", Loc: &parser.Location{Start: 24, End: 25}},
+				{Message: "value assigned to i is never read", Loc: &parser.Location{Start: 42, End: 43}},
+			},
+		},
+		"given compound assignment to unassigned variable": {
+			text: `let n; n += 1;`,
+			expectedWarnings: []*Warning{
+				{Message: "n is used before being assigned", Loc: &parser.Location{Start: 7, End: 8}},
+				{Message: "value assigned to n is never read", Loc: &parser.Location{Start: 7, End: 8}},
+			},
+		},
+		"given destructuring with defaults": {
+			text: `let { a, b = a } = o; let [c, d] = o; f(b, c);`,
+			expectedWarnings: []*Warning{
+				{Message: "value assigned to d is never read", Loc: &parser.Location{Start: 30, End: 31}},
+			},
+		},
+		"given variables of nested functions": {
+			text: `let x; let init = () => { x = 1; }; init(); f(x); let g = (a, b) => { let c = a; return b; }; g(1, 2);`,
+			expectedWarnings: []*Warning{
+				{Message: "value assigned to c is never read", Loc: &parser.Location{Start: 74, End: 75}},
+			},
+		},
+		"given exported variable and catch parameter": {
+			text:             `export let x = 1; try { f(); } catch (e) {}`,
+			expectedWarnings: []*Warning{},
+		},
+		"given read through optional chain": {
+			text: `let o = g(); let k; o?.[k = 1]; f(k ?? 0);`,
+			expectedWarnings: []*Warning{
+				{Message: "k is used before being assigned", Loc: &parser.Location{Start: 34, End: 35}},
+			},
+		},
+		"given unreachable code": {
+			text:             `let f = () => { let x; return; f(x); x = 1; };`,
+			expectedWarnings: []*Warning{},
+		},
+		"given value read in finally after return": {
+			text:             `let f = () => { let x = 0; try { x = 1; return g(); } finally { h(x); } }; f();`,
+			expectedWarnings: []*Warning{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			program, err := parser.New(parser.Props{Text: tc.text, Locations: true}).Run()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedWarnings, New(Props{}).Run(program))
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package dataflow
+
+import (
+	"github.com/dlanell/go-rdparser/parser"
+)
+
+// variable is a declaration the analysis may track, owner is the function declaring it
+// or nil for the top level. ignored marks names the analysis never tracks, imports and
+// function expression names, captured those a nested function refers to.
+type variable struct {
+	name     string
+	owner    *parser.FunctionValue
+	param    bool
+	ignored  bool
+	captured bool
+	exported bool
+}
+
+// resolver links every identifier to the variable in scope, blocks open a new scope
+// for let and const, functions one for their parameters and var declarations belong
+// to the scope of their function.
+type resolver struct {
+	scope     *scope
+	function  *parser.FunctionValue
+	variables map[*parser.Node]*variable
+	bindings  map[*parser.Node]bool
+	functions []*parser.FunctionValue
+}
+
+type scope struct {
+	names  map[string]*variable
+	parent *scope
+}
+
+func resolve(program *parser.Program) *resolver {
+	r := &resolver{
+		scope:     &scope{names: map[string]*variable{}},
+		variables: map[*parser.Node]*variable{},
+		bindings:  map[*parser.Node]bool{},
+		functions: make([]*parser.FunctionValue, 0),
+	}
+	r.declareVars(program.Body)
+	r.statementList(program.Body)
+	return r
+}
+
+func (r *resolver) statementList(statements []*parser.Node) {
+	r.declareLexical(statements)
+	for _, statement := range statements {
+		r.visit(statement)
+	}
+}
+
+func (r *resolver) visit(node *parser.Node) {
+	if node == nil {
+		return
+	}
+	switch node.NodeType {
+	case parser.BlockStatement:
+		r.scope = &scope{names: map[string]*variable{}, parent: r.scope}
+		r.statementList(node.Body.([]*parser.Node))
+		r.scope = r.scope.parent
+	case parser.SwitchStatement:
+		value := node.Body.(*parser.SwitchStatementValue)
+		r.visit(value.Discriminant)
+		r.scope = &scope{names: map[string]*variable{}, parent: r.scope}
+		for _, clause := range value.Cases {
+			r.declareLexical(clause.Body.(*parser.SwitchCaseValue).Consequent)
+		}
+		for _, clause := range value.Cases {
+			for _, child := range parser.Children(clause) {
+				r.visit(child)
+			}
+		}
+		r.scope = r.scope.parent
+	case parser.CatchClause:
+		value := node.Body.(*parser.CatchClauseValue)
+		r.scope = &scope{names: map[string]*variable{}, parent: r.scope}
+		if value.Param != nil {
+			r.bind(value.Param, &variable{})
+		}
+		r.visit(value.Body)
+		r.scope = r.scope.parent
+	case parser.FunctionExpression, parser.ArrowFunctionExpression:
+		r.visitFunction(node.Body.(*parser.FunctionValue))
+	case parser.ExportDeclaration:
+		r.visit(node.Body.(*parser.Node))
+		for _, declaration := range parser.Children(node.Body.(*parser.Node)) {
+			for _, identifier := range parser.BindingIdentifiers(declaration.Body.(*parser.VariableDeclarationValue).Id) {
+				r.variables[identifier].exported = true
+			}
+		}
+	case parser.ImportDeclaration:
+	case parser.LabeledStatement:
+		r.visit(node.Body.(*parser.LabeledStatementValue).Body)
+	case parser.BreakStatement:
+	case parser.MemberExpression:
+		value := node.Body.(*parser.MemberExpressionValue)
+		r.visit(value.Object)
+		if value.Computed {
+			r.visit(value.Property)
+		}
+	case parser.Property:
+		r.visit(node.Body.(*parser.PropertyValue).Value)
+	case parser.Identifier:
+		if found := r.scope.lookup(node.Body.(*parser.StringLiteralValue).Value); found != nil {
+			r.variables[node] = found
+			if found.owner != r.function {
+				found.captured = true
+			}
+		}
+	default:
+		for _, child := range parser.Children(node) {
+			r.visit(child)
+		}
+	}
+}
+
+// visitFunction declares the name of a function expression and the parameters in a
+// new function scope, parameter defaults are visited in it.
+func (r *resolver) visitFunction(node *parser.FunctionValue) {
+	r.functions = append(r.functions, node)
+	outer := r.function
+	r.function = node
+	r.scope = &scope{names: map[string]*variable{}, parent: r.scope}
+	if node.Id != nil {
+		r.bind(node.Id, &variable{ignored: true})
+	}
+	for _, param := range node.Params {
+		for _, identifier := range parser.BindingIdentifiers(param) {
+			r.bind(identifier, &variable{param: true})
+		}
+		r.visitDefaults(param)
+	}
+
+	if node.Expression {
+		r.visit(node.Body)
+	} else {
+		statements := node.Body.Body.([]*parser.Node)
+		r.declareVars(statements)
+		r.statementList(statements)
+	}
+	r.scope = r.scope.parent
+	r.function = outer
+}
+
+// visitDefaults visits the default values of a binding target.
+func (r *resolver) visitDefaults(target *parser.Node) {
+	parser.Walk(target, func(node *parser.Node) bool {
+		switch node.NodeType {
+		case parser.AssignmentPattern:
+			value := node.Body.(*parser.AssignmentPatternValue)
+			r.visitDefaults(value.Left)
+			r.visit(value.Right)
+			return false
+		case parser.Property:
+			r.visitDefaults(node.Body.(*parser.PropertyValue).Value)
+			return false
+		}
+		return true
+	})
+}
+
+// declareLexical declares the let and const bindings and the imports of a statement
+// list.
+func (r *resolver) declareLexical(statements []*parser.Node) {
+	for _, statement := range statements {
+		if statement.NodeType == parser.ImportDeclaration {
+			for _, specifier := range statement.Body.(*parser.ImportDeclarationValue).Specifiers {
+				r.bind(specifier.Body.(*parser.ImportSpecifierValue).Local, &variable{ignored: true})
+			}
+			continue
+		}
+		if statement.NodeType == parser.ExportDeclaration {
+			statement = statement.Body.(*parser.Node)
+		}
+		if statement.NodeType == parser.VariableStatement && statement.Body.(*parser.VariableStatementValue).Kind != parser.KindVar {
+			r.declare(statement)
+		}
+	}
+}
+
+// declareVars declares every var of the statements in the function scope, leaving out
+// those of nested functions.
+func (r *resolver) declareVars(statements []*parser.Node) {
+	for _, statement := range statements {
+		parser.Walk(statement, func(node *parser.Node) bool {
+			if node.NodeType == parser.FunctionExpression || node.NodeType == parser.ArrowFunctionExpression {
+				return false
+			}
+			if node.NodeType == parser.VariableStatement && node.Body.(*parser.VariableStatementValue).Kind == parser.KindVar {
+				r.declare(node)
+			}
+			return true
+		})
+	}
+}
+
+// declare binds the identifiers of the declarations, a var declared twice or with the
+// name of a parameter is one variable.
+func (r *resolver) declare(statement *parser.Node) {
+	for _, declaration := range parser.Children(statement) {
+		for _, identifier := range parser.BindingIdentifiers(declaration.Body.(*parser.VariableDeclarationValue).Id) {
+			name := identifier.Body.(*parser.StringLiteralValue).Value
+			if existing, ok := r.scope.names[name]; ok {
+				r.variables[identifier] = existing
+				r.bindings[identifier] = true
+				continue
+			}
+			r.bind(identifier, &variable{})
+		}
+	}
+}
+
+func (r *resolver) bind(identifier *parser.Node, v *variable) {
+	v.name = identifier.Body.(*parser.StringLiteralValue).Value
+	v.owner = r.function
+	r.scope.names[v.name] = v
+	r.variables[identifier] = v
+	r.bindings[identifier] = true
+}
+
+func (s *scope) lookup(name string) *variable {
+	for current := s; current != nil; current = current.parent {
+		if found, ok := current.names[name]; ok {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1171 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/module"
+	"github.com/dlanell/go-rdparser/parser"
+)
+
+type Evaluator struct {
+	maxSteps        int
+	maxCallDepth    int
+	maxStringLength int
+	maxArrayLength  int
+	globals         *environment
+	exports         map[string]map[string]interface{}
+	ctx             context.Context
+	steps           int
+	depth           int
+}
+
+// Props limits the resources a single Run may use. A zero value disables the limit.
+type Props struct {
+	// MaxSteps is the number of nodes that may be evaluated.
+	MaxSteps int
+	// MaxCallDepth is how many script function calls may be in progress at once.
+	MaxCallDepth int
+	// MaxStringLength is the length in bytes of any string value produced.
+	MaxStringLength int
+	// MaxArrayLength is the number of elements of any list and of properties of any
+	// record produced.
+	MaxArrayLength int
+	// Globals are values the host provides to scripts, they can be shadowed but not
+	// assigned. Scripts call the HostFunction values among them.
+	Globals map[string]interface{}
+}
+
+var (
+	ErrStepLimitExceeded   = errors.New("step limit exceeded")
+	ErrCallDepthExceeded   = errors.New("maximum call depth exceeded")
+	ErrStringLimitExceeded = errors.New("maximum string length exceeded")
+	ErrArrayLimitExceeded  = errors.New("maximum array length exceeded")
+)
+
+// Exception is a value thrown by a script that no catch clause handled.
+type Exception struct {
+	Value interface{}
+}
+
+func (e *Exception) Error() string {
+	return "uncaught exception: " + toString(e.Value)
+}
+
+// HostFunction is a function the host provides to scripts, it calls back into the
+// script functions it is passed with Evaluator.Call.
+type HostFunction func(e *Evaluator, args []interface{}) (interface{}, error)
+
+// closure is a script function together with the environment it was created in.
+type closure struct {
+	node *parser.FunctionValue
+	env  *environment
+}
+
+// RegExp is the value of a regular expression literal, scripts match strings with its
+// test method.
+type RegExp struct {
+	Source string
+	Flags  string
+	regexp *regexp.Regexp
+}
+
+func (f HostFunction) String() string {
+	return "function"
+}
+
+func (r *RegExp) String() string {
+	return "/" + r.Source + "/" + r.Flags
+}
+
+// property reads the test method, source or flags of the regular expression, anything
+// else is null.
+func (r *RegExp) property(name string) interface{} {
+	switch name {
+	case "test":
+		return HostFunction(func(e *Evaluator, args []interface{}) (interface{}, error) {
+			var text interface{}
+			if len(args) > 0 {
+				text = args[0]
+			}
+			if s, ok := text.(string); ok {
+				return r.regexp.MatchString(s), nil
+			}
+			return r.regexp.MatchString(toString(text)), nil
+		})
+	case "source":
+		return r.Source
+	case "flags":
+		return r.Flags
+	}
+	return nil
+}
+
+func (c *closure) String() string {
+	return "function"
+}
+
+// returnValue unwinds evaluation to the enclosing function call, it surfaces as an
+// error only when there is none.
+type returnValue struct {
+	value interface{}
+}
+
+func (r *returnValue) Error() string {
+	return "illegal return statement"
+}
+
+// errBreak unwinds evaluation to the enclosing switch, it surfaces as an error
+// only when there is none.
+var errBreak = errors.New("illegal break statement")
+
+// breakLabel unwinds evaluation to the statement with the label, it surfaces as an
+// error only when there is none.
+type breakLabel struct {
+	label string
+}
+
+func (b *breakLabel) Error() string {
+	return fmt.Sprintf("undefined label: %s", b.label)
+}
+
+// errShortCircuit ends a ChainExpression at an optional link whose object is null.
+var errShortCircuit = errors.New("optional chain short circuit")
+
+// environment
+// function marks the scope var declarations belong to, constants holds the names
+// declared with const.
+type environment struct {
+	values    map[string]interface{}
+	constants map[string]bool
+	function  bool
+	parent    *environment
+}
+
+func New(props Props) *Evaluator {
+	host := newEnvironment(nil)
+	for name, value := range props.Globals {
+		host.declare(name, value, true)
+	}
+	return &Evaluator{
+		maxSteps:        props.MaxSteps,
+		maxCallDepth:    props.MaxCallDepth,
+		maxStringLength: props.MaxStringLength,
+		maxArrayLength:  props.MaxArrayLength,
+		globals:         newFunctionEnvironment(host),
+		exports:         map[string]map[string]interface{}{},
+	}
+}
+
+// Run evaluates every statement of the program and returns the value of the last one.
+// Variables declared at the top level persist across calls to Run, and evaluation
+// stops with the context's error once ctx is done.
+func (e *Evaluator) Run(ctx context.Context, program *parser.Program) (interface{}, error) {
+	e.ctx = ctx
+	e.steps = 0
+	e.depth = 0
+
+	hoist(program.Body, e.globals)
+	return e.statementList(program.Body, e.globals)
+}
+
+// RunModule evaluates the module and returns the value of its last statement. The
+// modules it imports are evaluated first, each of them once per Evaluator, and the
+// top-level variables of a module are only visible to the modules importing them.
+func (e *Evaluator) RunModule(ctx context.Context, m *module.Module) (interface{}, error) {
+	e.ctx = ctx
+	e.steps = 0
+	e.depth = 0
+
+	return e.module(m)
+}
+
+func (e *Evaluator) module(m *module.Module) (interface{}, error) {
+	env := newFunctionEnvironment(e.globals)
+	body := make([]*parser.Node, 0, len(m.Program.Body))
+	for _, statement := range m.Program.Body {
+		if statement.NodeType != parser.ImportDeclaration {
+			body = append(body, statement)
+			continue
+		}
+		value := statement.Body.(*parser.ImportDeclarationValue)
+		exports, err := e.moduleExports(m.Dependencies[value.Source.Body.(*parser.StringLiteralValue).Value])
+		if err != nil {
+			return nil, err
+		}
+		for _, specifier := range value.Specifiers {
+			names := specifier.Body.(*parser.ImportSpecifierValue)
+			imported := names.Imported.Body.(*parser.StringLiteralValue).Value
+			env.declare(names.Local.Body.(*parser.StringLiteralValue).Value, exports[imported], true)
+		}
+	}
+
+	hoist(body, env)
+	result, err := e.statementList(body, env)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := map[string]interface{}{}
+	for _, name := range m.Exports {
+		exports[name] = env.values[name]
+	}
+	e.exports[m.ID] = exports
+	return result, nil
+}
+
+// moduleExports returns the exported values of the module, evaluating it when it has
+// not been yet.
+func (e *Evaluator) moduleExports(m *module.Module) (map[string]interface{}, error) {
+	if exports, ok := e.exports[m.ID]; ok {
+		return exports, nil
+	}
+	if _, err := e.module(m); err != nil {
+		return nil, err
+	}
+	return e.exports[m.ID], nil
+}
+
+func (e *Evaluator) statementList(statements []*parser.Node, env *environment) (interface{}, error) {
+	var result interface{}
+	for _, statement := range statements {
+		value, err := e.evaluate(statement, env)
+		if err != nil {
+			return nil, err
+		}
+		result = value
+	}
+	return result, nil
+}
+
+func (e *Evaluator) evaluate(node *parser.Node, env *environment) (interface{}, error) {
+	if err := e.enter(); err != nil {
+		return nil, err
+	}
+
+	switch node.NodeType {
+	case parser.EmptyStatement:
+		return nil, nil
+	case parser.ExpressionStatement:
+		return e.evaluate(node.Body.(*parser.Node), env)
+	case parser.BlockStatement:
+		return e.statementList(node.Body.([]*parser.Node), newEnvironment(env))
+	case parser.VariableStatement:
+		return nil, e.variableStatement(node.Body.(*parser.VariableStatementValue), env)
+	case parser.ExportDeclaration:
+		return e.evaluate(node.Body.(*parser.Node), env)
+	case parser.ImportDeclaration:
+		return nil, errors.New("import declarations may only appear in modules")
+	case parser.IfStatement:
+		return e.ifStatement(node.Body.(*parser.IfStatementValue), env)
+	case parser.SwitchStatement:
+		return e.switchStatement(node.Body.(*parser.SwitchStatementValue), env)
+	case parser.BreakStatement:
+		if label, ok := node.Body.(*parser.Node); ok {
+			return nil, &breakLabel{label: label.Body.(*parser.StringLiteralValue).Value}
+		}
+		return nil, errBreak
+	case parser.LabeledStatement:
+		return e.labeledStatement(node.Body.(*parser.LabeledStatementValue), env)
+	case parser.ReturnStatement:
+		return nil, e.returnStatement(node, env)
+	case parser.ThrowStatement:
+		return nil, e.throwStatement(node.Body.(*parser.Node), env)
+	case parser.TryStatement:
+		return e.tryStatement(node.Body.(*parser.TryStatementValue), env)
+	case parser.ConditionalExpression:
+		return e.conditionalExpression(node.Body.(*parser.ConditionalExpressionValue), env)
+	case parser.AssignmentExpression:
+		return e.assignmentExpression(node.Body.(*parser.BinaryExpressionNode), env)
+	case parser.BinaryExpression:
+		return e.binaryExpression(node.Body.(*parser.BinaryExpressionNode), env)
+	case parser.FunctionExpression, parser.ArrowFunctionExpression:
+		return newClosure(node.Body.(*parser.FunctionValue), env), nil
+	case parser.CallExpression:
+		return e.callExpression(node.Body.(*parser.CallExpressionValue), env)
+	case parser.MemberExpression:
+		return e.memberExpression(node.Body.(*parser.MemberExpressionValue), env)
+	case parser.ChainExpression:
+		value, err := e.evaluate(node.Body.(*parser.Node), env)
+		if errors.Is(err, errShortCircuit) {
+			return nil, nil
+		}
+		return value, err
+	case parser.ArrayExpression:
+		return e.arrayExpression(node.Body.([]*parser.Node), env)
+	case parser.ObjectExpression:
+		return e.objectExpression(node.Body.([]*parser.Node), env)
+	case parser.UnaryExpression:
+		return e.unaryExpression(node.Body.(*parser.UnaryExpressionValue), env)
+	case parser.UpdateExpression:
+		return e.updateExpression(node.Body.(*parser.UpdateExpressionValue), env)
+	case parser.Identifier:
+		return env.get(node.Body.(*parser.StringLiteralValue).Value)
+	case parser.NumericLiteral:
+		return node.Body.(*parser.NumericLiteralValue).Value, nil
+	case parser.StringLiteral:
+		return e.checkString(node.Body.(*parser.StringLiteralValue).Value)
+	case parser.RegExpLiteral:
+		return newRegExp(node.Body.(*parser.RegExpLiteralValue))
+	case parser.BooleanLiteral:
+		return strconv.ParseBool(node.Body.(*parser.StringLiteralValue).Value)
+	case parser.NullLiteral:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported node: %s", node.NodeType)
+}
+
+func (e *Evaluator) enter() error {
+	if e.ctx != nil {
+		if err := e.ctx.Err(); err != nil {
+			return fmt.Errorf("evaluation aborted: %w", err)
+		}
+	}
+
+	e.steps++
+	if e.maxSteps > 0 && e.steps > e.maxSteps {
+		return ErrStepLimitExceeded
+	}
+	return nil
+}
+
+func (e *Evaluator) checkString(value string) (interface{}, error) {
+	if e.maxStringLength > 0 && len(value) > e.maxStringLength {
+		return nil, ErrStringLimitExceeded
+	}
+	return value, nil
+}
+
+// checkLength fails when a list or record would hold more than length elements.
+func (e *Evaluator) checkLength(length int) error {
+	if e.maxArrayLength > 0 && length > e.maxArrayLength {
+		return ErrArrayLimitExceeded
+	}
+	return nil
+}
+
+func (e *Evaluator) variableStatement(node *parser.VariableStatementValue, env *environment) error {
+	scope := env
+	if node.Kind == parser.KindVar {
+		scope = env.functionScope()
+	}
+
+	for _, declaration := range node.Declarations {
+		value := declaration.Body.(*parser.VariableDeclarationValue)
+
+		var init interface{}
+		if value.Init != nil {
+			var err error
+			init, err = e.evaluate(value.Init, env)
+			if err != nil {
+				return err
+			}
+		}
+		err := e.destructure(value.Id, init, env, func(name string, value interface{}) error {
+			scope.declare(name, value, node.Kind == parser.KindConst)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// destructure binds the identifiers of the target, an Identifier or a pattern, to the
+// parts of the value they match. A default is used when the part is null.
+func (e *Evaluator) destructure(target *parser.Node, value interface{}, env *environment, bind func(name string, value interface{}) error) error {
+	switch target.NodeType {
+	case parser.Identifier:
+		return bind(target.Body.(*parser.StringLiteralValue).Value, value)
+	case parser.AssignmentPattern:
+		pattern := target.Body.(*parser.AssignmentPatternValue)
+		if value == nil {
+			var err error
+			value, err = e.evaluate(pattern.Right, env)
+			if err != nil {
+				return err
+			}
+		}
+		return e.destructure(pattern.Left, value, env, bind)
+	case parser.ArrayPattern:
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("cannot destructure %s as a list", toString(value))
+		}
+		for index, element := range target.Body.([]*parser.Node) {
+			if element == nil {
+				continue
+			}
+			if element.NodeType == parser.RestElement {
+				return e.destructure(element.Body.(*parser.Node), rest(list, index), env, bind)
+			}
+			var item interface{}
+			if index < len(list) {
+				item = list[index]
+			}
+			if err := e.destructure(element, item, env, bind); err != nil {
+				return err
+			}
+		}
+		return nil
+	case parser.ObjectPattern:
+		record, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot destructure %s as a record", toString(value))
+		}
+		used := map[string]bool{}
+		for _, property := range target.Body.([]*parser.Node) {
+			if property.NodeType == parser.RestElement {
+				remaining := map[string]interface{}{}
+				for key, value := range record {
+					if !used[key] {
+						remaining[key] = value
+					}
+				}
+				return e.destructure(property.Body.(*parser.Node), remaining, env, bind)
+			}
+			propertyValue := property.Body.(*parser.PropertyValue)
+			key := propertyKey(propertyValue.Key)
+			used[key] = true
+			if err := e.destructure(propertyValue.Value, record[key], env, bind); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported node: %s", target.NodeType)
+}
+
+// rest copies the elements of the list from index on, it is empty past the end.
+func rest(list []interface{}, index int) []interface{} {
+	if index >= len(list) {
+		return []interface{}{}
+	}
+	return append([]interface{}{}, list[index:]...)
+}
+
+// propertyKey is the name an Identifier, StringLiteral or NumericLiteral key stands for.
+func propertyKey(key *parser.Node) string {
+	if key.NodeType == parser.NumericLiteral {
+		return strconv.Itoa(key.Body.(*parser.NumericLiteralValue).Value)
+	}
+	return key.Body.(*parser.StringLiteralValue).Value
+}
+
+// hoist declares every var of the statements up front, so they read as null before
+// their declaration runs. The vars of nested functions belong to those functions.
+func hoist(statements []*parser.Node, env *environment) {
+	for _, statement := range statements {
+		parser.Walk(statement, func(node *parser.Node) bool {
+			if isFunction(node) {
+				return false
+			}
+			if node.NodeType != parser.VariableStatement || node.Body.(*parser.VariableStatementValue).Kind != parser.KindVar {
+				return true
+			}
+			for _, declaration := range node.Body.(*parser.VariableStatementValue).Declarations {
+				for _, id := range parser.BindingIdentifiers(declaration.Body.(*parser.VariableDeclarationValue).Id) {
+					name := id.Body.(*parser.StringLiteralValue).Value
+					if env.resolve(name) != env {
+						env.declare(name, nil, false)
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// newRegExp compiles the literal, every evaluation creates a new value.
+func newRegExp(node *parser.RegExpLiteralValue) (*RegExp, error) {
+	compiled, err := node.Compile()
+	if err != nil {
+		return nil, err
+	}
+	return &RegExp{Source: node.Pattern, Flags: node.Flags, regexp: compiled}, nil
+}
+
+func isFunction(node *parser.Node) bool {
+	return node.NodeType == parser.FunctionExpression || node.NodeType == parser.ArrowFunctionExpression
+}
+
+func (e *Evaluator) ifStatement(node *parser.IfStatementValue, env *environment) (interface{}, error) {
+	test, err := e.evaluate(node.Test, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTruthy(test) {
+		return e.evaluate(node.Consequent, env)
+	}
+	if node.Alternate != nil {
+		return e.evaluate(node.Alternate, env)
+	}
+	return nil, nil
+}
+
+// switchStatement runs the statements from the first case equal to the discriminant,
+// or from the default clause, falling through the following cases until a break.
+func (e *Evaluator) switchStatement(node *parser.SwitchStatementValue, env *environment) (interface{}, error) {
+	discriminant, err := e.evaluate(node.Discriminant, env)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := newEnvironment(env)
+	matched := -1
+	for index, clause := range node.Cases {
+		value := clause.Body.(*parser.SwitchCaseValue)
+		if value.Test == nil {
+			continue
+		}
+		test, testErr := e.evaluate(value.Test, scope)
+		if testErr != nil {
+			return nil, testErr
+		}
+		if strictEquals(test, discriminant) {
+			matched = index
+			break
+		}
+	}
+	if matched == -1 {
+		for index, clause := range node.Cases {
+			if clause.Body.(*parser.SwitchCaseValue).Test == nil {
+				matched = index
+			}
+		}
+	}
+	if matched == -1 {
+		return nil, nil
+	}
+
+	var result interface{}
+	for _, clause := range node.Cases[matched:] {
+		value, listErr := e.statementList(clause.Body.(*parser.SwitchCaseValue).Consequent, scope)
+		if errors.Is(listErr, errBreak) {
+			return result, nil
+		}
+		if listErr != nil {
+			return nil, listErr
+		}
+		result = value
+	}
+	return result, nil
+}
+
+// labeledStatement ends the statement at a break with its label.
+func (e *Evaluator) labeledStatement(node *parser.LabeledStatementValue, env *environment) (interface{}, error) {
+	value, err := e.evaluate(node.Body, env)
+	var broken *breakLabel
+	if errors.As(err, &broken) && broken.label == node.Label.Body.(*parser.StringLiteralValue).Value {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (e *Evaluator) returnStatement(node *parser.Node, env *environment) error {
+	argument, ok := node.Body.(*parser.Node)
+	if !ok {
+		return &returnValue{}
+	}
+	value, err := e.evaluate(argument, env)
+	if err != nil {
+		return err
+	}
+	return &returnValue{value: value}
+}
+
+func (e *Evaluator) throwStatement(argument *parser.Node, env *environment) error {
+	value, err := e.evaluate(argument, env)
+	if err != nil {
+		return err
+	}
+	return &Exception{Value: value}
+}
+
+// tryStatement runs the catch clause for any exception or runtime error of the block,
+// binding the thrown value or the error message, and always runs the finally block.
+// Exceeded limits, cancellation and break are not exceptions and pass through.
+func (e *Evaluator) tryStatement(node *parser.TryStatementValue, env *environment) (interface{}, error) {
+	result, err := e.evaluate(node.Block, env)
+
+	if err != nil && node.Handler != nil && isCatchable(err) {
+		handler := node.Handler.Body.(*parser.CatchClauseValue)
+		scope := newEnvironment(env)
+		if handler.Param != nil {
+			scope.declare(handler.Param.Body.(*parser.StringLiteralValue).Value, thrownValue(err), false)
+		}
+		result, err = e.evaluate(handler.Body, scope)
+	}
+
+	if node.Finalizer != nil {
+		if _, finalizerErr := e.evaluate(node.Finalizer, env); finalizerErr != nil {
+			return nil, finalizerErr
+		}
+	}
+	return result, err
+}
+
+func isCatchable(err error) bool {
+	var returned *returnValue
+	var broken *breakLabel
+	return !errors.Is(err, errBreak) &&
+		!errors.As(err, &broken) &&
+		!errors.As(err, &returned) &&
+		!errors.Is(err, ErrStepLimitExceeded) &&
+		!errors.Is(err, ErrCallDepthExceeded) &&
+		!errors.Is(err, ErrStringLimitExceeded) &&
+		!errors.Is(err, ErrArrayLimitExceeded) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
+}
+
+func thrownValue(err error) interface{} {
+	var exception *Exception
+	if errors.As(err, &exception) {
+		return exception.Value
+	}
+	return err.Error()
+}
+
+// newClosure creates the function, a named function expression sees itself under its
+// name.
+func newClosure(node *parser.FunctionValue, env *environment) *closure {
+	if node.Id == nil {
+		return &closure{node: node, env: env}
+	}
+	scope := newEnvironment(env)
+	function := &closure{node: node, env: scope}
+	scope.declare(node.Id.Body.(*parser.StringLiteralValue).Value, function, true)
+	return function
+}
+
+func (e *Evaluator) callExpression(node *parser.CallExpressionValue, env *environment) (interface{}, error) {
+	callee, err := e.evaluate(node.Callee, env)
+	if err != nil {
+		return nil, err
+	}
+	if node.Optional && callee == nil {
+		return nil, errShortCircuit
+	}
+
+	args, err := e.list(node.Arguments, env)
+	if err != nil {
+		return nil, err
+	}
+	return e.Call(callee, args)
+}
+
+// Call calls a script function or a HostFunction with the arguments, missing arguments
+// are null.
+func (e *Evaluator) Call(function interface{}, args []interface{}) (interface{}, error) {
+	switch callee := function.(type) {
+	case HostFunction:
+		return callee(e, args)
+	case *closure:
+		return e.callClosure(callee, args)
+	}
+	return nil, fmt.Errorf("%s is not a function", toString(function))
+}
+
+func (e *Evaluator) callClosure(function *closure, args []interface{}) (interface{}, error) {
+	e.depth++
+	defer func() { e.depth-- }()
+	if e.maxCallDepth > 0 && e.depth > e.maxCallDepth {
+		return nil, ErrCallDepthExceeded
+	}
+
+	env := newFunctionEnvironment(function.env)
+	for index, param := range function.node.Params {
+		if param.NodeType == parser.RestElement {
+			env.declare(param.Body.(*parser.Node).Body.(*parser.StringLiteralValue).Value, rest(args, index), false)
+			break
+		}
+		var arg interface{}
+		if index < len(args) {
+			arg = args[index]
+		}
+		env.declare(param.Body.(*parser.StringLiteralValue).Value, arg, false)
+	}
+
+	if function.node.Expression {
+		return e.evaluate(function.node.Body, env)
+	}
+
+	statements := function.node.Body.Body.([]*parser.Node)
+	hoist(statements, env)
+	_, err := e.statementList(statements, env)
+	var returned *returnValue
+	var broken *breakLabel
+	switch {
+	case errors.As(err, &returned):
+		return returned.value, nil
+	case errors.Is(err, errBreak):
+		// a break never leaves the function it is in
+		return nil, errors.New(errBreak.Error())
+	case errors.As(err, &broken):
+		return nil, errors.New(broken.Error())
+	}
+	return nil, err
+}
+
+// memberExpression reads a property of a record or, with a computed index, an element
+// of a list. A missing property or element is null.
+func (e *Evaluator) memberExpression(node *parser.MemberExpressionValue, env *environment) (interface{}, error) {
+	object, err := e.evaluate(node.Object, env)
+	if err != nil {
+		return nil, err
+	}
+	if node.Optional && object == nil {
+		return nil, errShortCircuit
+	}
+
+	var property interface{}
+	if node.Computed {
+		property, err = e.evaluate(node.Property, env)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		property = node.Property.Body.(*parser.StringLiteralValue).Value
+	}
+
+	switch object := object.(type) {
+	case map[string]interface{}:
+		if name, ok := property.(string); ok {
+			return object[name], nil
+		}
+	case []interface{}:
+		if index, ok := property.(int); ok {
+			if index < 0 || index >= len(object) {
+				return nil, nil
+			}
+			return object[index], nil
+		}
+	case *RegExp:
+		if name, ok := property.(string); ok {
+			return object.property(name), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot read property %s of %s", toString(property), toString(object))
+}
+
+func (e *Evaluator) arrayExpression(elements []*parser.Node, env *environment) (interface{}, error) {
+	list, err := e.list(elements, env)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// list evaluates array elements or call arguments, a spread element adds every element
+// of the list it evaluates to and a hole adds null.
+func (e *Evaluator) list(elements []*parser.Node, env *environment) ([]interface{}, error) {
+	list := make([]interface{}, 0, len(elements))
+	for _, element := range elements {
+		if element == nil {
+			if err := e.checkLength(len(list) + 1); err != nil {
+				return nil, err
+			}
+			list = append(list, nil)
+			continue
+		}
+		if element.NodeType == parser.SpreadElement {
+			value, err := e.evaluate(element.Body.(*parser.Node), env)
+			if err != nil {
+				return nil, err
+			}
+			spread, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot spread %s into a list", toString(value))
+			}
+			if err := e.checkLength(len(list) + len(spread)); err != nil {
+				return nil, err
+			}
+			list = append(list, spread...)
+			continue
+		}
+		value, err := e.evaluate(element, env)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.checkLength(len(list) + 1); err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+// objectExpression builds a record, later properties overwrite earlier ones and a
+// spread element copies the properties of a record while spreading null adds nothing.
+func (e *Evaluator) objectExpression(properties []*parser.Node, env *environment) (interface{}, error) {
+	record := make(map[string]interface{}, len(properties))
+	for _, property := range properties {
+		if property.NodeType == parser.SpreadElement {
+			value, err := e.evaluate(property.Body.(*parser.Node), env)
+			if err != nil {
+				return nil, err
+			}
+			spread, ok := value.(map[string]interface{})
+			if !ok && value != nil {
+				return nil, fmt.Errorf("cannot spread %s into a record", toString(value))
+			}
+			for key, propertyValue := range spread {
+				record[key] = propertyValue
+			}
+			if err := e.checkLength(len(record)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		value := property.Body.(*parser.PropertyValue)
+		propertyValue, err := e.evaluate(value.Value, env)
+		if err != nil {
+			return nil, err
+		}
+		record[propertyKey(value.Key)] = propertyValue
+		if err := e.checkLength(len(record)); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+func (e *Evaluator) conditionalExpression(node *parser.ConditionalExpressionValue, env *environment) (interface{}, error) {
+	test, err := e.evaluate(node.Test, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTruthy(test) {
+		return e.evaluate(node.Consequent, env)
+	}
+	return e.evaluate(node.Alternate, env)
+}
+
+// assignmentExpression assigns the value of the right side, ??= only evaluates it when
+// the variable is null.
+func (e *Evaluator) assignmentExpression(node *parser.BinaryExpressionNode, env *environment) (interface{}, error) {
+	if node.Operator == "??=" {
+		name := node.Left.(*parser.Node).Body.(*parser.StringLiteralValue).Value
+		current, err := env.get(name)
+		if err != nil || current != nil {
+			return current, err
+		}
+	}
+
+	value, err := e.evaluate(node.Right.(*parser.Node), env)
+	if err != nil {
+		return nil, err
+	}
+
+	left := node.Left.(*parser.Node)
+	if left.NodeType != parser.Identifier {
+		return value, e.destructure(left, value, env, env.assign)
+	}
+	name := left.Body.(*parser.StringLiteralValue).Value
+
+	if node.Operator != "=" && node.Operator != "??=" {
+		current, currentErr := env.get(name)
+		if currentErr != nil {
+			return nil, currentErr
+		}
+		value, err = e.arithmetic(strings.TrimSuffix(node.Operator, "="), current, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return value, env.assign(name, value)
+}
+
+func (e *Evaluator) binaryExpression(node *parser.BinaryExpressionNode, env *environment) (interface{}, error) {
+	left, err := e.evaluate(node.Left.(*parser.Node), env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch node.Operator {
+	case "&&", "AND":
+		if !isTruthy(left) {
+			return left, nil
+		}
+		return e.evaluate(node.Right.(*parser.Node), env)
+	case "||", "OR":
+		if isTruthy(left) {
+			return left, nil
+		}
+		return e.evaluate(node.Right.(*parser.Node), env)
+	case "??":
+		if left != nil {
+			return left, nil
+		}
+		return e.evaluate(node.Right.(*parser.Node), env)
+	}
+
+	right, err := e.evaluate(node.Right.(*parser.Node), env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch node.Operator {
+	case "==", "===":
+		return strictEquals(left, right), nil
+	case "!=", "!==":
+		return !strictEquals(left, right), nil
+	case ">", ">=", "<", "<=":
+		return compare(node.Operator, left, right)
+	}
+	return e.arithmetic(node.Operator, left, right)
+}
+
+func (e *Evaluator) arithmetic(operator string, left interface{}, right interface{}) (interface{}, error) {
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+	if operator == "+" && (leftIsString || rightIsString) {
+		if !leftIsString {
+			leftString = toString(left)
+		}
+		if !rightIsString {
+			rightString = toString(right)
+		}
+		return e.checkString(leftString + rightString)
+	}
+
+	leftNumber, leftOk := left.(int)
+	rightNumber, rightOk := right.(int)
+	if !leftOk || !rightOk {
+		return nil, fmt.Errorf("invalid operands for %s: %s, %s", operator, toString(left), toString(right))
+	}
+
+	switch operator {
+	case "+":
+		return leftNumber + rightNumber, nil
+	case "-":
+		return leftNumber - rightNumber, nil
+	case "*":
+		return leftNumber * rightNumber, nil
+	case "/", "%":
+		if rightNumber == 0 {
+			return nil, errors.New("division by zero")
+		}
+		if operator == "%" {
+			return leftNumber % rightNumber, nil
+		}
+		return leftNumber / rightNumber, nil
+	case "**":
+		return power(leftNumber, rightNumber)
+	case "&":
+		return int(int32(leftNumber) & int32(rightNumber)), nil
+	case "|":
+		return int(int32(leftNumber) | int32(rightNumber)), nil
+	case "^":
+		return int(int32(leftNumber) ^ int32(rightNumber)), nil
+	case "<<":
+		return int(int32(leftNumber) << (uint(rightNumber) & 31)), nil
+	case ">>":
+		return int(int32(leftNumber) >> (uint(rightNumber) & 31)), nil
+	case ">>>":
+		return int(uint32(leftNumber) >> (uint(rightNumber) & 31)), nil
+	}
+	return nil, fmt.Errorf("unsupported operator: %s", operator)
+}
+
+// power raises base to a non-negative exponent by repeated squaring.
+func power(base int, exponent int) (int, error) {
+	if exponent < 0 {
+		return 0, fmt.Errorf("negative exponent: %d", exponent)
+	}
+	result := 1
+	for ; exponent > 0; exponent >>= 1 {
+		if exponent&1 == 1 {
+			result *= base
+		}
+		base *= base
+	}
+	return result, nil
+}
+
+// unaryExpression applies - + and ~ to numbers, ~ works on 32-bit integers like the
+// other bitwise operators.
+func (e *Evaluator) unaryExpression(node *parser.UnaryExpressionValue, env *environment) (interface{}, error) {
+	argument, err := e.evaluate(node.Argument, env)
+	if err != nil {
+		return nil, err
+	}
+
+	number, ok := argument.(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid operand for %s: %s", node.Operator, toString(argument))
+	}
+	switch node.Operator {
+	case "-":
+		return -number, nil
+	case "~":
+		return int(^int32(number)), nil
+	}
+	return number, nil
+}
+
+// updateExpression adds or subtracts one from the variable and returns its new value
+// for a prefix operator and its previous one for a postfix operator.
+func (e *Evaluator) updateExpression(node *parser.UpdateExpressionValue, env *environment) (interface{}, error) {
+	name := node.Argument.Body.(*parser.StringLiteralValue).Value
+	current, err := env.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	number, ok := current.(int)
+	if !ok {
+		return nil, fmt.Errorf("invalid operand for %s: %s", node.Operator, toString(current))
+	}
+	updated := number + 1
+	if node.Operator == "--" {
+		updated = number - 1
+	}
+	if err := env.assign(name, updated); err != nil {
+		return nil, err
+	}
+
+	if node.Prefix {
+		return updated, nil
+	}
+	return number, nil
+}
+
+func compare(operator string, left interface{}, right interface{}) (bool, error) {
+	var order int
+	switch leftValue := left.(type) {
+	case int:
+		rightValue, ok := right.(int)
+		if !ok {
+			return false, fmt.Errorf("invalid operands for %s: %s, %s", operator, toString(left), toString(right))
+		}
+		switch {
+		case leftValue < rightValue:
+			order = -1
+		case leftValue > rightValue:
+			order = 1
+		}
+	case string:
+		rightValue, ok := right.(string)
+		if !ok {
+			return false, fmt.Errorf("invalid operands for %s: %s, %s", operator, toString(left), toString(right))
+		}
+		switch {
+		case leftValue < rightValue:
+			order = -1
+		case leftValue > rightValue:
+			order = 1
+		}
+	default:
+		return false, fmt.Errorf("invalid operands for %s: %s, %s", operator, toString(left), toString(right))
+	}
+
+	switch operator {
+	case ">":
+		return order > 0, nil
+	case ">=":
+		return order >= 0, nil
+	case "<":
+		return order < 0, nil
+	default:
+		return order <= 0, nil
+	}
+}
+
+// strictEquals compares functions, lists and records by identity, Go cannot compare
+// them with ==.
+func strictEquals(left interface{}, right interface{}) bool {
+	leftValue, rightValue := reflect.ValueOf(left), reflect.ValueOf(right)
+	switch leftValue.Kind() {
+	case reflect.Func, reflect.Map, reflect.Slice:
+		return rightValue.IsValid() && leftValue.Type() == rightValue.Type() && leftValue.Pointer() == rightValue.Pointer()
+	}
+	return left == right
+}
+
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+func toString(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	return fmt.Sprint(value)
+}
+
+func newEnvironment(parent *environment) *environment {
+	return &environment{
+		values:    map[string]interface{}{},
+		constants: map[string]bool{},
+		parent:    parent,
+	}
+}
+
+func newFunctionEnvironment(parent *environment) *environment {
+	env := newEnvironment(parent)
+	env.function = true
+	return env
+}
+
+func (env *environment) declare(name string, value interface{}, constant bool) {
+	env.values[name] = value
+	env.constants[name] = constant
+}
+
+func (env *environment) functionScope() *environment {
+	scope := env
+	for !scope.function {
+		scope = scope.parent
+	}
+	return scope
+}
+
+func (env *environment) resolve(name string) *environment {
+	for scope := env; scope != nil; scope = scope.parent {
+		if _, ok := scope.values[name]; ok {
+			return scope
+		}
+	}
+	return nil
+}
+
+func (env *environment) get(name string) (interface{}, error) {
+	scope := env.resolve(name)
+	if scope == nil {
+		return nil, fmt.Errorf("%s is not defined", name)
+	}
+	return scope.values[name], nil
+}
+
+func (env *environment) assign(name string, value interface{}) error {
+	scope := env.resolve(name)
+	if scope == nil {
+		return fmt.Errorf("%s is not defined", name)
+	}
+	if scope.constants[name] {
+		return fmt.Errorf("assignment to constant variable: %s", name)
+	}
+	scope.values[name] = value
+	return nil
+}
@@ -0,0 +1,676 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dlanell/go-rdparser/module"
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	text          string
+	props         Props
+	expectedValue interface{}
+	expectedError error
+}
+
+func run(t *testing.T, ctx context.Context, tc test) {
+	program, err := parser.New(parser.Props{Text: tc.text}).Run()
+	assert.NoError(t, err)
+
+	value, err := New(tc.props).Run(ctx, program)
+	assert.Equal(t, tc.expectedValue, value)
+	assert.Equal(t, tc.expectedError, err)
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Expressions", func(t *testing.T) {
+		tests := map[string]test{
+			"given numbers": {
+				text:          `42;`,
+				expectedValue: 42,
+			},
+			"given math precedence": {
+				text:          `2 + 2 * 3;`,
+				expectedValue: 8,
+			},
+			"given parenthesized expression": {
+				text:          `(2 + 2) * 3;`,
+				expectedValue: 12,
+			},
+			"given string concatenation": {
+				text:          `"sith" + " " + 42;`,
+				expectedValue: "sith 42",
+			},
+			"given relational expression": {
+				text:          `4 >= 2;`,
+				expectedValue: true,
+			},
+			"given equality expression": {
+				text:          `"revan" == "revan";`,
+				expectedValue: true,
+			},
+			"given logical and": {
+				text:          `true && 0;`,
+				expectedValue: 0,
+			},
+			"given logical or": {
+				text:          `null || "default";`,
+				expectedValue: "default",
+			},
+			"given conditional expression": {
+				text:          `let x = 0; x ? "yes" : x == 0 ? "zero" : "no";`,
+				expectedValue: "zero",
+			},
+			"given conditional expression evaluating one branch": {
+				text:          `let x = 1; true ? x = 2 : x = 3; x;`,
+				expectedValue: 2,
+			},
+			"given modulo and exponent": {
+				text:          `17 % 5 + 2 ** 3 ** 2;`,
+				expectedValue: 514,
+			},
+			"given negative exponent": {
+				text:          `2 ** -1;`,
+				expectedError: errors.New("negative exponent: -1"),
+			},
+			"given bitwise operators": {
+				text:          `(6 & 3) + (6 | 3) * 10 + (6 ^ 3) * 100;`,
+				expectedValue: 572,
+			},
+			"given shift operators": {
+				text:          `(1 << 4) + (-16 >> 2) + (-1 >>> 28);`,
+				expectedValue: 27,
+			},
+			"given unary operators": {
+				text:          `-~5 + +2;`,
+				expectedValue: 8,
+			},
+			"given strict equality": {
+				text:          `1 === 1 && "1" !== 1;`,
+				expectedValue: true,
+			},
+			"given unary operator on string": {
+				text:          `-"x";`,
+				expectedError: errors.New("invalid operand for -: x"),
+			},
+			"given modulo by zero": {
+				text:          `1 % 0;`,
+				expectedError: errors.New("division by zero"),
+			},
+			"given division by zero": {
+				text:          `1 / 0;`,
+				expectedError: errors.New("division by zero"),
+			},
+			"given undefined identifier": {
+				text:          `x;`,
+				expectedError: errors.New("x is not defined"),
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, context.Background(), tc)
+			})
+		}
+	})
+	t.Run("Statements", func(t *testing.T) {
+		tests := map[string]test{
+			"given variable statement": {
+				text:          `let x = 2, y; y = x * 3; y;`,
+				expectedValue: 6,
+			},
+			"given complex assignment": {
+				text:          `let x = 2; x += 40;`,
+				expectedValue: 42,
+			},
+			"given compound assignments": {
+				text:          `let x = 7; x %= 4; x **= 3; x <<= 1; x >>>= 2; x |= 64; x;`,
+				expectedValue: 77,
+			},
+			"given update expressions": {
+				text:          `let x = 1; let y = x++; let z = ++x; --x; x-- + y * 10 + z * 100;`,
+				expectedValue: 312,
+			},
+			"given update of const": {
+				text:          `const x = 1; x++;`,
+				expectedError: errors.New("assignment to constant variable: x"),
+			},
+			"given if statement": {
+				text:          `let x = 1; if (x > 0) { x = "positive"; } else { x = "negative"; } x;`,
+				expectedValue: "positive",
+			},
+			"given block scope": {
+				text:          `let x = 1; { let x = 2; } x;`,
+				expectedValue: 1,
+			},
+			"given assignment to const": {
+				text:          `const x = 1; x += 1;`,
+				expectedError: errors.New("assignment to constant variable: x"),
+			},
+			"given const shadowed in block": {
+				text:          `const x = 1; { let x = 2; x = 3; } x;`,
+				expectedValue: 1,
+			},
+			"given var declared in block": {
+				text:          `{ var x = 2; } x;`,
+				expectedValue: 2,
+			},
+			"given var read before its declaration": {
+				text:          `let y = x; { var x = 1; } y;`,
+				expectedValue: nil,
+			},
+			"given switch with matching case": {
+				text:          `let x = 2, y; switch (x) { case 1: y = "one"; break; case 2: y = "two"; break; default: y = "many"; } y;`,
+				expectedValue: "two",
+			},
+			"given switch falling through cases": {
+				text:          `let y = ""; switch ("a") { case "a": y += "a"; case "b": y += "b"; break; case "c": y += "c"; } y;`,
+				expectedValue: "ab",
+			},
+			"given switch without match running default in the middle": {
+				text:          `let y = ""; switch (9) { case 1: y += "1"; default: y += "d"; case 2: y += "2"; } y;`,
+				expectedValue: "d2",
+			},
+			"given switch without match or default": {
+				text:          `let y = 0; switch (9) { case 1: y = 1; } y;`,
+				expectedValue: 0,
+			},
+			"given break inside if within switch": {
+				text:          `let y = 0; switch (1) { case 1: if (true) { break; } y = 1; } y;`,
+				expectedValue: 0,
+			},
+			"given break outside of switch": {
+				text:          `break;`,
+				expectedError: errors.New("illegal break statement"),
+			},
+			"given labeled break out of nested blocks": {
+				text:          `let y = ""; outer: { inner: { y += "a"; break outer; } y += "b"; } y;`,
+				expectedValue: "a",
+			},
+			"given labeled break out of switch and if": {
+				text:          `let y = 0; found: if (true) { switch (1) { case 1: break found; } y = 1; } y;`,
+				expectedValue: 0,
+			},
+			"given labeled break leaving function": {
+				text:          `outer: { (() => { break outer; })(); }`,
+				expectedError: errors.New("undefined label: outer"),
+			},
+			"given uncaught throw": {
+				text:          `throw "bad input";`,
+				expectedError: &Exception{Value: "bad input"},
+			},
+			"given caught throw": {
+				text:          `let y; try { throw 42; y = 1; } catch (e) { y = e + 1; } y;`,
+				expectedValue: 43,
+			},
+			"given caught runtime error": {
+				text:          `let y; try { y = missing; } catch (e) { y = "recovered: " + e; } y;`,
+				expectedValue: "recovered: missing is not defined",
+			},
+			"given finally after catch": {
+				text:          `let y = ""; try { throw 1; } catch { y += "c"; } finally { y += "f"; } y;`,
+				expectedValue: "cf",
+			},
+			"given finally without catch": {
+				text:          `let y = ""; try { try { throw "inner"; } finally { y += "f"; } } catch (e) { y += e; } y;`,
+				expectedValue: "finner",
+			},
+			"given throw from catch": {
+				text:          `try { throw 1; } catch (e) { throw e + 1; }`,
+				expectedError: &Exception{Value: 2},
+			},
+			"given catch parameter scoped to catch": {
+				text:          `try { throw 1; } catch (e) {} e;`,
+				expectedError: errors.New("e is not defined"),
+			},
+			"given break through finally": {
+				text:          `let y = ""; switch (1) { case 1: try { break; } finally { y = "f"; } y = "after"; } y;`,
+				expectedValue: "f",
+			},
+			"given block declaration used outside of block": {
+				text:          `{ let y = 2; } y;`,
+				expectedError: errors.New("y is not defined"),
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, context.Background(), tc)
+			})
+		}
+	})
+	t.Run("Functions", func(t *testing.T) {
+		tests := map[string]test{
+			"given arrow function": {
+				text:          `let add = (a, b) => a + b; add(40, 2);`,
+				expectedValue: 42,
+			},
+			"given function expression with return": {
+				text:          `let sign = function (x) { if (x < 0) { return -1; } return 1; }; sign(-5) + sign(5) * 10;`,
+				expectedValue: 9,
+			},
+			"given function without return": {
+				text:          `(() => { 1; })();`,
+				expectedValue: nil,
+			},
+			"given missing argument": {
+				text:          `(x => x)();`,
+				expectedValue: nil,
+			},
+			"given closure": {
+				text:          `let counter = () => { let count = 0; return () => ++count; }; let next = counter(); next(); next();`,
+				expectedValue: 2,
+			},
+			"given named function expression calling itself": {
+				text:          `let fact = function f(n) { return n < 2 ? 1 : n * f(n - 1); }; fact(5);`,
+				expectedValue: 120,
+			},
+			"given var inside function": {
+				text:          `var x = 1; (() => { var x = 2; })(); x;`,
+				expectedValue: 1,
+			},
+			"given return inside switch inside function": {
+				text:          `let f = x => { switch (x) { case 1: return "one"; } return "other"; }; f(1) + f(2);`,
+				expectedValue: "oneother",
+			},
+			"given return caught by try": {
+				text:          `let f = () => { try { return 1; } catch (e) { return 2; } finally { 3; } }; f();`,
+				expectedValue: 1,
+			},
+			"given function compared with itself": {
+				text:          `let f = () => 1; f == f && f != (() => 1);`,
+				expectedValue: true,
+			},
+			"given call of non function": {
+				text:          `let x = 1; x();`,
+				expectedError: errors.New("1 is not a function"),
+			},
+			"given return outside of function": {
+				text:          `return 1;`,
+				expectedError: &returnValue{value: 1},
+			},
+			"given property of number": {
+				text:          `let x = 1; x.y;`,
+				expectedError: errors.New("cannot read property y of 1"),
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, context.Background(), tc)
+			})
+		}
+	})
+	t.Run("Destructuring", func(t *testing.T) {
+		tests := map[string]test{
+			"given object pattern with rename and default": {
+				text:          `let { a, b: renamed = 1, c = 2 } = { a: 40, c: null }; a + renamed + c;`,
+				expectedValue: 43,
+			},
+			"given array pattern with hole": {
+				text:          `let [x, , y] = [1, 2, 3]; x + y;`,
+				expectedValue: 4,
+			},
+			"given nested patterns": {
+				text:          `const { user: { name, tags: [first] } } = { user: { name: "ada", tags: ["admin"] } }; name + first;`,
+				expectedValue: "adaadmin",
+			},
+			"given destructuring assignment swapping values": {
+				text:          `let a = 1; let b = 2; [a, b] = [b, a]; a * 10 + b;`,
+				expectedValue: 21,
+			},
+			"given destructuring of host record": {
+				text:          `let { id, "full name": name } = user; id + ": " + name;`,
+				props:         Props{Globals: map[string]interface{}{"user": map[string]interface{}{"id": "7", "full name": "Ada"}}},
+				expectedValue: "7: Ada",
+			},
+			"given computed member access": {
+				text:          `let list = [1, 2]; let record = { key: 3 }; list[1] + record["key"] + (list[5] == null ? 10 : 0);`,
+				expectedValue: 15,
+			},
+			"given var pattern hoisted": {
+				text:          `let before = a; var [a] = [1]; before == null && a == 1;`,
+				expectedValue: true,
+			},
+			"given object pattern of number": {
+				text:          `let { a } = 1;`,
+				expectedError: errors.New("cannot destructure 1 as a record"),
+			},
+			"given array pattern of null": {
+				text:          `let [a] = null;`,
+				expectedError: errors.New("cannot destructure null as a list"),
+			},
+			"given assignment pattern to const": {
+				text:          `const a = 1; [a] = [2];`,
+				expectedError: errors.New("assignment to constant variable: a"),
+			},
+			"given index of record": {
+				text:          `let record = {}; record[0];`,
+				expectedError: errors.New("cannot read property 0 of map[]"),
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, context.Background(), tc)
+			})
+		}
+	})
+	t.Run("SpreadAndRest", func(t *testing.T) {
+		tests := map[string]test{
+			"given merged config records": {
+				text:          `let defaults = { retries: 3, verbose: false }; let config = { ...defaults, ...{ verbose: true }, ...null }; config.retries + (config.verbose ? 10 : 0);`,
+				expectedValue: 13,
+			},
+			"given spread in array and call arguments": {
+				text:          `let add = (a, b, c) => a + b + c; let rest = [2, 3]; add(...[1], ...rest) + [0, ...rest, 4][3];`,
+				expectedValue: 10,
+			},
+			"given rest parameter": {
+				text:          `let count = function (first, ...others) { return first + others[0] + others[1]; }; count(1, 2, 3, 4);`,
+				expectedValue: 6,
+			},
+			"given empty rest parameter": {
+				text:          `((...all) => all)();`,
+				expectedValue: []interface{}{},
+			},
+			"given rest elements in patterns": {
+				text:          `let [head, ...tail] = [1, 2, 3]; let { a, ...others } = { a: 1, b: 2, c: 3 }; head + tail[1] + others.b + others.c + (others.a == null ? 0 : 100);`,
+				expectedValue: 9,
+			},
+			"given rest element in assignment pattern": {
+				text:          `let first; let rest; [first, ...rest] = [1]; rest;`,
+				expectedValue: []interface{}{},
+			},
+			"given spread of number into list": {
+				text:          `[...1];`,
+				expectedError: errors.New("cannot spread 1 into a list"),
+			},
+			"given spread of list into record": {
+				text:          `({ ...[1] });`,
+				expectedError: errors.New("cannot spread [1] into a record"),
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, context.Background(), tc)
+			})
+		}
+	})
+	t.Run("OptionalChainingAndNullishCoalescing", func(t *testing.T) {
+		tests := map[string]test{
+			"given optional member of missing record": {
+				text:          `let user = { profile: null }; user.profile?.address.city;`,
+				expectedValue: nil,
+			},
+			"given optional member of record": {
+				text:          `let user = { profile: { name: "ada" } }; user?.profile?.["name"];`,
+				expectedValue: "ada",
+			},
+			"given optional call of missing function": {
+				text:          `let calls = 0; let f = null; f?.(calls++); calls;`,
+				expectedValue: 0,
+			},
+			"given optional call of function": {
+				text:          `let f = x => x * 2; f?.(21);`,
+				expectedValue: 42,
+			},
+			"given parentheses ending the chain": {
+				text:          `let a = null; (a?.b).c;`,
+				expectedError: errors.New("cannot read property c of null"),
+			},
+			"given nullish coalescing": {
+				text:          `let missing = null; (missing ?? "default") + (0 ?? 1) + (false ?? true);`,
+				expectedValue: "default0false",
+			},
+			"given nullish assignment": {
+				text:          `let a = null; let b = 1; let calls = 0; a ??= 2; b ??= calls++; a + b + calls;`,
+				expectedValue: 3,
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, context.Background(), tc)
+			})
+		}
+	})
+	t.Run("RegExpLiteral", func(t *testing.T) {
+		tests := map[string]test{
+			"given matching email": {
+				text:          `/^[a-z]+@[a-z]+\.com$/i.test("Ada@Example.com");`,
+				expectedValue: true,
+			},
+			"given id not matching": {
+				text:          `let id = /^id-[0-9]+$/; id.test("id-") || id.test("x-12");`,
+				expectedValue: false,
+			},
+			"given source and flags": {
+				text:          `let r = /a+/mi; r.source + "/" + r.flags;`,
+				expectedValue: "a+/mi",
+			},
+			"given number argument": {
+				text:          `/^[0-9]+$/.test(42);`,
+				expectedValue: true,
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, context.Background(), tc)
+			})
+		}
+	})
+	t.Run("Limits", func(t *testing.T) {
+		tests := map[string]test{
+			"given steps within limit": {
+				text:          `1 + 2;`,
+				props:         Props{MaxSteps: 4},
+				expectedValue: 3,
+			},
+			"given too many steps": {
+				text:          `1 + 2 + 3;`,
+				props:         Props{MaxSteps: 4},
+				expectedError: ErrStepLimitExceeded,
+			},
+			"given deeply nested blocks within call depth": {
+				text:          `{{{{ 1; }}}}`,
+				props:         Props{MaxCallDepth: 1},
+				expectedValue: 1,
+			},
+			"given calls within call depth": {
+				text:          `let f = (n) => n ? f(n - 1) : 0; f(3);`,
+				props:         Props{MaxCallDepth: 4},
+				expectedValue: 0,
+			},
+			"given recursion exceeding call depth": {
+				text:          `let f = (n) => f(n + 1); f(0);`,
+				props:         Props{MaxCallDepth: 4},
+				expectedError: ErrCallDepthExceeded,
+			},
+			"given string within limit": {
+				text:          `"sith" + "lord";`,
+				props:         Props{MaxStringLength: 8},
+				expectedValue: "sithlord",
+			},
+			"given string exceeding limit": {
+				text:          `let s = "sith"; s = s + s; s = s + s;`,
+				props:         Props{MaxStringLength: 8},
+				expectedError: ErrStringLimitExceeded,
+			},
+			"given array within limit": {
+				text:          `[1, 2, , 4];`,
+				props:         Props{MaxArrayLength: 4},
+				expectedValue: []interface{}{1, 2, nil, 4},
+			},
+			"given array exceeding limit": {
+				text:          `[1, 2, 3, 4, 5];`,
+				props:         Props{MaxArrayLength: 4},
+				expectedError: ErrArrayLimitExceeded,
+			},
+			"given spread doubling a list past limit": {
+				text:          `let f = (a, n) => n ? f([...a, ...a], n - 1) : a; f([1], 40);`,
+				props:         Props{MaxArrayLength: 1024},
+				expectedError: ErrArrayLimitExceeded,
+			},
+			"given spread arguments exceeding limit": {
+				text:          `let f = (...args) => args; let a = [1, 2, 3]; f(...a, ...a);`,
+				props:         Props{MaxArrayLength: 4},
+				expectedError: ErrArrayLimitExceeded,
+			},
+			"given spread record exceeding limit": {
+				text:          `let r = { a: 1, b: 2 }; ({ c: 3, ...r });`,
+				props:         Props{MaxArrayLength: 2},
+				expectedError: ErrArrayLimitExceeded,
+			},
+			"given array limit in try statement": {
+				text:          `let r; try { [1, 2, 3, 4]; } catch (e) { r = e; } r;`,
+				props:         Props{MaxArrayLength: 3},
+				expectedError: ErrArrayLimitExceeded,
+			},
+			"given record exceeding limit": {
+				text:          `({ a: 1, b: 2, c: 3 });`,
+				props:         Props{MaxArrayLength: 2},
+				expectedError: ErrArrayLimitExceeded,
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, context.Background(), tc)
+			})
+		}
+	})
+	t.Run("Context", func(t *testing.T) {
+		t.Run("given cancelled context", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			program, _ := parser.New(parser.Props{Text: `1;`}).Run()
+			value, err := New(Props{}).Run(ctx, program)
+			assert.Nil(t, value)
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+		t.Run("given deadline exceeded", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+			defer cancel()
+			<-ctx.Done()
+
+			program, _ := parser.New(parser.Props{Text: strings.Repeat(`1 + 1;`, 10)}).Run()
+			value, err := New(Props{}).Run(ctx, program)
+			assert.Nil(t, value)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	})
+	t.Run("given host functions, call them with script callbacks", func(t *testing.T) {
+		filter := HostFunction(func(e *Evaluator, args []interface{}) (interface{}, error) {
+			kept := make([]interface{}, 0)
+			for _, item := range args[0].([]interface{}) {
+				keep, err := e.Call(args[1], []interface{}{item})
+				if err != nil {
+					return nil, err
+				}
+				if keep == true {
+					kept = append(kept, item)
+				}
+			}
+			return kept, nil
+		})
+		items := []interface{}{
+			map[string]interface{}{"name": "revan", "active": true},
+			map[string]interface{}{"name": "malak", "active": false},
+		}
+		program, _ := parser.New(parser.Props{Text: `filter(items, x => x.active);`}).Run()
+
+		value, err := New(Props{Globals: map[string]interface{}{"filter": filter, "items": items}}).Run(context.Background(), program)
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{items[0]}, value)
+	})
+	t.Run("given assignment to global, return error", func(t *testing.T) {
+		program, _ := parser.New(parser.Props{Text: `let x = items; items = 1;`}).Run()
+		_, err := New(Props{Globals: map[string]interface{}{"items": 1}}).Run(context.Background(), program)
+		assert.EqualError(t, err, "assignment to constant variable: items")
+	})
+	t.Run("given globals persisting across runs", func(t *testing.T) {
+		evaluator := New(Props{})
+		first, _ := parser.New(parser.Props{Text: `let x = 40;`}).Run()
+		second, _ := parser.New(parser.Props{Text: `x + 2;`}).Run()
+
+		_, err := evaluator.Run(context.Background(), first)
+		assert.NoError(t, err)
+		value, err := evaluator.Run(context.Background(), second)
+		assert.Equal(t, 42, value)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRunModule(t *testing.T) {
+	tests := map[string]struct {
+		modules       map[string]string
+		expectedValue interface{}
+		expectedError error
+	}{
+		"given imports, bind the exported values": {
+			modules: map[string]string{
+				"main.rd": `import { a, b as c } from "./lib"; a + c;`,
+				"lib.rd":  `export let a = 40; export const b = 2;`,
+			},
+			expectedValue: 42,
+		},
+		"given shared dependency, share its exports": {
+			modules: map[string]string{
+				"main.rd":  `import { a } from "./a"; import { b } from "./b"; a + b;`,
+				"a.rd":     `import { count } from "./count"; export let a = count;`,
+				"b.rd":     `import { count } from "./count"; export let b = count;`,
+				"count.rd": `import { start } from "./start"; export var count = start + 1;`,
+				"start.rd": `export let start = 0;`,
+			},
+			expectedValue: 2,
+		},
+		"given assignment to import": {
+			modules: map[string]string{
+				"main.rd": `import { a } from "./lib"; a = 2;`,
+				"lib.rd":  `export let a = 1;`,
+			},
+			expectedError: errors.New("assignment to constant variable: a"),
+		},
+		"given module variables, keep them out of importers": {
+			modules: map[string]string{
+				"main.rd": `import { a } from "./lib"; hidden;`,
+				"lib.rd":  `let hidden = 1; export let a = hidden;`,
+			},
+			expectedError: errors.New("hidden is not defined"),
+		},
+		"given error in dependency": {
+			modules: map[string]string{
+				"main.rd": `import { a } from "./lib"; a;`,
+				"lib.rd":  `export let a = 1; throw "broken";`,
+			},
+			expectedError: &Exception{Value: "broken"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m, err := module.New(module.Props{Loader: module.NewMemoryLoader(tc.modules)}).Run("main.rd")
+			assert.NoError(t, err)
+
+			value, err := New(Props{}).RunModule(context.Background(), m)
+			assert.Equal(t, tc.expectedValue, value)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+
+	t.Run("given import in script, return error", func(t *testing.T) {
+		program, _ := parser.New(parser.Props{Text: `import { a } from "./lib";`}).Run()
+		_, err := New(Props{}).Run(context.Background(), program)
+		assert.EqualError(t, err, "import declarations may only appear in modules")
+	})
+}
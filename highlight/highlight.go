@@ -0,0 +1,213 @@
+package highlight
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/parser/tokenizer"
+	"github.com/dlanell/go-rdparser/queryparser/querytokenizer"
+)
+
+type Highlighter struct {
+	format   string
+	language string
+}
+
+type Props struct {
+	Format   string
+	Language string
+}
+
+const (
+	ANSI string = "ansi"
+	HTML        = "html"
+)
+
+const (
+	Script string = "script"
+	Query         = "query"
+)
+
+// Token categories, rendered as the CSS class ClassPrefix + category in HTML.
+const (
+	Keyword     string = "keyword"
+	Constant           = "constant"
+	Number             = "number"
+	String             = "string"
+	Identifier         = "identifier"
+	Operator           = "operator"
+	Punctuation        = "punctuation"
+	Comment            = "comment"
+	Invalid            = "invalid"
+	Plain              = ""
+)
+
+const ClassPrefix = "rd-"
+
+var ansiColors = map[string]string{
+	Keyword:  "\x1b[35m",
+	Constant: "\x1b[33m",
+	Number:   "\x1b[33m",
+	String:   "\x1b[32m",
+	Operator: "\x1b[36m",
+	Comment:  "\x1b[90m",
+	Invalid:  "\x1b[31m",
+}
+
+const ansiReset = "\x1b[0m"
+
+var scriptCategories = map[string]string{
+	tokenizer.NumberToken:            Number,
+	tokenizer.StringToken:            String,
+	tokenizer.RegExpToken:            String,
+	tokenizer.SemiColonToken:         Punctuation,
+	tokenizer.OpenCurlyBrace:         Punctuation,
+	tokenizer.CloseCurlyBrace:        Punctuation,
+	tokenizer.OpenParentheses:        Punctuation,
+	tokenizer.CloseParentheses:       Punctuation,
+	tokenizer.OpenBracket:            Punctuation,
+	tokenizer.CloseBracket:           Punctuation,
+	tokenizer.Comma:                  Punctuation,
+	tokenizer.Dot:                    Punctuation,
+	tokenizer.Arrow:                  Operator,
+	tokenizer.Spread:                 Operator,
+	tokenizer.OptionalChaining:       Punctuation,
+	tokenizer.NullishCoalescing:      Operator,
+	tokenizer.QuestionMark:           Operator,
+	tokenizer.Colon:                  Operator,
+	tokenizer.AdditiveOperator:       Operator,
+	tokenizer.MultiplicativeOperator: Operator,
+	tokenizer.ExponentOperator:       Operator,
+	tokenizer.UpdateOperator:         Operator,
+	tokenizer.ShiftOperator:          Operator,
+	tokenizer.BitwiseAndOperator:     Operator,
+	tokenizer.BitwiseOrOperator:      Operator,
+	tokenizer.BitwiseXorOperator:     Operator,
+	tokenizer.BitwiseNotOperator:     Operator,
+	tokenizer.RelationalOperator:     Operator,
+	tokenizer.LogicalAnd:             Operator,
+	tokenizer.LogicalOr:              Operator,
+	tokenizer.EqualityOperator:       Operator,
+	tokenizer.SimpleAssignment:       Operator,
+	tokenizer.ComplexAssignment:      Operator,
+	tokenizer.Identifier:             Identifier,
+	tokenizer.LetKeyword:             Keyword,
+	tokenizer.ConstKeyword:           Keyword,
+	tokenizer.VarKeyword:             Keyword,
+	tokenizer.IfKeyword:              Keyword,
+	tokenizer.ElseKeyword:            Keyword,
+	tokenizer.SwitchKeyword:          Keyword,
+	tokenizer.CaseKeyword:            Keyword,
+	tokenizer.DefaultKeyword:         Keyword,
+	tokenizer.BreakKeyword:           Keyword,
+	tokenizer.ThrowKeyword:           Keyword,
+	tokenizer.TryKeyword:             Keyword,
+	tokenizer.CatchKeyword:           Keyword,
+	tokenizer.FinallyKeyword:         Keyword,
+	tokenizer.ImportKeyword:          Keyword,
+	tokenizer.ExportKeyword:          Keyword,
+	tokenizer.FunctionKeyword:        Keyword,
+	tokenizer.ReturnKeyword:          Keyword,
+	tokenizer.TrueKeyword:            Constant,
+	tokenizer.FalseKeyword:           Constant,
+	tokenizer.NullKeyword:            Constant,
+	tokenizer.CommentToken:           Comment,
+	tokenizer.WhitespaceToken:        Plain,
+}
+
+var queryCategories = map[string]string{
+	querytokenizer.NumberToken:        Number,
+	querytokenizer.StringToken:        String,
+	querytokenizer.BooleanToken:       Constant,
+	querytokenizer.DateToken:          Constant,
+	querytokenizer.OpenParentheses:    Punctuation,
+	querytokenizer.CloseParentheses:   Punctuation,
+	querytokenizer.Comma:              Punctuation,
+	querytokenizer.RelationalOperator: Keyword,
+	querytokenizer.LogicalOperator:    Keyword,
+	querytokenizer.Identifier:         Identifier,
+	querytokenizer.WhitespaceToken:    Plain,
+}
+
+func New(props Props) *Highlighter {
+	format := props.Format
+	if format == "" {
+		format = ANSI
+	}
+	language := props.Language
+	if language == "" {
+		language = Script
+	}
+	return &Highlighter{
+		format:   format,
+		language: language,
+	}
+}
+
+// Run renders the text with every token colored by its category. Text the tokenizer
+// cannot read is rendered as Invalid so partial input still highlights.
+func (h *Highlighter) Run(text string) (string, error) {
+	if h.format != ANSI && h.format != HTML {
+		return "", fmt.Errorf("unsupported format: %s", h.format)
+	}
+
+	builder := &strings.Builder{}
+	var end int
+	switch h.language {
+	case Script:
+		end = h.script(builder, text)
+	case Query:
+		end = h.query(builder, text)
+	default:
+		return "", fmt.Errorf("unsupported language: %s", h.language)
+	}
+	if end < len(text) {
+		h.write(builder, Invalid, text[end:])
+	}
+	return builder.String(), nil
+}
+
+func (h *Highlighter) script(builder *strings.Builder, text string) int {
+	t := tokenizer.New(tokenizer.Props{Text: text, EmitTrivia: true})
+	end := 0
+	for {
+		token, err := t.GetNextToken()
+		if err != nil {
+			return end
+		}
+		h.write(builder, scriptCategories[token.TokenType], token.Value)
+		end += len(token.Value)
+	}
+}
+
+func (h *Highlighter) query(builder *strings.Builder, text string) int {
+	t := querytokenizer.New(querytokenizer.Props{Text: text, EmitTrivia: true})
+	end := 0
+	for {
+		token, err := t.GetNextToken()
+		if err != nil {
+			return end
+		}
+		h.write(builder, queryCategories[token.TokenType], token.Value)
+		end += len(token.Value)
+	}
+}
+
+func (h *Highlighter) write(builder *strings.Builder, category string, value string) {
+	if h.format == HTML {
+		if category == Plain {
+			builder.WriteString(html.EscapeString(value))
+			return
+		}
+		fmt.Fprintf(builder, `<span class="%s%s">%s</span>`, ClassPrefix, category, html.EscapeString(value))
+		return
+	}
+
+	color, ok := ansiColors[category]
+	if !ok {
+		builder.WriteString(value)
+		return
+	}
+	builder.WriteString(color + value + ansiReset)
+}
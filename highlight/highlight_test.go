@@ -0,0 +1,80 @@
+package highlight
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("given New with empty Props, return ANSI script Highlighter", func(t *testing.T) {
+		assert.Equal(t, &Highlighter{format: ANSI, language: Script}, New(Props{}))
+	})
+}
+
+type test struct {
+	props          Props
+	text           string
+	expectedOutput string
+	expectedError  error
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given script, return ANSI colored tokens": {
+			text:           `let x = 42;`,
+			expectedOutput: "\x1b[35mlet\x1b[0m x \x1b[36m=\x1b[0m \x1b[33m42\x1b[0m;",
+		},
+		"given script with comment and string, return ANSI colored tokens": {
+			text:           "// hi\nx + 'a';",
+			expectedOutput: "\x1b[90m// hi\x1b[0m\nx \x1b[36m+\x1b[0m \x1b[32m'a'\x1b[0m;",
+		},
+		"given script with keyword constants, return HTML spans": {
+			props:          Props{Format: HTML},
+			text:           `if (true) null;`,
+			expectedOutput: `<span class="rd-keyword">if</span> <span class="rd-punctuation">(</span><span class="rd-constant">true</span><span class="rd-punctuation">)</span> <span class="rd-constant">null</span><span class="rd-punctuation">;</span>`,
+		},
+		"given script with markup characters, return escaped HTML": {
+			props:          Props{Format: HTML},
+			text:           `x < "<b>";`,
+			expectedOutput: `<span class="rd-identifier">x</span> <span class="rd-operator">&lt;</span> <span class="rd-string">&#34;&lt;b&gt;&#34;</span><span class="rd-punctuation">;</span>`,
+		},
+		"given script with unreadable remainder, render it invalid": {
+			text:           `x @ y`,
+			expectedOutput: "x \x1b[31m@ y\x1b[0m",
+		},
+		"given query, return ANSI colored tokens": {
+			props:          Props{Language: Query},
+			text:           `and(eq(name, "revan"), gt(cores, 4))`,
+			expectedOutput: "\x1b[35mand\x1b[0m(\x1b[35meq\x1b[0m(name, \x1b[32m\"revan\"\x1b[0m), \x1b[35mgt\x1b[0m(cores, \x1b[33m4\x1b[0m))",
+		},
+		"given query, return HTML spans": {
+			props:          Props{Format: HTML, Language: Query},
+			text:           `eq(done, true)`,
+			expectedOutput: `<span class="rd-keyword">eq</span><span class="rd-punctuation">(</span><span class="rd-identifier">done</span><span class="rd-punctuation">,</span> <span class="rd-constant">true</span><span class="rd-punctuation">)</span>`,
+		},
+		"given empty text, return empty output": {
+			text:           ``,
+			expectedOutput: "",
+		},
+		"given unsupported format, return error": {
+			props:         Props{Format: "latex"},
+			text:          `x;`,
+			expectedError: errors.New("unsupported format: latex"),
+		},
+		"given unsupported language, return error": {
+			props:         Props{Language: "sql"},
+			text:          `x;`,
+			expectedError: errors.New("unsupported language: sql"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := New(tc.props).Run(tc.text)
+			assert.Equal(t, tc.expectedOutput, output)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// File is the diagnostics of one linted file.
+type File struct {
+	Name        string
+	Diagnostics []*Diagnostic
+}
+
+// Text renders one line per diagnostic, `name:line:column: severity: message (rule)`.
+func Text(files []*File) string {
+	builder := &strings.Builder{}
+	for _, file := range files {
+		for _, diagnostic := range file.Diagnostics {
+			fmt.Fprintf(builder, "%s:%d:%d: %s: %s (%s)\n", file.Name, diagnostic.Start.Line, diagnostic.Start.Column,
+				diagnostic.Severity, diagnostic.Message, diagnostic.Rule)
+		}
+	}
+	return builder.String()
+}
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "rdparser"
+)
+
+// SARIF is a Static Analysis Results Interchange Format 2.1.0 log, the subset code
+// scanning services read to annotate the lines of a change.
+type SARIF struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// NewSARIF returns the log of a single run over the files, describing every registered
+// rule.
+func NewSARIF(files []*File) *SARIF {
+	rules := make([]sarifRule, 0)
+	indexes := map[string]int{}
+	for index, rule := range Rules() {
+		indexes[rule.Name] = index
+		rules = append(rules, sarifRule{
+			ID:                   rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	results := make([]sarifResult, 0)
+	for _, file := range files {
+		for _, diagnostic := range file.Diagnostics {
+			results = append(results, sarifResult{
+				RuleID:    diagnostic.Rule,
+				RuleIndex: indexes[diagnostic.Rule],
+				Level:     sarifLevel(diagnostic.Severity),
+				Message:   sarifMessage{Text: diagnostic.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(file.Name)},
+						Region: sarifRegion{
+							StartLine:   diagnostic.Start.Line,
+							StartColumn: diagnostic.Start.Column,
+							EndLine:     diagnostic.End.Line,
+							EndColumn:   diagnostic.End.Column,
+						},
+					},
+				}},
+			})
+		}
+	}
+
+	return &SARIF{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: toolName, Rules: rules}},
+			Results: results,
+		}},
+	}
+}
+
+// sarifLevel maps a severity to a SARIF level, the two share their names but for
+// SeverityOff.
+func sarifLevel(severity Severity) string {
+	if severity == SeverityOff {
+		return "none"
+	}
+	return string(severity)
+}
@@ -0,0 +1,197 @@
+package lint
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/dlanell/go-rdparser/parser"
+)
+
+// Linter
+// rules are the enabled rules in name order with the severity they report at.
+type Linter struct {
+	rules []enabled
+}
+
+// Props
+// Severities overrides the default severity of the rules named, SeverityOff disables
+// a rule. Names no rule is registered under are ignored.
+type Props struct {
+	Severities map[string]Severity
+}
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning          = "warning"
+	SeverityOff              = "off"
+)
+
+// Check is the function of a rule, it is called with every node of the program in
+// source order, parents before their children, and reports through the context.
+type Check func(c *Context, node *parser.Node)
+
+// Rule is a check registered by name, Severity is the one it reports at unless
+// configured otherwise.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       Check
+}
+
+// Diagnostic is a problem a rule found, Start and End are the positions of Loc.
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	Message  string
+	Loc      parser.Location
+	Start    Position
+	End      Position
+}
+
+// Position is a one based line and column, the column counts characters.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Context is what a check knows of the program, ancestors are the nodes enclosing the
+// current one, outermost first.
+type Context struct {
+	text        string
+	program     *parser.Program
+	ancestors   []*parser.Node
+	rule        enabled
+	diagnostics []*Diagnostic
+}
+
+type enabled struct {
+	*Rule
+	severity Severity
+}
+
+var registry = map[string]*Rule{}
+
+// Register adds the rule, replacing the one registered under the same name.
+func Register(rule *Rule) {
+	registry[rule.Name] = rule
+}
+
+// Lookup returns the rule registered under the name, nil when there is none.
+func Lookup(name string) *Rule {
+	return registry[name]
+}
+
+// Rules returns every registered rule in name order.
+func Rules() []*Rule {
+	rules := make([]*Rule, 0, len(registry))
+	for _, rule := range registry {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+	return rules
+}
+
+func New(props Props) *Linter {
+	rules := make([]enabled, 0)
+	for _, rule := range Rules() {
+		severity := rule.Severity
+		if configured, ok := props.Severities[rule.Name]; ok {
+			severity = configured
+		}
+		if severity != SeverityOff {
+			rules = append(rules, enabled{Rule: rule, severity: severity})
+		}
+	}
+	return &Linter{rules: rules}
+}
+
+// Run checks the script with every enabled rule and returns the diagnostics in source
+// order.
+func (l *Linter) Run(text string) ([]*Diagnostic, error) {
+	program, err := parser.New(parser.Props{Text: text, Locations: true}).Run()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Context{text: text, program: program, diagnostics: make([]*Diagnostic, 0)}
+	for _, statement := range program.Body {
+		l.visit(c, statement)
+	}
+
+	sort.SliceStable(c.diagnostics, func(i, j int) bool {
+		return c.diagnostics[i].Loc.Start < c.diagnostics[j].Loc.Start
+	})
+	for _, diagnostic := range c.diagnostics {
+		diagnostic.Start = position(text, diagnostic.Loc.Start)
+		diagnostic.End = position(text, diagnostic.Loc.End)
+	}
+	return c.diagnostics, nil
+}
+
+func (l *Linter) visit(c *Context, node *parser.Node) {
+	for _, rule := range l.rules {
+		c.rule = rule
+		rule.Check(c, node)
+	}
+	c.ancestors = append(c.ancestors, node)
+	for _, child := range parser.Children(node) {
+		l.visit(c, child)
+	}
+	c.ancestors = c.ancestors[:len(c.ancestors)-1]
+}
+
+// Program returns the program being checked.
+func (c *Context) Program() *parser.Program {
+	return c.program
+}
+
+// Ancestors returns the nodes enclosing the current one, outermost first.
+func (c *Context) Ancestors() []*parser.Node {
+	return c.ancestors
+}
+
+// Parent returns the node directly enclosing the current one, nil for a top-level
+// statement.
+func (c *Context) Parent() *parser.Node {
+	if len(c.ancestors) == 0 {
+		return nil
+	}
+	return c.ancestors[len(c.ancestors)-1]
+}
+
+// Source returns the text of the node.
+func (c *Context) Source(node *parser.Node) string {
+	return c.text[node.Loc.Start:node.Loc.End]
+}
+
+// Report adds a diagnostic of the current rule at the node.
+func (c *Context) Report(node *parser.Node, message string) {
+	c.ReportAt(*node.Loc, message)
+}
+
+// ReportAt adds a diagnostic of the current rule at the source range, for checks
+// analyzing the program as a whole.
+func (c *Context) ReportAt(loc parser.Location, message string) {
+	c.diagnostics = append(c.diagnostics, &Diagnostic{
+		Rule:     c.rule.Name,
+		Severity: c.rule.severity,
+		Message:  message,
+		Loc:      loc,
+	})
+}
+
+func position(text string, offset int) Position {
+	line, start := 1, 0
+	for index := 0; index < offset; index++ {
+		if text[index] == '\n' {
+			line++
+			start = index + 1
+		}
+	}
+	return Position{Line: line, Column: utf8.RuneCountInString(text[start:offset]) + 1}
+}
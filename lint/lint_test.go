@@ -0,0 +1,204 @@
+package lint
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+// noDataFlow leaves out the data-flow findings of scripts testing other rules.
+var noDataFlow = map[string]Severity{"dataflow": SeverityOff}
+
+type test struct {
+	text                string
+	severities          map[string]Severity
+	expectedDiagnostics []*Diagnostic
+	expectedError       error
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given clean script": {
+			text:                `let x = f(); if (x === null) { g(x); } else { x = 1; }`,
+			severities:          noDataFlow,
+			expectedDiagnostics: []*Diagnostic{},
+		},
+		"given syntax error": {
+			text:          `let = 1;`,
+			expectedError: errors.New("Unexpected token: =, expected: IDENTIFIER\n"),
+		},
+		"given assignment in conditions": {
+			text:       `if (x = f()) { g(); } let y = (z = 1) ? 2 : 3;`,
+			severities: noDataFlow,
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "no-cond-assign", Severity: SeverityError, Message: "unexpected assignment in condition", Loc: parser.Location{Start: 4, End: 11},
+					Start: Position{Line: 1, Column: 5}, End: Position{Line: 1, Column: 12}},
+				{Rule: "no-cond-assign", Severity: SeverityError, Message: "unexpected assignment in condition", Loc: parser.Location{Start: 31, End: 36},
+					Start: Position{Line: 1, Column: 32}, End: Position{Line: 1, Column: 37}},
+			},
+		},
+		"given comparisons with null": {
+			text: "f(x == null);\nf(null != x);\nf(x === null);",
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "no-eq-null", Severity: SeverityWarning, Message: "comparison with null using ==, use ===", Loc: parser.Location{Start: 2, End: 11},
+					Start: Position{Line: 1, Column: 3}, End: Position{Line: 1, Column: 12}},
+				{Rule: "no-eq-null", Severity: SeverityWarning, Message: "comparison with null using !=, use !==", Loc: parser.Location{Start: 16, End: 25},
+					Start: Position{Line: 2, Column: 3}, End: Position{Line: 2, Column: 12}},
+			},
+		},
+		"given constant conditions": {
+			text:       `if (-1) { f(); } let y = [] ? 1 : 2; if (a && 1) { f(); }`,
+			severities: noDataFlow,
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "no-constant-condition", Severity: SeverityError, Message: "constant condition", Loc: parser.Location{Start: 4, End: 6},
+					Start: Position{Line: 1, Column: 5}, End: Position{Line: 1, Column: 7}},
+				{Rule: "no-constant-condition", Severity: SeverityError, Message: "constant condition", Loc: parser.Location{Start: 25, End: 27},
+					Start: Position{Line: 1, Column: 26}, End: Position{Line: 1, Column: 28}},
+			},
+		},
+		"given empty blocks": {
+			text:       "if (a) {}\nswitch (a) {}\ntry { f(); } catch { /* ignored */ }\nlet g = () => {};",
+			severities: noDataFlow,
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "no-empty", Severity: SeverityWarning, Message: "empty block statement", Loc: parser.Location{Start: 7, End: 9},
+					Start: Position{Line: 1, Column: 8}, End: Position{Line: 1, Column: 10}},
+				{Rule: "no-empty", Severity: SeverityWarning, Message: "empty switch statement", Loc: parser.Location{Start: 10, End: 23},
+					Start: Position{Line: 2, Column: 1}, End: Position{Line: 2, Column: 14}},
+			},
+		},
+		"given shadowed variables": {
+			text:       `let x = 1; let f = (x) => { let y; { let y; var z; } }; try { f(); } catch (x) { f(); }`,
+			severities: noDataFlow,
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "no-shadow", Severity: SeverityWarning, Message: "x is already declared in an outer scope", Loc: parser.Location{Start: 20, End: 21},
+					Start: Position{Line: 1, Column: 21}, End: Position{Line: 1, Column: 22}},
+				{Rule: "no-shadow", Severity: SeverityWarning, Message: "y is already declared in an outer scope", Loc: parser.Location{Start: 41, End: 42},
+					Start: Position{Line: 1, Column: 42}, End: Position{Line: 1, Column: 43}},
+				{Rule: "no-shadow", Severity: SeverityWarning, Message: "x is already declared in an outer scope", Loc: parser.Location{Start: 76, End: 77},
+					Start: Position{Line: 1, Column: 77}, End: Position{Line: 1, Column: 78}},
+			},
+		},
+		"given parameters redeclared in the function body": {
+			text:                `let f = (a) => { var a; { var b; } }; let g = () => { let b; };`,
+			severities:          noDataFlow,
+			expectedDiagnostics: []*Diagnostic{},
+		},
+		"given self assignments": {
+			text: `x = x; x = y; x += x;`,
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "no-self-assign", Severity: SeverityError, Message: "x is assigned to itself", Loc: parser.Location{Start: 0, End: 5},
+					Start: Position{Line: 1, Column: 1}, End: Position{Line: 1, Column: 6}},
+			},
+		},
+		"given data-flow problems": {
+			text:       "let x; if (1) { x = 1; } x;\nlet y = 1; y = 2; f(y);",
+			severities: map[string]Severity{"no-constant-condition": SeverityOff},
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "dataflow", Severity: SeverityWarning, Message: "x is used before being assigned", Loc: parser.Location{Start: 25, End: 26},
+					Start: Position{Line: 1, Column: 26}, End: Position{Line: 1, Column: 27}},
+				{Rule: "dataflow", Severity: SeverityWarning, Message: "value assigned to y is never read", Loc: parser.Location{Start: 32, End: 33},
+					Start: Position{Line: 2, Column: 5}, End: Position{Line: 2, Column: 6}},
+			},
+		},
+		"given type errors": {
+			text:       "let x: number = 1;\nx = \"a\" * 2;",
+			severities: noDataFlow,
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "type-check", Severity: SeverityWarning, Message: "invalid operands for *: string, number", Loc: parser.Location{Start: 23, End: 30},
+					Start: Position{Line: 2, Column: 5}, End: Position{Line: 2, Column: 12}},
+			},
+		},
+		"given configured severities": {
+			text:       "if (x = 1) {}",
+			severities: map[string]Severity{"no-cond-assign": SeverityWarning, "no-empty": SeverityOff},
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "no-cond-assign", Severity: SeverityWarning, Message: "unexpected assignment in condition", Loc: parser.Location{Start: 4, End: 9},
+					Start: Position{Line: 1, Column: 5}, End: Position{Line: 1, Column: 10}},
+			},
+		},
+		"given positions after multibyte characters": {
+			text: "f(\"é\");\n  x = x;",
+			expectedDiagnostics: []*Diagnostic{
+				{Rule: "no-self-assign", Severity: SeverityError, Message: "x is assigned to itself", Loc: parser.Location{Start: 11, End: 16},
+					Start: Position{Line: 2, Column: 3}, End: Position{Line: 2, Column: 8}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			diagnostics, err := New(Props{Severities: tc.severities}).Run(tc.text)
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDiagnostics, diagnostics)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(&Rule{
+		Name:        "no-debugger-call",
+		Description: "disallow calling debugger",
+		Severity:    SeverityError,
+		Check: func(c *Context, node *parser.Node) {
+			if node.NodeType == parser.CallExpression && c.Source(node.Body.(*parser.CallExpressionValue).Callee) == "debugger" {
+				c.Report(node, "unexpected debugger call")
+			}
+		},
+	})
+	defer delete(registry, "no-debugger-call")
+
+	diagnostics, err := New(Props{}).Run(`debugger();`)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Diagnostic{
+		{Rule: "no-debugger-call", Severity: SeverityError, Message: "unexpected debugger call", Loc: parser.Location{Start: 0, End: 10},
+			Start: Position{Line: 1, Column: 1}, End: Position{Line: 1, Column: 11}},
+	}, diagnostics)
+	assert.Equal(t, "no-debugger-call", Lookup("no-debugger-call").Name)
+}
+
+func TestText(t *testing.T) {
+	files := []*File{
+		{Name: "a.js", Diagnostics: []*Diagnostic{
+			{Rule: "no-empty", Severity: SeverityWarning, Message: "empty block statement", Start: Position{Line: 2, Column: 8}},
+		}},
+		{Name: "b.js", Diagnostics: []*Diagnostic{}},
+	}
+	assert.Equal(t, "a.js:2:8: warning: empty block statement (no-empty)\n", Text(files))
+}
+
+func TestNewSARIF(t *testing.T) {
+	files := []*File{
+		{Name: "src/a.js", Diagnostics: []*Diagnostic{
+			{Rule: "no-self-assign", Severity: SeverityError, Message: "x is assigned to itself",
+				Start: Position{Line: 1, Column: 1}, End: Position{Line: 1, Column: 6}},
+		}},
+	}
+	log := NewSARIF(files)
+
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+	driver := log.Runs[0].Tool.Driver
+	assert.Equal(t, "rdparser", driver.Name)
+	assert.Len(t, driver.Rules, len(Rules()))
+	assert.Equal(t, []sarifResult{
+		{
+			RuleID:    "no-self-assign",
+			RuleIndex: 5,
+			Level:     "error",
+			Message:   sarifMessage{Text: "x is assigned to itself"},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: "src/a.js"},
+					Region:           sarifRegion{StartLine: 1, StartColumn: 1, EndLine: 1, EndColumn: 6},
+				},
+			}},
+		},
+	}, log.Runs[0].Results)
+	assert.Equal(t, "no-self-assign", driver.Rules[5].ID)
+}
@@ -0,0 +1,313 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/dataflow"
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/dlanell/go-rdparser/types"
+)
+
+func init() {
+	Register(&Rule{
+		Name:        "no-cond-assign",
+		Description: "disallow assignments in the test of if statements and conditional expressions",
+		Severity:    SeverityError,
+		Check:       noCondAssign,
+	})
+	Register(&Rule{
+		Name:        "no-eq-null",
+		Description: "disallow comparing with null using == and !=",
+		Severity:    SeverityWarning,
+		Check:       noEqNull,
+	})
+	Register(&Rule{
+		Name:        "no-constant-condition",
+		Description: "disallow constant tests in if statements and conditional expressions",
+		Severity:    SeverityError,
+		Check:       noConstantCondition,
+	})
+	Register(&Rule{
+		Name:        "no-empty",
+		Description: "disallow empty blocks and switch statements without a comment",
+		Severity:    SeverityWarning,
+		Check:       noEmpty,
+	})
+	Register(&Rule{
+		Name:        "no-shadow",
+		Description: "disallow declaring a variable already declared in an outer scope",
+		Severity:    SeverityWarning,
+		Check:       noShadow,
+	})
+	Register(&Rule{
+		Name:        "dataflow",
+		Description: "disallow reading variables before they are assigned and assigning values never read",
+		Severity:    SeverityWarning,
+		Check:       dataFlow,
+	})
+	Register(&Rule{
+		Name:        "type-check",
+		Description: "disallow values of the wrong type for operators, annotations and calls",
+		Severity:    SeverityWarning,
+		Check:       typeCheck,
+	})
+	Register(&Rule{
+		Name:        "no-self-assign",
+		Description: "disallow assigning a variable to itself",
+		Severity:    SeverityError,
+		Check:       noSelfAssign,
+	})
+}
+
+func noCondAssign(c *Context, node *parser.Node) {
+	if test := conditionTest(node); test != nil && test.NodeType == parser.AssignmentExpression {
+		c.Report(test, "unexpected assignment in condition")
+	}
+}
+
+func noEqNull(c *Context, node *parser.Node) {
+	if node.NodeType != parser.BinaryExpression {
+		return
+	}
+	value := node.Body.(*parser.BinaryExpressionNode)
+	if value.Operator != "==" && value.Operator != "!=" {
+		return
+	}
+	if value.Left.(*parser.Node).NodeType == parser.NullLiteral || value.Right.(*parser.Node).NodeType == parser.NullLiteral {
+		c.Report(node, fmt.Sprintf("comparison with null using %s, use %s=", value.Operator, value.Operator))
+	}
+}
+
+func noConstantCondition(c *Context, node *parser.Node) {
+	if test := conditionTest(node); test != nil && isConstant(test) {
+		c.Report(test, "constant condition")
+	}
+}
+
+// noEmpty allows the empty body of a function and blocks holding only a comment.
+func noEmpty(c *Context, node *parser.Node) {
+	switch node.NodeType {
+	case parser.BlockStatement:
+		if len(node.Body.([]*parser.Node)) > 0 || isFunction(c.Parent()) || hasComment(c, node, node.Loc.Start) {
+			return
+		}
+		c.Report(node, "empty block statement")
+	case parser.SwitchStatement:
+		value := node.Body.(*parser.SwitchStatementValue)
+		if len(value.Cases) == 0 && !hasComment(c, node, value.Discriminant.Loc.End) {
+			c.Report(node, "empty switch statement")
+		}
+	}
+}
+
+// noShadow reports the declarations of a name an enclosing scope declares, a scope
+// being the program, a function, a block, a switch statement or a catch clause.
+func noShadow(c *Context, node *parser.Node) {
+	ancestors := c.Ancestors()
+	var identifiers []*parser.Node
+	// outer is the number of ancestors enclosing the scope of the declaration
+	outer := len(ancestors)
+
+	switch node.NodeType {
+	case parser.VariableStatement:
+		for _, declaration := range parser.Children(node) {
+			identifiers = append(identifiers, parser.BindingIdentifiers(declaration.Body.(*parser.VariableDeclarationValue).Id)...)
+		}
+		outer = -1
+		for index := len(ancestors) - 1; index >= 0 && outer < 0; index-- {
+			switch ancestors[index].NodeType {
+			case parser.FunctionExpression, parser.ArrowFunctionExpression:
+				outer = index
+			case parser.BlockStatement, parser.SwitchStatement:
+				if node.Body.(*parser.VariableStatementValue).Kind == parser.KindVar {
+					continue
+				}
+				outer = index
+				// the body of a function shares the scope of the parameters
+				if index > 0 && isFunction(ancestors[index-1]) {
+					outer = index - 1
+				}
+			}
+		}
+		if outer < 0 {
+			return
+		}
+	case parser.FunctionExpression, parser.ArrowFunctionExpression:
+		for _, param := range node.Body.(*parser.FunctionValue).Params {
+			identifiers = append(identifiers, parser.BindingIdentifiers(param)...)
+		}
+	case parser.CatchClause:
+		if param := node.Body.(*parser.CatchClauseValue).Param; param != nil {
+			identifiers = append(identifiers, param)
+		}
+	default:
+		return
+	}
+
+	for _, identifier := range identifiers {
+		name := identifier.Body.(*parser.StringLiteralValue).Value
+		shadowed := declares(c.Program().Body, name, true)
+		for index := 0; index < outer && !shadowed; index++ {
+			shadowed = scopeDeclares(ancestors[index], name)
+		}
+		if shadowed {
+			c.Report(identifier, fmt.Sprintf("%s is already declared in an outer scope", name))
+		}
+	}
+}
+
+func noSelfAssign(c *Context, node *parser.Node) {
+	if node.NodeType != parser.AssignmentExpression {
+		return
+	}
+	value := node.Body.(*parser.BinaryExpressionNode)
+	left, right := value.Left.(*parser.Node), value.Right.(*parser.Node)
+	if value.Operator == "=" && left.NodeType == parser.Identifier && right.NodeType == parser.Identifier &&
+		left.Body.(*parser.StringLiteralValue).Value == right.Body.(*parser.StringLiteralValue).Value {
+		c.Report(node, fmt.Sprintf("%s is assigned to itself", c.Source(left)))
+	}
+}
+
+func dataFlow(c *Context, node *parser.Node) {
+	if !isFirstStatement(c, node) {
+		return
+	}
+	for _, warning := range dataflow.New(dataflow.Props{}).Run(c.Program()) {
+		c.ReportAt(*warning.Loc, warning.Message)
+	}
+}
+
+func typeCheck(c *Context, node *parser.Node) {
+	if !isFirstStatement(c, node) {
+		return
+	}
+	for _, typeErr := range types.New(types.Props{}).Run(c.Program()) {
+		c.ReportAt(*typeErr.Loc, typeErr.Message)
+	}
+}
+
+// isFirstStatement reports whether the node is the first statement of the program,
+// where rules analyzing the whole program run once.
+func isFirstStatement(c *Context, node *parser.Node) bool {
+	return c.Parent() == nil && node == c.Program().Body[0]
+}
+
+// conditionTest returns the test of an if statement or conditional expression, nil for
+// other nodes.
+func conditionTest(node *parser.Node) *parser.Node {
+	switch node.NodeType {
+	case parser.IfStatement:
+		return node.Body.(*parser.IfStatementValue).Test
+	case parser.ConditionalExpression:
+		return node.Body.(*parser.ConditionalExpressionValue).Test
+	}
+	return nil
+}
+
+// isConstant reports whether the truthiness of the expression is known without
+// evaluating it.
+func isConstant(node *parser.Node) bool {
+	switch node.NodeType {
+	case parser.NumericLiteral, parser.StringLiteral, parser.BooleanLiteral, parser.NullLiteral, parser.RegExpLiteral,
+		parser.ArrayExpression, parser.ObjectExpression, parser.FunctionExpression, parser.ArrowFunctionExpression:
+		return true
+	case parser.UnaryExpression:
+		return isConstant(node.Body.(*parser.UnaryExpressionValue).Argument)
+	case parser.BinaryExpression:
+		value := node.Body.(*parser.BinaryExpressionNode)
+		return isConstant(value.Left.(*parser.Node)) && isConstant(value.Right.(*parser.Node))
+	}
+	return false
+}
+
+// scopeDeclares reports whether the node opens a scope declaring the name.
+func scopeDeclares(node *parser.Node, name string) bool {
+	switch node.NodeType {
+	case parser.BlockStatement:
+		return declares(node.Body.([]*parser.Node), name, false)
+	case parser.SwitchStatement:
+		for _, clause := range node.Body.(*parser.SwitchStatementValue).Cases {
+			if declares(clause.Body.(*parser.SwitchCaseValue).Consequent, name, false) {
+				return true
+			}
+		}
+	case parser.CatchClause:
+		param := node.Body.(*parser.CatchClauseValue).Param
+		return param != nil && param.Body.(*parser.StringLiteralValue).Value == name
+	case parser.FunctionExpression, parser.ArrowFunctionExpression:
+		value := node.Body.(*parser.FunctionValue)
+		targets := append([]*parser.Node{value.Id}, value.Params...)
+		for _, target := range targets {
+			if target != nil && bindsName(target, name) {
+				return true
+			}
+		}
+		return !value.Expression && declaresVar(value.Body.Body.([]*parser.Node), name)
+	}
+	return false
+}
+
+// declares reports whether the statements declare the name with let or const, or with
+// var and import as well when the statements are those of the program.
+func declares(statements []*parser.Node, name string, program bool) bool {
+	for _, statement := range statements {
+		if statement.NodeType == parser.ImportDeclaration && program {
+			for _, specifier := range statement.Body.(*parser.ImportDeclarationValue).Specifiers {
+				if bindsName(specifier.Body.(*parser.ImportSpecifierValue).Local, name) {
+					return true
+				}
+			}
+		}
+		if statement.NodeType == parser.ExportDeclaration {
+			statement = statement.Body.(*parser.Node)
+		}
+		if statement.NodeType == parser.VariableStatement && statement.Body.(*parser.VariableStatementValue).Kind != parser.KindVar {
+			for _, declaration := range parser.Children(statement) {
+				if bindsName(declaration.Body.(*parser.VariableDeclarationValue).Id, name) {
+					return true
+				}
+			}
+		}
+	}
+	return program && declaresVar(statements, name)
+}
+
+// declaresVar reports whether the statements declare the name with var, leaving out
+// nested functions.
+func declaresVar(statements []*parser.Node, name string) bool {
+	found := false
+	for _, statement := range statements {
+		parser.Walk(statement, func(node *parser.Node) bool {
+			if found || isFunction(node) {
+				return false
+			}
+			if node.NodeType == parser.VariableStatement && node.Body.(*parser.VariableStatementValue).Kind == parser.KindVar {
+				for _, declaration := range parser.Children(node) {
+					found = found || bindsName(declaration.Body.(*parser.VariableDeclarationValue).Id, name)
+				}
+			}
+			return true
+		})
+	}
+	return found
+}
+
+func bindsName(target *parser.Node, name string) bool {
+	for _, identifier := range parser.BindingIdentifiers(target) {
+		if identifier.Body.(*parser.StringLiteralValue).Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isFunction(node *parser.Node) bool {
+	return node != nil && (node.NodeType == parser.FunctionExpression || node.NodeType == parser.ArrowFunctionExpression)
+}
+
+// hasComment reports whether there is a comment in the empty braces ending the node,
+// after the offset.
+func hasComment(c *Context, node *parser.Node, offset int) bool {
+	return strings.Contains(c.text[offset:node.Loc.End], "/")
+}
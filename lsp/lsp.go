@@ -0,0 +1,575 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/dlanell/go-rdparser/parser/printer"
+	"github.com/dlanell/go-rdparser/semantic"
+	"github.com/dlanell/go-rdparser/types"
+)
+
+type Server struct {
+	reader    *bufio.Reader
+	writer    io.Writer
+	documents map[string]*document
+	shutdown  bool
+}
+
+type Props struct {
+	In  io.Reader
+	Out io.Writer
+}
+
+type document struct {
+	text         string
+	program      *parser.Program
+	err          error
+	declarations []*declaration
+	references   map[*parser.Node]*declaration
+}
+
+// declaration is a binding introduced by a VariableDeclaration,
+// id is the Identifier node naming it.
+type declaration struct {
+	name string
+	kind string
+	id   *parser.Node
+	node *parser.Node
+}
+
+const source = "rdparser"
+
+// maxContentLength bounds the payload a client may send in one message.
+const maxContentLength = 64 << 20
+
+// parameterKind is the kind of function and catch clause parameters, which are not
+// listed as document symbols.
+const parameterKind = "parameter"
+
+var errExit = errors.New("exit")
+
+func New(props Props) *Server {
+	return &Server{
+		reader:    bufio.NewReader(props.In),
+		writer:    props.Out,
+		documents: map[string]*document{},
+	}
+}
+
+// Run serves requests until the client sends exit or closes the connection.
+func (s *Server) Run() error {
+	for {
+		payload, err := s.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(payload, &req); err != nil {
+			if err := s.respond(nil, nil, &ResponseError{Code: ParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, responseErr := s.handle(req)
+		if responseErr == errExit {
+			return nil
+		}
+		if req.ID == nil {
+			continue
+		}
+
+		var rpcErr *ResponseError
+		if responseErr != nil {
+			if !errors.As(responseErr, &rpcErr) {
+				rpcErr = &ResponseError{Code: RequestFailed, Message: responseErr.Error()}
+			}
+		}
+		if err := s.respond(req.ID, result, rpcErr); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *ResponseError) Error() string {
+	return e.Message
+}
+
+func (s *Server) handle(req request) (interface{}, error) {
+	if s.shutdown && req.Method != "exit" {
+		return nil, &ResponseError{Code: InvalidRequest, Message: "server is shutting down"}
+	}
+
+	switch req.Method {
+	case "initialize":
+		return &InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:           TextDocumentSyncFull,
+				DocumentSymbolProvider:     true,
+				DefinitionProvider:         true,
+				HoverProvider:              true,
+				DocumentFormattingProvider: true,
+			},
+			ServerInfo: ServerInfo{Name: source},
+		}, nil
+	case "initialized", "$/cancelRequest", "$/setTrace":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "exit":
+		return nil, errExit
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decode(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decode(req.Params, &params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		return nil, s.update(params.TextDocument.URI, text)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decode(req.Params, &params); err != nil {
+			return nil, err
+		}
+		delete(s.documents, params.TextDocument.URI)
+		return nil, s.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err := decode(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.documentSymbols(params.TextDocument.URI), nil
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := decode(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(params), nil
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := decode(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(params), nil
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := decode(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.formatting(params.TextDocument.URI)
+	}
+	return nil, &ResponseError{Code: MethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+}
+
+func (s *Server) update(uri string, text string) error {
+	doc := analyze(text)
+	s.documents[uri] = doc
+
+	diagnostics := make([]Diagnostic, 0)
+	if doc.err != nil {
+		loc := parser.Location{}
+		var syntaxErr *parser.SyntaxError
+		if errors.As(doc.err, &syntaxErr) {
+			loc = syntaxErr.Loc
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    toRange(text, loc),
+			Severity: SeverityError,
+			Source:   source,
+			Message:  strings.TrimSpace(doc.err.Error()),
+		})
+	} else {
+		for _, semanticErr := range semantic.New(semantic.Props{}).Run(doc.program) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Range:    toRange(text, *semanticErr.Loc),
+				Severity: SeverityError,
+				Source:   source,
+				Message:  semanticErr.Message,
+			})
+		}
+		for _, typeErr := range types.New(types.Props{}).Run(doc.program) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Range:    toRange(text, *typeErr.Loc),
+				Severity: SeverityWarning,
+				Source:   source,
+				Message:  typeErr.Message,
+			})
+		}
+	}
+
+	return s.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnostics,
+	})
+}
+
+func (s *Server) documentSymbols(uri string) []DocumentSymbol {
+	symbols := make([]DocumentSymbol, 0)
+	doc, ok := s.documents[uri]
+	if !ok {
+		return symbols
+	}
+
+	for _, decl := range doc.declarations {
+		symbols = append(symbols, DocumentSymbol{
+			Name:           decl.name,
+			Kind:           SymbolKindVariable,
+			Range:          toRange(doc.text, *decl.node.Loc),
+			SelectionRange: toRange(doc.text, *decl.id.Loc),
+		})
+	}
+	return symbols
+}
+
+func (s *Server) definition(params TextDocumentPositionParams) *Location {
+	doc, decl, _ := s.resolve(params)
+	if decl == nil {
+		return nil
+	}
+	return &Location{
+		URI:   params.TextDocument.URI,
+		Range: toRange(doc.text, *decl.id.Loc),
+	}
+}
+
+func (s *Server) hover(params TextDocumentPositionParams) *Hover {
+	doc, decl, identifier := s.resolve(params)
+	if decl == nil {
+		return nil
+	}
+
+	if decl.kind == parameterKind {
+		return &Hover{
+			Contents: MarkupContent{Kind: "markdown", Value: "```\n(parameter) " + decl.name + "\n```"},
+			Range:    toRange(doc.text, *identifier.Loc),
+		}
+	}
+
+	signature, err := printer.New(printer.Props{}).Run(&parser.Program{
+		NodeType: parser.ProgramEnum,
+		Body: []*parser.Node{{
+			NodeType: parser.VariableStatement,
+			Body:     &parser.VariableStatementValue{Kind: decl.kind, Declarations: []*parser.Node{decl.node}},
+		}},
+	})
+	if err != nil {
+		signature = decl.kind + " " + decl.name + ";\n"
+	}
+
+	return &Hover{
+		Contents: MarkupContent{
+			Kind:  "markdown",
+			Value: "```\n" + signature + "```",
+		},
+		Range: toRange(doc.text, *identifier.Loc),
+	}
+}
+
+func (s *Server) formatting(uri string) ([]TextEdit, error) {
+	doc, ok := s.documents[uri]
+	if !ok || doc.err != nil {
+		return nil, nil
+	}
+
+	formatted, err := printer.New(printer.Props{}).Run(doc.program)
+	if err != nil {
+		return nil, err
+	}
+	if formatted == doc.text {
+		return []TextEdit{}, nil
+	}
+	return []TextEdit{{
+		Range:   toRange(doc.text, parser.Location{Start: 0, End: len(doc.text)}),
+		NewText: formatted,
+	}}, nil
+}
+
+// resolve finds the identifier at the requested position and the declaration it refers to.
+func (s *Server) resolve(params TextDocumentPositionParams) (*document, *declaration, *parser.Node) {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok || doc.program == nil {
+		return nil, nil, nil
+	}
+
+	offset := toOffset(doc.text, params.Position)
+	var identifier *parser.Node
+	for _, statement := range doc.program.Body {
+		parser.Walk(statement, func(node *parser.Node) bool {
+			if node.Loc == nil || offset < node.Loc.Start || offset > node.Loc.End {
+				return false
+			}
+			if node.NodeType == parser.Identifier {
+				identifier = node
+			}
+			return true
+		})
+	}
+	if identifier == nil {
+		return doc, nil, nil
+	}
+	return doc, doc.references[identifier], identifier
+}
+
+// analyze parses the text and links every identifier to the declaration in scope,
+// blocks open a new scope for let and const, functions and catch clauses one for
+// their parameters, var declarations belong to the scope of their function or the top
+// level, and a declaration is visible from its initializer onwards.
+func analyze(text string) *document {
+	doc := &document{
+		text:         text,
+		declarations: make([]*declaration, 0),
+		references:   map[*parser.Node]*declaration{},
+	}
+	doc.program, doc.err = parser.New(parser.Props{Text: text, Locations: true, Comments: true}).Run()
+	if doc.err != nil {
+		return doc
+	}
+
+	scopes := []map[string]*declaration{{}}
+	functionScope := 0
+	kind := parser.KindLet
+	var visit func(node *parser.Node)
+	// visitDefaults visits the default values of a binding target, its identifiers are
+	// declarations rather than references.
+	var visitDefaults func(target *parser.Node)
+	visitDefaults = func(target *parser.Node) {
+		switch target.NodeType {
+		case parser.AssignmentPattern:
+			value := target.Body.(*parser.AssignmentPatternValue)
+			visitDefaults(value.Left)
+			visit(value.Right)
+		case parser.Property:
+			visitDefaults(target.Body.(*parser.PropertyValue).Value)
+		case parser.ArrayPattern, parser.ObjectPattern, parser.RestElement:
+			for _, child := range parser.Children(target) {
+				visitDefaults(child)
+			}
+		}
+	}
+	visit = func(node *parser.Node) {
+		switch node.NodeType {
+		case parser.BlockStatement:
+			scopes = append(scopes, map[string]*declaration{})
+			for _, child := range parser.Children(node) {
+				visit(child)
+			}
+			scopes = scopes[:len(scopes)-1]
+		case parser.VariableStatement:
+			// a function in an initializer may hold declarations of another kind
+			outer := kind
+			kind = node.Body.(*parser.VariableStatementValue).Kind
+			for _, child := range parser.Children(node) {
+				visit(child)
+			}
+			kind = outer
+		case parser.FunctionExpression, parser.ArrowFunctionExpression:
+			value := node.Body.(*parser.FunctionValue)
+			scopes = append(scopes, map[string]*declaration{})
+			for _, param := range value.Params {
+				for _, id := range parser.BindingIdentifiers(param) {
+					decl := &declaration{
+						name: id.Body.(*parser.StringLiteralValue).Value,
+						kind: parameterKind,
+						id:   id,
+						node: param,
+					}
+					doc.references[id] = decl
+					scopes[len(scopes)-1][decl.name] = decl
+				}
+			}
+			outer := functionScope
+			functionScope = len(scopes) - 1
+			visit(value.Body)
+			functionScope = outer
+			scopes = scopes[:len(scopes)-1]
+		case parser.CatchClause:
+			value := node.Body.(*parser.CatchClauseValue)
+			scopes = append(scopes, map[string]*declaration{})
+			if value.Param != nil {
+				decl := &declaration{
+					name: value.Param.Body.(*parser.StringLiteralValue).Value,
+					kind: parameterKind,
+					id:   value.Param,
+					node: value.Param,
+				}
+				doc.references[value.Param] = decl
+				scopes[len(scopes)-1][decl.name] = decl
+			}
+			visit(value.Body)
+			scopes = scopes[:len(scopes)-1]
+		case parser.LabeledStatement:
+			// labels are not variables
+			visit(node.Body.(*parser.LabeledStatementValue).Body)
+		case parser.BreakStatement:
+		case parser.MemberExpression:
+			value := node.Body.(*parser.MemberExpressionValue)
+			visit(value.Object)
+			if value.Computed {
+				visit(value.Property)
+			}
+		case parser.Property:
+			visit(node.Body.(*parser.PropertyValue).Value)
+		case parser.VariableDeclaration:
+			value := node.Body.(*parser.VariableDeclarationValue)
+			scope := scopes[len(scopes)-1]
+			if kind == parser.KindVar {
+				scope = scopes[functionScope]
+			}
+			for _, id := range parser.BindingIdentifiers(value.Id) {
+				decl := &declaration{
+					name: id.Body.(*parser.StringLiteralValue).Value,
+					kind: kind,
+					id:   id,
+					node: node,
+				}
+				doc.declarations = append(doc.declarations, decl)
+				doc.references[id] = decl
+				scope[decl.name] = decl
+			}
+			visitDefaults(value.Id)
+			if value.Init != nil {
+				visit(value.Init)
+			}
+		case parser.Identifier:
+			name := node.Body.(*parser.StringLiteralValue).Value
+			for index := len(scopes) - 1; index >= 0; index-- {
+				if decl, ok := scopes[index][name]; ok {
+					doc.references[node] = decl
+					break
+				}
+			}
+		default:
+			for _, child := range parser.Children(node) {
+				visit(child)
+			}
+		}
+	}
+	for _, statement := range doc.program.Body {
+		visit(statement)
+	}
+	return doc
+}
+
+// toPosition converts a byte offset into the zero based line and UTF-16 character the protocol uses.
+func toPosition(text string, offset int) Position {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	position := Position{}
+	for _, r := range text[:offset] {
+		if r == '\n' {
+			position.Line++
+			position.Character = 0
+			continue
+		}
+		position.Character += utf16Length(r)
+	}
+	return position
+}
+
+func toOffset(text string, position Position) int {
+	line, character := 0, 0
+	for offset, r := range text {
+		if line == position.Line && character >= position.Character {
+			return offset
+		}
+		if r == '\n' {
+			if line == position.Line {
+				return offset
+			}
+			line++
+			character = 0
+			continue
+		}
+		if line == position.Line {
+			character += utf16Length(r)
+		}
+	}
+	return len(text)
+}
+
+func toRange(text string, loc parser.Location) Range {
+	return Range{Start: toPosition(text, loc.Start), End: toPosition(text, loc.End)}
+}
+
+func utf16Length(r rune) int {
+	if r >= 0x10000 && utf8.ValidRune(r) {
+		return 2
+	}
+	return 1
+}
+
+func decode(params json.RawMessage, value interface{}) error {
+	if err := json.Unmarshal(params, value); err != nil {
+		return &ResponseError{Code: InvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) read() ([]byte, error) {
+	headers, err := textproto.NewReader(s.reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length: %s", headers.Get("Content-Length"))
+	}
+	if length > maxContentLength {
+		return nil, fmt.Errorf("Content-Length exceeds %d bytes: %d", maxContentLength, length)
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(s.reader, payload)
+	return payload, err
+}
+
+func (s *Server) write(message interface{}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.writer, "Content-Length: %d\r\n\r\n%s", len(payload), payload)
+	return err
+}
+
+func (s *Server) respond(id *json.RawMessage, result interface{}, rpcErr *ResponseError) error {
+	res := response{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		res.Result = encoded
+	}
+	return s.write(res)
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return s.write(&notification{JSONRPC: "2.0", Method: method, Params: params})
+}
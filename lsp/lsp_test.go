@@ -0,0 +1,376 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// client speaks JSON-RPC to an in-process server.
+type client struct {
+	t      *testing.T
+	writer io.Writer
+	reader *bufio.Reader
+	nextID int
+	done   chan error
+}
+
+func newClient(t *testing.T) *client {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+
+	c := &client{
+		t:      t,
+		writer: clientOut,
+		reader: bufio.NewReader(clientIn),
+		done:   make(chan error, 1),
+	}
+	go func() {
+		c.done <- New(Props{In: serverIn, Out: serverOut}).Run()
+		serverOut.Close()
+	}()
+	return c
+}
+
+func (c *client) send(message interface{}) {
+	payload, err := json.Marshal(message)
+	assert.NoError(c.t, err)
+	_, err = fmt.Fprintf(c.writer, "Content-Length: %d\r\n\r\n%s", len(payload), payload)
+	assert.NoError(c.t, err)
+}
+
+func (c *client) receive() map[string]interface{} {
+	headers, err := textproto.NewReader(c.reader).ReadMIMEHeader()
+	assert.NoError(c.t, err)
+	length, _ := strconv.Atoi(headers.Get("Content-Length"))
+	payload := make([]byte, length)
+	_, err = io.ReadFull(c.reader, payload)
+	assert.NoError(c.t, err)
+
+	message := map[string]interface{}{}
+	assert.NoError(c.t, json.Unmarshal(payload, &message))
+	return message
+}
+
+func (c *client) request(method string, params interface{}) map[string]interface{} {
+	c.nextID++
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	return c.receive()
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (c *client) open(uri string, text string) map[string]interface{} {
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "rdparser", "version": 1, "text": text},
+	})
+	return c.receive()
+}
+
+func position(uri string, line int, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": line, "character": character},
+	}
+}
+
+func decodeJSON(t *testing.T, text string) interface{} {
+	var value interface{}
+	assert.NoError(t, json.Unmarshal([]byte(text), &value))
+	return value
+}
+
+const uri = "file:///script.rd"
+
+func TestServer(t *testing.T) {
+	t.Run("given initialize, return capabilities", func(t *testing.T) {
+		c := newClient(t)
+		res := c.request("initialize", map[string]interface{}{})
+		assert.Equal(t, decodeJSON(t, `{
+			"capabilities": {
+				"textDocumentSync": 1,
+				"documentSymbolProvider": true,
+				"definitionProvider": true,
+				"hoverProvider": true,
+				"documentFormattingProvider": true
+			},
+			"serverInfo": {"name": "rdparser"}
+		}`), res["result"])
+	})
+	t.Run("given documents, publish diagnostics on every change", func(t *testing.T) {
+		c := newClient(t)
+		diagnostics := c.open(uri, "let x = 1;\nx +;")
+		assert.Equal(t, "textDocument/publishDiagnostics", diagnostics["method"])
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"diagnostics": [{
+				"range": {"start": {"line": 1, "character": 3}, "end": {"line": 1, "character": 4}},
+				"severity": 1,
+				"source": "rdparser",
+				"message": "Unexpected token: ;, expected: IDENTIFIER"
+			}]
+		}`), diagnostics["params"])
+
+		c.notify("textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+			"contentChanges": []interface{}{map[string]interface{}{"text": "let x = 1;\nx + 1;"}},
+		})
+		diagnostics = c.receive()
+		assert.Equal(t, decodeJSON(t, `{"uri": "file:///script.rd", "diagnostics": []}`), diagnostics["params"])
+	})
+	t.Run("given unreadable character after last statement, publish diagnostic", func(t *testing.T) {
+		c := newClient(t)
+		diagnostics := c.open(uri, "let x = 1;\n@")
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"diagnostics": [{
+				"range": {"start": {"line": 1, "character": 0}, "end": {"line": 1, "character": 1}},
+				"severity": 1,
+				"source": "rdparser",
+				"message": "unexpected token: @"
+			}]
+		}`), diagnostics["params"])
+	})
+	t.Run("given assignment to const, publish diagnostic", func(t *testing.T) {
+		c := newClient(t)
+		diagnostics := c.open(uri, "const x = 1;\nx = 2;")
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"diagnostics": [{
+				"range": {"start": {"line": 1, "character": 0}, "end": {"line": 1, "character": 1}},
+				"severity": 1,
+				"source": "rdparser",
+				"message": "assignment to constant variable: x"
+			}]
+		}`), diagnostics["params"])
+	})
+	t.Run("given type mismatch, publish warning", func(t *testing.T) {
+		c := newClient(t)
+		diagnostics := c.open(uri, "let x: number = 1;\nx = \"a\" * 2;")
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"diagnostics": [{
+				"range": {"start": {"line": 1, "character": 4}, "end": {"line": 1, "character": 11}},
+				"severity": 2,
+				"source": "rdparser",
+				"message": "invalid operands for *: string, number"
+			}]
+		}`), diagnostics["params"])
+	})
+	t.Run("given let declarations, return document symbols", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "let x = 1, y;\n{ let z = x; }")
+		res := c.request("textDocument/documentSymbol", map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+		})
+		assert.Equal(t, decodeJSON(t, `[
+			{
+				"name": "x", "kind": 13,
+				"range": {"start": {"line": 0, "character": 4}, "end": {"line": 0, "character": 9}},
+				"selectionRange": {"start": {"line": 0, "character": 4}, "end": {"line": 0, "character": 5}}
+			},
+			{
+				"name": "y", "kind": 13,
+				"range": {"start": {"line": 0, "character": 11}, "end": {"line": 0, "character": 12}},
+				"selectionRange": {"start": {"line": 0, "character": 11}, "end": {"line": 0, "character": 12}}
+			},
+			{
+				"name": "z", "kind": 13,
+				"range": {"start": {"line": 1, "character": 6}, "end": {"line": 1, "character": 11}},
+				"selectionRange": {"start": {"line": 1, "character": 6}, "end": {"line": 1, "character": 7}}
+			}
+		]`), res["result"])
+	})
+	t.Run("given identifier, return definition in scope", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "let x = 1;\n{ let x = 2; x; }\nx;")
+
+		res := c.request("textDocument/definition", position(uri, 1, 13))
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"range": {"start": {"line": 1, "character": 6}, "end": {"line": 1, "character": 7}}
+		}`), res["result"])
+
+		res = c.request("textDocument/definition", position(uri, 2, 0))
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"range": {"start": {"line": 0, "character": 4}, "end": {"line": 0, "character": 5}}
+		}`), res["result"])
+
+		res = c.request("textDocument/definition", position(uri, 0, 8))
+		assert.Nil(t, res["result"])
+	})
+	t.Run("given function parameter, return it as definition and hover", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "let x = 1;\nlet f = (x) => x.x;")
+
+		res := c.request("textDocument/definition", position(uri, 1, 15))
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"range": {"start": {"line": 1, "character": 9}, "end": {"line": 1, "character": 10}}
+		}`), res["result"])
+
+		res = c.request("textDocument/definition", position(uri, 1, 17))
+		assert.Nil(t, res["result"])
+
+		res = c.request("textDocument/hover", position(uri, 1, 15))
+		assert.Equal(t, decodeJSON(t, `{
+			"contents": {"kind": "markdown", "value": "`+"```"+`\n(parameter) x\n`+"```"+`"},
+			"range": {"start": {"line": 1, "character": 15}, "end": {"line": 1, "character": 16}}
+		}`), res["result"])
+	})
+	t.Run("given catch parameter, return it as definition and hover", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "let e = 1;\ntry { f(); } catch (e) { e; }\ne;")
+
+		res := c.request("textDocument/definition", position(uri, 1, 25))
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"range": {"start": {"line": 1, "character": 20}, "end": {"line": 1, "character": 21}}
+		}`), res["result"])
+
+		res = c.request("textDocument/hover", position(uri, 1, 25))
+		assert.Equal(t, decodeJSON(t, `{
+			"contents": {"kind": "markdown", "value": "`+"```"+`\n(parameter) e\n`+"```"+`"},
+			"range": {"start": {"line": 1, "character": 25}, "end": {"line": 1, "character": 26}}
+		}`), res["result"])
+
+		res = c.request("textDocument/definition", position(uri, 2, 0))
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"range": {"start": {"line": 0, "character": 4}, "end": {"line": 0, "character": 5}}
+		}`), res["result"])
+	})
+	t.Run("given declarations after a function initializer, hover with their own kind", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "const f = () => { let y = 1; }, c = 2;\nc;")
+
+		res := c.request("textDocument/hover", position(uri, 1, 0))
+		assert.Equal(t, decodeJSON(t, `{
+			"contents": {"kind": "markdown", "value": "`+"```"+`\nconst c = 2;\n`+"```"+`"},
+			"range": {"start": {"line": 1, "character": 0}, "end": {"line": 1, "character": 1}}
+		}`), res["result"])
+	})
+	t.Run("given destructured binding, return it as definition", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "let { a, b: c = a } = o;\nc;")
+
+		res := c.request("textDocument/definition", position(uri, 1, 0))
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"range": {"start": {"line": 0, "character": 12}, "end": {"line": 0, "character": 13}}
+		}`), res["result"])
+
+		res = c.request("textDocument/definition", position(uri, 0, 16))
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"range": {"start": {"line": 0, "character": 6}, "end": {"line": 0, "character": 7}}
+		}`), res["result"])
+
+		res = c.request("textDocument/definition", position(uri, 0, 9))
+		assert.Nil(t, res["result"])
+	})
+	t.Run("given label, return no definition", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "let x = 1;\nx: { x; break x; }")
+
+		res := c.request("textDocument/definition", position(uri, 1, 5))
+		assert.Equal(t, decodeJSON(t, `{
+			"uri": "file:///script.rd",
+			"range": {"start": {"line": 0, "character": 4}, "end": {"line": 0, "character": 5}}
+		}`), res["result"])
+
+		res = c.request("textDocument/definition", position(uri, 1, 0))
+		assert.Nil(t, res["result"])
+
+		res = c.request("textDocument/definition", position(uri, 1, 14))
+		assert.Nil(t, res["result"])
+	})
+	t.Run("given identifier, return hover", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "const answer = 40 + 2;\nanswer;")
+		res := c.request("textDocument/hover", position(uri, 1, 3))
+		assert.Equal(t, decodeJSON(t, `{
+			"contents": {"kind": "markdown", "value": "`+"```"+`\nconst answer = 40 + 2;\n`+"```"+`"},
+			"range": {"start": {"line": 1, "character": 0}, "end": {"line": 1, "character": 6}}
+		}`), res["result"])
+	})
+	t.Run("given formatting, return edit replacing the document", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "let x=1;\nif(x){x=2;}")
+		res := c.request("textDocument/formatting", map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+		})
+		assert.Equal(t, decodeJSON(t, `[{
+			"range": {"start": {"line": 0, "character": 0}, "end": {"line": 1, "character": 11}},
+			"newText": "let x = 1;\nif (x) {\n  x = 2;\n}\n"
+		}]`), res["result"])
+	})
+	t.Run("given formatting of document with comments, keep the comments", func(t *testing.T) {
+		c := newClient(t)
+		c.open(uri, "// one\nlet x=1;")
+		res := c.request("textDocument/formatting", map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+		})
+		assert.Equal(t, decodeJSON(t, `[{
+			"range": {"start": {"line": 0, "character": 0}, "end": {"line": 1, "character": 8}},
+			"newText": "// one\nlet x = 1;\n"
+		}]`), res["result"])
+	})
+	t.Run("given unknown method, return method not found", func(t *testing.T) {
+		c := newClient(t)
+		res := c.request("workspace/symbol", map[string]interface{}{})
+		assert.Equal(t, decodeJSON(t, `{"code": -32601, "message": "method not found: workspace/symbol"}`), res["error"])
+	})
+	t.Run("given shutdown and exit, stop serving", func(t *testing.T) {
+		c := newClient(t)
+		res := c.request("shutdown", nil)
+		assert.Contains(t, res, "result")
+		assert.Nil(t, res["result"])
+
+		c.notify("exit", nil)
+		assert.NoError(t, <-c.done)
+	})
+}
+
+func TestRead(t *testing.T) {
+	tests := map[string]struct {
+		input         string
+		expectedError string
+	}{
+		"given negative Content-Length": {
+			input:         "Content-Length: -1\r\n\r\n",
+			expectedError: "invalid Content-Length: -1",
+		},
+		"given Content-Length over the maximum": {
+			input:         "Content-Length: 1099511627776\r\n\r\n",
+			expectedError: "Content-Length exceeds 67108864 bytes: 1099511627776",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := New(Props{In: strings.NewReader(tc.input), Out: &bytes.Buffer{}}).Run()
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestPositions(t *testing.T) {
+	text := "let s = 'é😀';\nx;"
+	assert.Equal(t, Position{Line: 0, Character: 12}, toPosition(text, 15))
+	assert.Equal(t, 15, toOffset(text, Position{Line: 0, Character: 12}))
+	assert.Equal(t, Position{Line: 1, Character: 1}, toPosition(text, len(text)-1))
+	assert.Equal(t, len(text)-1, toOffset(text, Position{Line: 1, Character: 1}))
+	assert.Equal(t, len(text), toOffset(text, Position{Line: 5, Character: 0}))
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/cfg"
+	"github.com/dlanell/go-rdparser/highlight"
+	"github.com/dlanell/go-rdparser/lint"
+	"github.com/dlanell/go-rdparser/lsp"
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/dlanell/go-rdparser/parser/docs"
+	"github.com/dlanell/go-rdparser/parser/printer"
+	"github.com/dlanell/go-rdparser/parser/tokenizer"
+	"github.com/dlanell/go-rdparser/querybuilder/mongobuilder"
+	"github.com/dlanell/go-rdparser/queryparser"
+	"github.com/dlanell/go-rdparser/repl"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const usage = `usage: rdparser <command> [arguments]
+
+commands:
+  tokenize [file...]              print the tokens of a script
+  parse [file...]                 print the syntax tree of a script as JSON
+  fmt [-w] [-l] [file...]         reformat a script
+  docs [-json] [file...]          print the top-level declarations of a script with their doc comments
+  cfg [file...]                   print the control-flow graph of a script in the DOT language
+  lint [-format text|json|sarif] [-rule name=severity...] [file...]
+                                  check a script with the lint rules
+  query [file...]                 print the syntax tree of a query filter as JSON
+  mongo [-fields f,...] [file...] print the MongoDB filter of a query filter as extended JSON
+  highlight [-format ansi|html] [-query] [file...]
+                                  print a script or query filter with syntax highlighting
+  repl                            start an interactive session
+  lsp                             serve the Language Server Protocol over standard input and output
+
+Commands read the named files, or standard input when none are given.
+`
+
+const (
+	exitOK    int = 0
+	exitError     = 1
+	exitUsage     = 2
+)
+
+type input struct {
+	name string
+	text string
+}
+
+type command func(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int
+
+var commands = map[string]command{
+	"tokenize":  tokenizeCommand,
+	"parse":     parseCommand,
+	"fmt":       fmtCommand,
+	"docs":      docsCommand,
+	"cfg":       cfgCommand,
+	"lint":      lintCommand,
+	"query":     queryCommand,
+	"mongo":     mongoCommand,
+	"highlight": highlightCommand,
+	"repl":      replCommand,
+	"lsp":       lspCommand,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command: %s\n%s", args[0], usage)
+		return exitUsage
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
+
+func tokenizeCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	return eachInput(args, stdin, stderr, func(in input) error {
+		t := tokenizer.New(tokenizer.Props{Text: in.text})
+		for {
+			token, err := t.GetNextToken()
+			if errors.Is(err, tokenizer.ErrNoTokens) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(stdout, "%s\t%s\n", token.TokenType, token.Value)
+		}
+	})
+}
+
+func parseCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	return eachInput(args, stdin, stderr, func(in input) error {
+		program, err := parser.New(parser.Props{Text: in.text}).Run()
+		if err != nil {
+			return err
+		}
+		return writeJSON(stdout, program)
+	})
+}
+
+func fmtCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	write := flags.Bool("w", false, "write the result back to the file instead of standard output")
+	list := flags.Bool("l", false, "list files whose formatting differs and exit with an error")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	unformatted := false
+	code := eachInput(flags.Args(), stdin, stderr, func(in input) error {
+		program, err := parser.New(parser.Props{Text: in.text, Comments: true}).Run()
+		if err != nil {
+			return err
+		}
+		formatted, err := printer.New(printer.Props{}).Run(program)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case *list:
+			if formatted != in.text {
+				unformatted = true
+				fmt.Fprintln(stdout, in.name)
+			}
+			return nil
+		case *write && in.name != stdinName:
+			if formatted == in.text {
+				return nil
+			}
+			info, err := os.Stat(in.name)
+			if err != nil {
+				return err
+			}
+			return ioutil.WriteFile(in.name, []byte(formatted), info.Mode().Perm())
+		default:
+			fmt.Fprint(stdout, formatted)
+			return nil
+		}
+	})
+
+	if code == exitOK && unformatted {
+		return exitError
+	}
+	return code
+}
+
+func docsCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("docs", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the declarations as JSON instead of Markdown")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	return eachInput(flags.Args(), stdin, stderr, func(in input) error {
+		declarations, err := docs.New(docs.Props{}).Run(in.text)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return writeJSON(stdout, declarations)
+		}
+		fmt.Fprint(stdout, docs.Markdown(declarations))
+		return nil
+	})
+}
+
+func cfgCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	return eachInput(args, stdin, stderr, func(in input) error {
+		program, err := parser.New(parser.Props{Text: in.text}).Run()
+		if err != nil {
+			return err
+		}
+		dot, err := cfg.DOT(cfg.New(cfg.Props{}).Run(program))
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(stdout, dot)
+		return nil
+	})
+}
+
+func lintCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format, text, json or sarif")
+	severities := map[string]lint.Severity{}
+	flags.Func("rule", "set the severity of a rule, error, warning or off, as name=severity", func(value string) error {
+		parts := strings.SplitN(value, "=", 2)
+		if lint.Lookup(parts[0]) == nil {
+			return fmt.Errorf("unknown rule: %s", parts[0])
+		}
+		severity := lint.Severity(parts[len(parts)-1])
+		switch severity {
+		case lint.SeverityError, lint.SeverityWarning, lint.SeverityOff:
+			severities[parts[0]] = severity
+			return nil
+		}
+		return fmt.Errorf("unknown severity: %s", severity)
+	})
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" && *format != "sarif" {
+		fmt.Fprintf(stderr, "unsupported format: %s\n", *format)
+		return exitUsage
+	}
+
+	linter := lint.New(lint.Props{Severities: severities})
+	files := make([]*lint.File, 0)
+	failed := false
+	code := eachInput(flags.Args(), stdin, stderr, func(in input) error {
+		diagnostics, err := linter.Run(in.text)
+		if err != nil {
+			return err
+		}
+		for _, diagnostic := range diagnostics {
+			failed = failed || diagnostic.Severity == lint.SeverityError
+		}
+		files = append(files, &lint.File{Name: in.name, Diagnostics: diagnostics})
+		return nil
+	})
+
+	var err error
+	switch *format {
+	case "json":
+		err = writeJSON(stdout, files)
+	case "sarif":
+		err = writeJSON(stdout, lint.NewSARIF(files))
+	default:
+		fmt.Fprint(stdout, lint.Text(files))
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if code == exitOK && failed {
+		return exitError
+	}
+	return code
+}
+
+func queryCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	return eachInput(args, stdin, stderr, func(in input) error {
+		program, err := queryparser.New().Run(strings.TrimSpace(in.text))
+		if err != nil {
+			return err
+		}
+		return writeJSON(stdout, program)
+	})
+}
+
+func mongoCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("mongo", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	fields := flags.String("fields", "", "comma separated fields that bare literals are compared against")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	literalComparisonFields := make([]string, 0)
+	if *fields != "" {
+		literalComparisonFields = strings.Split(*fields, ",")
+	}
+
+	return eachInput(flags.Args(), stdin, stderr, func(in input) error {
+		builder := mongobuilder.New(mongobuilder.Props{LiteralComparisonFields: literalComparisonFields})
+		filter, err := builder.Run(strings.TrimSpace(in.text))
+		if err != nil {
+			return err
+		}
+		extJSON, err := bson.MarshalExtJSON(filter, false, false)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(extJSON))
+		return nil
+	})
+}
+
+func highlightCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("highlight", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", highlight.ANSI, "output format, ansi or html")
+	query := flags.Bool("query", false, "highlight the input as a query filter instead of a script")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	language := highlight.Script
+	if *query {
+		language = highlight.Query
+	}
+	highlighter := highlight.New(highlight.Props{Format: *format, Language: language})
+	return eachInput(flags.Args(), stdin, stderr, func(in input) error {
+		output, err := highlighter.Run(in.text)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(stdout, output)
+		return nil
+	})
+}
+
+func replCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	err := repl.New(repl.Props{
+		In:          stdin,
+		Out:         stdout,
+		HistoryFile: historyFile(),
+	}).Run()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+func lspCommand(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	err := lsp.New(lsp.Props{In: stdin, Out: stdout}).Run()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+const stdinName = "<stdin>"
+
+// eachInput runs fn over every named file, or standard input when there are none,
+// reporting each failure and exiting with an error if any input failed.
+func eachInput(paths []string, stdin io.Reader, stderr io.Writer, fn func(in input) error) int {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	code := exitOK
+	for _, path := range paths {
+		in, err := readInput(path, stdin)
+		if err == nil {
+			err = fn(in)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", in.name, strings.TrimSpace(err.Error()))
+			code = exitError
+		}
+	}
+	return code
+}
+
+func readInput(path string, stdin io.Reader) (input, error) {
+	if path == "-" {
+		text, err := ioutil.ReadAll(stdin)
+		return input{name: stdinName, text: string(text)}, err
+	}
+	text, err := ioutil.ReadFile(path)
+	return input{name: path, text: string(text)}, err
+}
+
+func writeJSON(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func historyFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".rdparser_history")
+}
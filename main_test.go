@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	args           []string
+	stdin          string
+	expectedOutput string
+	expectedErrors string
+	expectedCode   int
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given no command": {
+			expectedErrors: usage,
+			expectedCode:   exitUsage,
+		},
+		"given unknown command": {
+			args:           []string{"compile"},
+			expectedErrors: "unknown command: compile\n" + usage,
+			expectedCode:   exitUsage,
+		},
+		"given tokenize": {
+			args:           []string{"tokenize"},
+			stdin:          `let x = 42;`,
+			expectedOutput: "let\tlet\nIDENTIFIER\tx\nSIMPLE_ASSIGNMENT\t=\nNUMBER\t42\n;\t;\n",
+		},
+		"given tokenize with invalid character": {
+			args:           []string{"tokenize"},
+			stdin:          `x @`,
+			expectedOutput: "IDENTIFIER\tx\n",
+			expectedErrors: "<stdin>: unexpected token: @\n",
+			expectedCode:   exitError,
+		},
+		"given parse": {
+			args:           []string{"parse"},
+			stdin:          `42;`,
+			expectedOutput: "{\n  \"NodeType\": \"Program\",\n  \"Body\": [\n    {\n      \"NodeType\": \"ExpressionStatement\",\n      \"Body\": {\n        \"NodeType\": \"NumericLiteral\",\n        \"Body\": {\n          \"Value\": 42\n        }\n      }\n    }\n  ]\n}\n",
+		},
+		"given parse error": {
+			args:           []string{"parse"},
+			stdin:          `42`,
+			expectedErrors: "<stdin>: Unexpected end of input, expected: ;\n",
+			expectedCode:   exitError,
+		},
+		"given fmt": {
+			args:           []string{"fmt"},
+			stdin:          `let x=1;if(x){x=2;}`,
+			expectedOutput: "let x = 1;\nif (x) {\n  x = 2;\n}\n",
+		},
+		"given fmt of script with comments": {
+			args:           []string{"fmt"},
+			stdin:          "// answer\nlet x='//'; /* done */",
+			expectedOutput: "// answer\nlet x = \"//\"; /* done */\n",
+		},
+		"given fmt with unreadable character after last statement": {
+			args:           []string{"fmt"},
+			stdin:          "let x = 1;\nlet y = 2; # oops\nlet z = 3;\n",
+			expectedErrors: "<stdin>: unexpected token: #\n",
+			expectedCode:   exitError,
+		},
+		"given parse with unreadable character after last statement": {
+			args:           []string{"parse"},
+			stdin:          `1; @`,
+			expectedErrors: "<stdin>: unexpected token: @\n",
+			expectedCode:   exitError,
+		},
+		"given docs": {
+			args:           []string{"docs"},
+			stdin:          "/** The answer. */\nlet answer = 42;",
+			expectedOutput: "## answer\n\n```\nlet answer = 42;\n```\n\nThe answer.\n",
+		},
+		"given docs as json": {
+			args:           []string{"docs", "-json"},
+			stdin:          "let x;",
+			expectedOutput: "[\n  {\n    \"Name\": \"x\",\n    \"Kind\": \"let\",\n    \"Signature\": \"let x;\",\n    \"Doc\": \"\",\n    \"Loc\": {\n      \"Start\": 4,\n      \"End\": 5\n    }\n  }\n]\n",
+		},
+		"given cfg": {
+			args:           []string{"cfg"},
+			stdin:          "if (x) return;",
+			expectedOutput: "digraph cfg {\n\tnode [shape=box];\n\tB0 [label=\"B0 (entry)\\lx\\l\"];\n\tB1 [label=\"B1 (exit)\\l\"];\n\tB2 [label=\"B2\\lreturn;\\l\"];\n\tB3 [label=\"B3\\l\"];\n\tB0 -> B2 [label=\"true\"];\n\tB0 -> B3 [label=\"false\"];\n\tB2 -> B1;\n\tB3 -> B1;\n}\n",
+		},
+		"given lint": {
+			args:           []string{"lint"},
+			stdin:          "if (x = 1) {}",
+			expectedOutput: "<stdin>:1:5: error: unexpected assignment in condition (no-cond-assign)\n<stdin>:1:12: warning: empty block statement (no-empty)\n",
+			expectedCode:   exitError,
+		},
+		"given lint with rule severities": {
+			args:           []string{"lint", "-rule", "no-cond-assign=warning", "-rule", "no-empty=off", "-format", "json"},
+			stdin:          "if (x = 1) {}",
+			expectedOutput: "[\n  {\n    \"Name\": \"\\u003cstdin\\u003e\",\n    \"Diagnostics\": [\n      {\n        \"Rule\": \"no-cond-assign\",\n        \"Severity\": \"warning\",\n        \"Message\": \"unexpected assignment in condition\",\n        \"Loc\": {\n          \"Start\": 4,\n          \"End\": 9\n        },\n        \"Start\": {\n          \"Line\": 1,\n          \"Column\": 5\n        },\n        \"End\": {\n          \"Line\": 1,\n          \"Column\": 10\n        }\n      }\n    ]\n  }\n]\n",
+		},
+		"given lint with data-flow problem": {
+			args:           []string{"lint"},
+			stdin:          "let x; if (1) { x = 1; } x;",
+			expectedOutput: "<stdin>:1:12: error: constant condition (no-constant-condition)\n<stdin>:1:26: warning: x is used before being assigned (dataflow)\n",
+			expectedCode:   exitError,
+		},
+		"given lint with type error": {
+			args:           []string{"lint", "-rule", "dataflow=off"},
+			stdin:          "let x: number = \"a\";",
+			expectedOutput: "<stdin>:1:17: warning: type string is not assignable to type number (type-check)\n",
+		},
+		"given lint with unknown rule": {
+			args: []string{"lint", "-rule", "no-var=error"},
+			expectedErrors: "invalid value \"no-var=error\" for flag -rule: unknown rule: no-var\nUsage of lint:\n" +
+				"  -format string\n    \toutput format, text, json or sarif (default \"text\")\n" +
+				"  -rule value\n    \tset the severity of a rule, error, warning or off, as name=severity\n",
+			expectedCode: exitUsage,
+		},
+		"given query": {
+			args:           []string{"query"},
+			stdin:          "eq(name, \"revan\")\n",
+			expectedOutput: "{\n  \"NodeType\": \"Program\",\n  \"Body\": {\n    \"NodeType\": \"RelationalFunction\",\n    \"Body\": {\n      \"Operator\": \"eq\",\n      \"Arguments\": [\n        {\n          \"NodeType\": \"Identifier\",\n          \"Body\": {\n            \"Value\": \"name\"\n          }\n        },\n        {\n          \"NodeType\": \"StringLiteral\",\n          \"Body\": {\n            \"Value\": \"revan\"\n          }\n        }\n      ]\n    }\n  }\n}\n",
+		},
+		"given query error": {
+			args:           []string{"query"},
+			stdin:          `eq(name)`,
+			expectedErrors: "<stdin>: unexpected token: ), expected: ,\n",
+			expectedCode:   exitError,
+		},
+		"given mongo": {
+			args:           []string{"mongo"},
+			stdin:          `and(eq(name, "revan"), gt(cores, 4))`,
+			expectedOutput: `{"$and":[{"name":"revan"},{"cores":{"$gt":4}}]}` + "\n",
+		},
+		"given mongo with literal comparison fields": {
+			args:           []string{"mongo", "-fields", "title,email"},
+			stdin:          `"sith"`,
+			expectedOutput: `{"$or":[{"title":"sith"},{"email":"sith"}]}` + "\n",
+		},
+		"given highlight": {
+			args:           []string{"highlight"},
+			stdin:          `let x = 1;`,
+			expectedOutput: "\x1b[35mlet\x1b[0m x \x1b[36m=\x1b[0m \x1b[33m1\x1b[0m;",
+		},
+		"given highlight of query as html": {
+			args:           []string{"highlight", "-format", "html", "-query"},
+			stdin:          `eq(x, 1)`,
+			expectedOutput: `<span class="rd-keyword">eq</span><span class="rd-punctuation">(</span><span class="rd-identifier">x</span><span class="rd-punctuation">,</span> <span class="rd-number">1</span><span class="rd-punctuation">)</span>`,
+		},
+		"given highlight with unsupported format": {
+			args:           []string{"highlight", "-format", "latex"},
+			stdin:          `x;`,
+			expectedErrors: "<stdin>: unsupported format: latex\n",
+			expectedCode:   exitError,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			code := run(tc.args, strings.NewReader(tc.stdin), stdout, stderr)
+			assert.Equal(t, tc.expectedOutput, stdout.String())
+			assert.Equal(t, tc.expectedErrors, stderr.String())
+			assert.Equal(t, tc.expectedCode, code)
+		})
+	}
+
+	t.Run("given fmt with files", func(t *testing.T) {
+		dir := t.TempDir()
+		formatted := filepath.Join(dir, "formatted.rd")
+		unformatted := filepath.Join(dir, "unformatted.rd")
+		assert.NoError(t, ioutil.WriteFile(formatted, []byte("let x = 1;\n"), 0644))
+		assert.NoError(t, ioutil.WriteFile(unformatted, []byte("let   x=1;"), 0644))
+
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"fmt", "-l", formatted, unformatted}, nil, stdout, stderr)
+		assert.Equal(t, unformatted+"\n", stdout.String())
+		assert.Equal(t, exitError, code)
+
+		code = run([]string{"fmt", "-w", formatted, unformatted}, nil, stdout, stderr)
+		assert.Equal(t, exitOK, code)
+		text, _ := ioutil.ReadFile(unformatted)
+		assert.Equal(t, "let x = 1;\n", string(text))
+		assert.Empty(t, stderr.String())
+	})
+	t.Run("given fmt -w with files it cannot parse or not readable by others", func(t *testing.T) {
+		dir := t.TempDir()
+		invalid := filepath.Join(dir, "invalid.rd")
+		private := filepath.Join(dir, "private.rd")
+		assert.NoError(t, ioutil.WriteFile(invalid, []byte("let x=1;\nlet y = 2; # oops\nlet z = 3;\n"), 0644))
+		assert.NoError(t, ioutil.WriteFile(private, []byte("let   x=1;"), 0600))
+
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"fmt", "-w", invalid, private}, nil, stdout, stderr)
+		assert.Equal(t, exitError, code)
+		assert.Equal(t, invalid+": unexpected token: #\n", stderr.String())
+		text, _ := ioutil.ReadFile(invalid)
+		assert.Equal(t, "let x=1;\nlet y = 2; # oops\nlet z = 3;\n", string(text))
+		info, err := os.Stat(private)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+	t.Run("given missing file", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"parse", "missing.rd"}, nil, stdout, stderr)
+		assert.Contains(t, stderr.String(), "missing.rd: open missing.rd")
+		assert.Equal(t, exitError, code)
+	})
+}
@@ -0,0 +1,85 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ModuleLoader finds the text of the modules scripts import.
+type ModuleLoader interface {
+	// Resolve returns the id of the module the specifier names, importer is the id of
+	// the importing module or "" for the entry module.
+	Resolve(specifier string, importer string) (string, error)
+	// Load returns the text of the module with the id.
+	Load(id string) (string, error)
+}
+
+// Extension is added to specifiers that have none.
+const Extension = ".rd"
+
+var ErrModuleNotFound = errors.New("module not found")
+
+// FileSystemLoader loads modules from the files beneath a root directory.
+type FileSystemLoader struct {
+	root string
+}
+
+// MemoryLoader loads modules from a map of module ids to their text.
+type MemoryLoader struct {
+	modules map[string]string
+}
+
+func NewFileSystemLoader(root string) *FileSystemLoader {
+	return &FileSystemLoader{root: root}
+}
+
+func NewMemoryLoader(modules map[string]string) *MemoryLoader {
+	return &MemoryLoader{modules: modules}
+}
+
+func (l *FileSystemLoader) Resolve(specifier string, importer string) (string, error) {
+	return resolvePath(specifier, importer)
+}
+
+func (l *FileSystemLoader) Load(id string) (string, error) {
+	text, err := ioutil.ReadFile(filepath.Join(l.root, filepath.FromSlash(id)))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s", ErrModuleNotFound, id)
+	}
+	return string(text), err
+}
+
+func (l *MemoryLoader) Resolve(specifier string, importer string) (string, error) {
+	return resolvePath(specifier, importer)
+}
+
+func (l *MemoryLoader) Load(id string) (string, error) {
+	text, ok := l.modules[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrModuleNotFound, id)
+	}
+	return text, nil
+}
+
+// resolvePath resolves specifiers starting with ./ or ../ against the directory of the
+// importer and any other specifier against the root, ids are slash separated paths
+// relative to the root.
+func resolvePath(specifier string, importer string) (string, error) {
+	id := specifier
+	if strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../") {
+		id = path.Join(path.Dir(importer), specifier)
+	}
+	id = path.Clean(strings.TrimPrefix(id, "/"))
+	if id == ".." || strings.HasPrefix(id, "../") {
+		return "", fmt.Errorf("module outside of the root: %s", specifier)
+	}
+	if path.Ext(id) == "" {
+		id += Extension
+	}
+	return id, nil
+}
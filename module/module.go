@@ -0,0 +1,143 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/parser"
+)
+
+// Module is a parsed script together with the modules it imports,
+// Dependencies maps the source of every import to its module.
+type Module struct {
+	ID           string
+	Program      *parser.Program
+	Exports      []string
+	Dependencies map[string]*Module
+}
+
+// Linker loads a module and everything it imports, every module is parsed once.
+type Linker struct {
+	loader  ModuleLoader
+	parser  parser.Props
+	modules map[string]*Module
+	loading []string
+}
+
+// Props
+// Parser configures how modules are parsed, its Text is ignored.
+type Props struct {
+	Loader ModuleLoader
+	Parser parser.Props
+}
+
+var ErrImportCycle = errors.New("import cycle")
+
+func New(props Props) *Linker {
+	return &Linker{
+		loader:  props.Loader,
+		parser:  props.Parser,
+		modules: map[string]*Module{},
+	}
+}
+
+// Run loads the entry module the specifier names and, recursively, its imports. It fails
+// when an import cycles back to a module being loaded or names a missing export.
+func (l *Linker) Run(specifier string) (*Module, error) {
+	l.loading = nil
+	return l.load(specifier, "")
+}
+
+func (l *Linker) load(specifier string, importer string) (*Module, error) {
+	id, err := l.loader.Resolve(specifier, importer)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, loading := range l.loading {
+		if loading == id {
+			cycle := append(append([]string{}, l.loading[index:]...), id)
+			return nil, fmt.Errorf("%w: %s", ErrImportCycle, strings.Join(cycle, " -> "))
+		}
+	}
+	if m, ok := l.modules[id]; ok {
+		return m, nil
+	}
+
+	text, err := l.loader.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	props := l.parser
+	props.Text = text
+	program, err := parser.New(props).Run()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", id, err)
+	}
+
+	m := &Module{
+		ID:           id,
+		Program:      program,
+		Exports:      exports(program),
+		Dependencies: map[string]*Module{},
+	}
+
+	l.loading = append(l.loading, id)
+	for _, statement := range program.Body {
+		if statement.NodeType != parser.ImportDeclaration {
+			continue
+		}
+		value := statement.Body.(*parser.ImportDeclarationValue)
+		source := value.Source.Body.(*parser.StringLiteralValue).Value
+		dependency, err := l.load(source, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkImports(m, dependency, value.Specifiers); err != nil {
+			return nil, err
+		}
+		m.Dependencies[source] = dependency
+	}
+	l.loading = l.loading[:len(l.loading)-1]
+
+	l.modules[id] = m
+	return m, nil
+}
+
+// exports lists the names the export declarations of the program declare.
+func exports(program *parser.Program) []string {
+	names := make([]string, 0)
+	for _, statement := range program.Body {
+		if statement.NodeType != parser.ExportDeclaration {
+			continue
+		}
+		declaration := statement.Body.(*parser.Node)
+		for _, child := range parser.Children(declaration) {
+			id := child.Body.(*parser.VariableDeclarationValue).Id
+			for _, identifier := range parser.BindingIdentifiers(id) {
+				names = append(names, identifier.Body.(*parser.StringLiteralValue).Value)
+			}
+		}
+	}
+	return names
+}
+
+func checkImports(m *Module, dependency *Module, specifiers []*parser.Node) error {
+	for _, specifier := range specifiers {
+		imported := specifier.Body.(*parser.ImportSpecifierValue).Imported.Body.(*parser.StringLiteralValue).Value
+		if !dependency.exports(imported) {
+			return fmt.Errorf("%s: %s has no export %s", m.ID, dependency.ID, imported)
+		}
+	}
+	return nil
+}
+
+func (m *Module) exports(name string) bool {
+	for _, export := range m.Exports {
+		if export == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package module
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	modules       map[string]string
+	entry         string
+	expectedID    string
+	expectedError error
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given relative and root imports, resolve them": {
+			modules: map[string]string{
+				"main.rd":      `import { a } from "./lib/a"; import { b } from "lib/b.rd";`,
+				"lib/a.rd":     `import { b } from "./b"; export let a = b;`,
+				"lib/b.rd":     `export const b = 1;`,
+				"lib/other.rd": `1;`,
+			},
+			entry:      "main",
+			expectedID: "main.rd",
+		},
+		"given missing module": {
+			modules: map[string]string{
+				"main.rd": `import { a } from "./a";`,
+			},
+			entry:         "main.rd",
+			expectedError: errors.New("module not found: a.rd"),
+		},
+		"given import of missing export": {
+			modules: map[string]string{
+				"main.rd": `import { a, c } from "./a";`,
+				"a.rd":    `export let a = 1; let c = 2;`,
+			},
+			entry:         "main.rd",
+			expectedError: errors.New("main.rd: a.rd has no export c"),
+		},
+		"given import cycle": {
+			modules: map[string]string{
+				"main.rd": `import {} from "./a";`,
+				"a.rd":    `import {} from "./b";`,
+				"b.rd":    `import {} from "./a";`,
+			},
+			entry:         "main.rd",
+			expectedError: errors.New("import cycle: a.rd -> b.rd -> a.rd"),
+		},
+		"given syntax error, prefix it with the module": {
+			modules: map[string]string{
+				"main.rd": `import {} from "./a";`,
+				"a.rd":    `let;`,
+			},
+			entry:         "main.rd",
+			expectedError: errors.New("a.rd: Unexpected token: ;, expected: IDENTIFIER\n"),
+		},
+		"given import outside of the root": {
+			modules:       map[string]string{"main.rd": `import {} from "../a";`},
+			entry:         "main.rd",
+			expectedError: errors.New("module outside of the root: ../a"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m, err := New(Props{Loader: NewMemoryLoader(tc.modules)}).Run(tc.entry)
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedID, m.ID)
+		})
+	}
+
+	t.Run("given diamond imports, load the shared module once", func(t *testing.T) {
+		loader := NewMemoryLoader(map[string]string{
+			"main.rd":   `import { a } from "./a"; import { b } from "./b";`,
+			"a.rd":      `import { s } from "./shared"; export let a = s;`,
+			"b.rd":      `import { s } from "./shared"; export let b = s;`,
+			"shared.rd": `export let s = 1, t;`,
+		})
+		m, err := New(Props{Loader: loader}).Run("main.rd")
+		assert.NoError(t, err)
+
+		a, b := m.Dependencies["./a"], m.Dependencies["./b"]
+		assert.Equal(t, []string{"a"}, a.Exports)
+		assert.Same(t, a.Dependencies["./shared"], b.Dependencies["./shared"])
+		assert.Equal(t, []string{"s", "t"}, a.Dependencies["./shared"].Exports)
+	})
+
+	t.Run("given Parser props, parse modules with them", func(t *testing.T) {
+		loader := NewMemoryLoader(map[string]string{"main.rd": "let x = 1\nx"})
+		m, err := New(Props{Loader: loader, Parser: parser.Props{AutoSemicolons: true}}).Run("main.rd")
+		assert.NoError(t, err)
+		assert.Len(t, m.Program.Body, 2)
+	})
+}
+
+func TestFileSystemLoader(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(root, "lib"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "main.rd"), []byte(`import { a } from "./lib/a";`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "lib", "a.rd"), []byte(`export let a = 1;`), 0o644))
+
+	m, err := New(Props{Loader: NewFileSystemLoader(root)}).Run("main")
+	assert.NoError(t, err)
+	assert.Equal(t, "lib/a.rd", m.Dependencies["./lib/a"].ID)
+
+	_, err = NewFileSystemLoader(root).Load("missing.rd")
+	assert.ErrorIs(t, err, ErrModuleNotFound)
+}
@@ -0,0 +1,136 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/dlanell/go-rdparser/parser/printer"
+)
+
+type Extractor struct {
+	printer *printer.Printer
+}
+
+type Props struct{}
+
+// Declaration is a top-level declaration, Doc is the text of the comments directly
+// above it with the comment delimiters removed.
+type Declaration struct {
+	Name      string
+	Kind      string
+	Signature string
+	Doc       string
+	Loc       parser.Location
+}
+
+func New(props Props) *Extractor {
+	return &Extractor{
+		printer: printer.New(printer.Props{}),
+	}
+}
+
+// Run lists every top-level declaration of the script in source order.
+func (e *Extractor) Run(text string) ([]Declaration, error) {
+	program, err := parser.New(parser.Props{Text: text, Locations: true, Comments: true}).Run()
+	if err != nil {
+		return nil, err
+	}
+
+	declarations := make([]Declaration, 0)
+	for _, statement := range program.Body {
+		doc := docComment(text, statement)
+		variables := statement
+		if statement.NodeType == parser.ExportDeclaration {
+			variables = statement.Body.(*parser.Node)
+		}
+		if variables.NodeType != parser.VariableStatement {
+			continue
+		}
+		kind := variables.Body.(*parser.VariableStatementValue).Kind
+		for _, declaration := range parser.Children(variables) {
+			value := declaration.Body.(*parser.VariableDeclarationValue)
+			single := &parser.Node{
+				NodeType: parser.VariableStatement,
+				Body:     &parser.VariableStatementValue{Kind: kind, Declarations: []*parser.Node{declaration}},
+			}
+			if variables != statement {
+				single = &parser.Node{NodeType: parser.ExportDeclaration, Body: single}
+			}
+			signature, err := e.printer.Run(&parser.Program{
+				NodeType: parser.ProgramEnum,
+				Body:     []*parser.Node{single},
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, identifier := range parser.BindingIdentifiers(value.Id) {
+				declarations = append(declarations, Declaration{
+					Name:      identifier.Body.(*parser.StringLiteralValue).Value,
+					Kind:      kind,
+					Signature: strings.TrimSuffix(signature, "\n"),
+					Doc:       doc,
+					Loc:       *declaration.Loc,
+				})
+			}
+		}
+	}
+	return declarations, nil
+}
+
+// Markdown renders the declarations as a reference page, one section per declaration.
+func Markdown(declarations []Declaration) string {
+	builder := &strings.Builder{}
+	for index, declaration := range declarations {
+		if index > 0 {
+			builder.WriteString("\n")
+		}
+		fmt.Fprintf(builder, "## %s\n\n```\n%s\n```\n", declaration.Name, declaration.Signature)
+		if declaration.Doc != "" {
+			fmt.Fprintf(builder, "\n%s\n", declaration.Doc)
+		}
+	}
+	return builder.String()
+}
+
+// docComment joins the leading comments of the statement that are not separated from
+// it, or from each other, by a blank line.
+func docComment(text string, statement *parser.Node) string {
+	if statement.Comments == nil {
+		return ""
+	}
+
+	leading := statement.Comments.Leading
+	end := statement.Loc.Start
+	first := len(leading)
+	for first > 0 && strings.Count(text[leading[first-1].Loc.End:end], "\n") < 2 {
+		first--
+		end = leading[first].Loc.Start
+	}
+
+	lines := make([]string, 0)
+	for _, comment := range leading[first:] {
+		lines = append(lines, commentLines(comment.Value)...)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// commentLines strips the delimiters of a comment, and the leading `*` of every line
+// of a block comment.
+func commentLines(comment string) []string {
+	if strings.HasPrefix(comment, "//") {
+		return []string{trimMarker(strings.TrimPrefix(comment, "//"), "/")}
+	}
+
+	body := strings.TrimSuffix(strings.TrimPrefix(comment, "/*"), "*/")
+	lines := strings.Split(body, "\n")
+	for index, line := range lines {
+		lines[index] = trimMarker(strings.TrimSpace(line), "*")
+	}
+	return lines
+}
+
+func trimMarker(line string, marker string) string {
+	line = strings.TrimLeft(line, marker)
+	return strings.TrimSpace(line)
+}
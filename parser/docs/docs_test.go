@@ -0,0 +1,100 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	text                 string
+	expectedDeclarations []Declaration
+	expectedError        error
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given doc block comment, return declaration with doc": {
+			text: "/**\n * The answer.\n * Computed slowly.\n */\nlet answer = 40 + 2;",
+			expectedDeclarations: []Declaration{{
+				Name:      "answer",
+				Kind:      "let",
+				Signature: "let answer = 40 + 2;",
+				Doc:       "The answer.\nComputed slowly.",
+				Loc:       parser.Location{Start: 47, End: 62},
+			}},
+		},
+		"given line comments, join them": {
+			text: "// Greets.\n// Politely.\nlet greeting = 'hi';",
+			expectedDeclarations: []Declaration{{
+				Name:      "greeting",
+				Kind:      "let",
+				Signature: `let greeting = "hi";`,
+				Doc:       "Greets.\nPolitely.",
+				Loc:       parser.Location{Start: 28, End: 43},
+			}},
+		},
+		"given comment separated by blank line, ignore it": {
+			text: "// License.\n\n// Counter.\nlet count;",
+			expectedDeclarations: []Declaration{{
+				Name:      "count",
+				Kind:      "let",
+				Signature: "let count;",
+				Doc:       "Counter.",
+				Loc:       parser.Location{Start: 29, End: 34},
+			}},
+		},
+		"given several declarations in one statement, share the doc": {
+			text: "/** Coordinates. */\nlet x = 1, y;\n{ let z; }\nx + y;",
+			expectedDeclarations: []Declaration{
+				{Name: "x", Kind: "let", Signature: "let x = 1;", Doc: "Coordinates.", Loc: parser.Location{Start: 24, End: 29}},
+				{Name: "y", Kind: "let", Signature: "let y;", Doc: "Coordinates.", Loc: parser.Location{Start: 31, End: 32}},
+			},
+		},
+		"given const declaration, return its kind": {
+			text: "/** Retries. */\nconst retries = 3;",
+			expectedDeclarations: []Declaration{
+				{Name: "retries", Kind: "const", Signature: "const retries = 3;", Doc: "Retries.", Loc: parser.Location{Start: 22, End: 33}},
+			},
+		},
+		"given exported declaration, keep export in signature": {
+			text: "/** Limit. */\nexport const limit = 3;",
+			expectedDeclarations: []Declaration{
+				{Name: "limit", Kind: "const", Signature: "export const limit = 3;", Doc: "Limit.", Loc: parser.Location{Start: 27, End: 36}},
+			},
+		},
+		"given destructuring declaration, return every bound name": {
+			text: "/** Options. */\nconst { debug, level: [verbosity] } = options;",
+			expectedDeclarations: []Declaration{
+				{Name: "debug", Kind: "const", Signature: "const { debug, level: [verbosity] } = options;", Doc: "Options.", Loc: parser.Location{Start: 22, End: 61}},
+				{Name: "verbosity", Kind: "const", Signature: "const { debug, level: [verbosity] } = options;", Doc: "Options.", Loc: parser.Location{Start: 22, End: 61}},
+			},
+		},
+		"given undocumented declaration, return empty doc": {
+			text: "let x;",
+			expectedDeclarations: []Declaration{
+				{Name: "x", Kind: "let", Signature: "let x;", Loc: parser.Location{Start: 4, End: 5}},
+			},
+		},
+		"given syntax error, return error": {
+			text:          "let;",
+			expectedError: &parser.SyntaxError{Message: "Unexpected token: ;, expected: IDENTIFIER\n", Loc: parser.Location{Start: 3, End: 4}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			declarations, err := New(Props{}).Run(tc.text)
+			assert.Equal(t, tc.expectedDeclarations, declarations)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	assert.Equal(t, "## x\n\n```\nlet x = 1;\n```\n\nThe x.\n\n## y\n\n```\nlet y;\n```\n", Markdown([]Declaration{
+		{Name: "x", Signature: "let x = 1;", Doc: "The x."},
+		{Name: "y", Signature: "let y;"},
+	}))
+}
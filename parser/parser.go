@@ -3,7 +3,9 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/dlanell/go-rdparser/parser/tokenizer"
 )
@@ -12,20 +14,76 @@ type Parser struct {
 	text      string
 	lookAhead *tokenizer.Token
 	tokenizer *tokenizer.Tokenizer
+	maxDepth  int
+	depth     int
+	locations bool
+	autoSemi  bool
+	end       int
+	err       error
+	pending   []Comment
+	// cover is the first shorthand property initializer `{ a = 1 }` read since the
+	// enclosing AssignmentExpression began, valid only if the object becomes a pattern.
+	cover      *Location
+	allowCover bool
 }
 
+// Props
+// MaxDepth bounds how deeply statements and expressions may nest,
+// DefaultMaxDepth is used when it is left at zero and a negative value disables the
+// limit.
+// Locations records the source range of every node in Node.Loc.
+// Comments attaches comments to the statements and blocks around them in Node.Comments,
+// comments after the last statement are kept in Program.Comments.
+// AutoSemicolons inserts a missing ';' the way JavaScript does: before a token on a
+// new line, before '}' and at the end of the input.
 type Props struct {
-	Text string
+	Text           string
+	MaxDepth       int
+	Locations      bool
+	Comments       bool
+	AutoSemicolons bool
 }
 
 type Program struct {
 	NodeType string
 	Body     []*Node
+	Comments []Comment `json:",omitempty"`
 }
 
 type Node struct {
 	NodeType string
 	Body     interface{}
+	Loc      *Location `json:",omitempty"`
+	Comments *Comments `json:",omitempty"`
+}
+
+// Comments
+// Leading are the comments before a statement, Trailing the comments inside it
+// and those following it on the same line. Inner are the comments of a block after
+// its last statement, before the closing brace.
+type Comments struct {
+	Leading  []Comment `json:",omitempty"`
+	Trailing []Comment `json:",omitempty"`
+	Inner    []Comment `json:",omitempty"`
+}
+
+// Comment is the text of a comment, delimiters included.
+type Comment struct {
+	Value string
+	Loc   Location
+}
+
+// Location is a range of byte offsets into the parsed text, End is exclusive.
+type Location struct {
+	Start int
+	End   int
+}
+
+// SyntaxError is returned when the text does not match the grammar,
+// Loc is the range of the offending token.
+type SyntaxError struct {
+	Message string
+	Loc     Location
 }
 
 type BinaryExpressionNode struct {
@@ -34,21 +92,156 @@ type BinaryExpressionNode struct {
 	Right    interface{}
 }
 
+// VariableStatementValue
+// Kind is the keyword the statement starts with, KindLet, KindConst or KindVar.
+type VariableStatementValue struct {
+	Kind         string
+	Declarations []*Node
+}
+
+// VariableDeclarationValue
+// Type is the optional TypeAnnotation declaring the values the binding holds.
 type VariableDeclarationValue struct {
 	Id   *Node
+	Type *Node `json:",omitempty"`
 	Init *Node
 }
 
+// TypeAnnotationValue
+// Types are the names of the union, an IDENTIFIER or null each.
+type TypeAnnotationValue struct {
+	Types []string
+}
+
 type IfStatementValue struct {
 	Test       *Node
 	Consequent *Node
 	Alternate  *Node
 }
 
+// LabeledStatementValue
+// Label is the Identifier break statements refer to.
+type LabeledStatementValue struct {
+	Label *Node
+	Body  *Node
+}
+
+type SwitchStatementValue struct {
+	Discriminant *Node
+	Cases        []*Node
+}
+
+// SwitchCaseValue
+// Test is nil for the default clause.
+type SwitchCaseValue struct {
+	Test       *Node
+	Consequent []*Node
+}
+
+// TryStatementValue
+// Handler is the CatchClause and Finalizer the finally block, at least one of them is set.
+type TryStatementValue struct {
+	Block     *Node
+	Handler   *Node
+	Finalizer *Node
+}
+
+// CatchClauseValue
+// Param is nil when the catch clause does not bind the exception.
+type CatchClauseValue struct {
+	Param *Node
+	Body  *Node
+}
+
+// ImportDeclarationValue
+// Source is the StringLiteral naming the module.
+type ImportDeclarationValue struct {
+	Specifiers []*Node
+	Source     *Node
+}
+
+// ImportSpecifierValue
+// Local is the name the Imported export is bound to, the same Identifier without 'as'.
+type ImportSpecifierValue struct {
+	Imported *Node
+	Local    *Node
+}
+
+// FunctionValue is the body of function and arrow function expressions.
+// Id is the optional name of a function expression, Body is a BlockStatement or, for
+// an arrow function with Expression set, the expression it returns.
+type FunctionValue struct {
+	Id         *Node
+	Params     []*Node
+	Body       *Node
+	Expression bool
+}
+
+// CallExpressionValue
+// Optional calls skip the rest of their ChainExpression when the callee is null.
+type CallExpressionValue struct {
+	Callee    *Node
+	Arguments []*Node
+	Optional  bool
+}
+
+// MemberExpressionValue
+// Property is the Identifier after the '.', or the expression between the brackets
+// when Computed is set. Optional members skip the rest of their ChainExpression when
+// the object is null.
+type MemberExpressionValue struct {
+	Object   *Node
+	Property *Node
+	Computed bool
+	Optional bool
+}
+
+// PropertyValue is a property of an object literal or pattern, Key is an Identifier,
+// StringLiteral or NumericLiteral. A Shorthand property `{ a }` has the Identifier as
+// both Key and Value, or as the Left of an AssignmentPattern for `{ a = 1 }`.
+type PropertyValue struct {
+	Key       *Node
+	Value     *Node
+	Shorthand bool
+}
+
+// AssignmentPatternValue
+// Right is the default used when the destructured value is null.
+type AssignmentPatternValue struct {
+	Left  *Node
+	Right *Node
+}
+
+type UnaryExpressionValue struct {
+	Operator string
+	Argument *Node
+}
+
+// UpdateExpressionValue
+// Prefix is true for ++x and false for x++.
+type UpdateExpressionValue struct {
+	Operator string
+	Prefix   bool
+	Argument *Node
+}
+
+type ConditionalExpressionValue struct {
+	Test       *Node
+	Consequent *Node
+	Alternate  *Node
+}
+
 type StringLiteralValue struct {
 	Value string
 }
 
+// RegExpLiteralValue
+// Flags holds any of g, i, m and s, Pattern is the text between the slashes.
+type RegExpLiteralValue struct {
+	Pattern string
+	Flags   string
+}
+
 type NumericLiteralValue struct {
 	Value int
 }
@@ -61,29 +254,85 @@ const (
 	Identifier                  = "IDENTIFIER"
 	ExpressionStatement         = "ExpressionStatement"
 	AssignmentExpression        = "AssignmentExpression"
+	ConditionalExpression       = "ConditionalExpression"
 	BlockStatement              = "BlockStatement"
 	BinaryExpression            = "BinaryExpression"
+	UnaryExpression             = "UnaryExpression"
+	UpdateExpression            = "UpdateExpression"
+	FunctionExpression          = "FunctionExpression"
+	ArrowFunctionExpression     = "ArrowFunctionExpression"
+	CallExpression              = "CallExpression"
+	MemberExpression            = "MemberExpression"
+	RegExpLiteral               = "RegExpLiteral"
+	ChainExpression             = "ChainExpression"
+	ArrayExpression             = "ArrayExpression"
+	ObjectExpression            = "ObjectExpression"
+	Property                    = "Property"
+	ArrayPattern                = "ArrayPattern"
+	ObjectPattern               = "ObjectPattern"
+	AssignmentPattern           = "AssignmentPattern"
+	SpreadElement               = "SpreadElement"
+	RestElement                 = "RestElement"
+	ReturnStatement             = "ReturnStatement"
 	EmptyStatement              = "EmptyStatement"
 	IfStatement                 = "IfStatement"
+	SwitchStatement             = "SwitchStatement"
+	SwitchCase                  = "SwitchCase"
+	BreakStatement              = "BreakStatement"
+	LabeledStatement            = "LabeledStatement"
+	ThrowStatement              = "ThrowStatement"
+	TryStatement                = "TryStatement"
+	CatchClause                 = "CatchClause"
+	ImportDeclaration           = "ImportDeclaration"
+	ImportSpecifier             = "ImportSpecifier"
+	ExportDeclaration           = "ExportDeclaration"
 	VariableStatement           = "VariableStatement"
 	VariableDeclaration         = "VariableDeclaration"
+	TypeAnnotation              = "TypeAnnotation"
 	ProgramEnum                 = "Program"
 )
 
+const (
+	KindLet   string = "let"
+	KindConst        = "const"
+	KindVar          = "var"
+)
+
+const DefaultMaxDepth = 256
+
+var ErrMaxDepthExceeded = errors.New("maximum nesting depth exceeded")
+
 func New(props Props) *Parser {
+	maxDepth := props.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
+	}
 	return &Parser{
 		text:      props.Text,
-		tokenizer: tokenizer.New(tokenizer.Props{Text: props.Text}),
+		tokenizer: tokenizer.New(tokenizer.Props{Text: props.Text, Lossless: props.Comments}),
 		lookAhead: nil,
+		maxDepth:  maxDepth,
+		locations: props.Locations,
+		autoSemi:  props.AutoSemicolons,
 	}
 }
 
+func (e *SyntaxError) Error() string {
+	return e.Message
+}
+
 func (p *Parser) Run() (*Program, error) {
 	token, err := p.tokenizer.GetNextToken()
 	if err != nil {
 		return nil, err
 	}
-	p.lookAhead = token
+	p.pending = nil
+	p.lookAhead = p.collect(token)
+	if p.lookAhead == nil {
+		return nil, tokenizer.ErrNoTokens
+	}
+	p.err = nil
+	p.end = 0
 
 	return p.Program()
 }
@@ -103,6 +352,7 @@ func (p *Parser) Program() (*Program, error) {
 	return &Program{
 		NodeType: ProgramEnum,
 		Body:     statements,
+		Comments: p.takeComments(),
 	}, nil
 }
 
@@ -124,6 +374,10 @@ func (p *Parser) StatementList(stopLookAhead string) ([]*Node, error) {
 		}
 		statements = append(statements, statement)
 	}
+	// a token the tokenizer cannot read after a statement ends the input early
+	if p.lookAhead == nil && p.err != nil {
+		return nil, p.err
+	}
 	return statements, nil
 }
 
@@ -133,17 +387,59 @@ func (p *Parser) StatementList(stopLookAhead string) ([]*Node, error) {
 //	| EmptyStatement
 //	| VariableStatement
 //	| IfStatement
+//	| SwitchStatement
+//	| BreakStatement
+//	| LabeledStatement
+//	| ReturnStatement
+//	| ThrowStatement
+//	| TryStatement
+//	| ImportDeclaration
+//	| ExportDeclaration
+//
+// Import and export declarations may only appear at the top level.
 ///*
 func (p *Parser) Statement() (*Node, error) {
-	switch p.lookAhead.TokenType {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	leading := p.takeComments()
+	statement, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	return p.attach(statement, leading), nil
+}
+
+func (p *Parser) statement() (*Node, error) {
+	switch p.lookAheadType() {
 	case tokenizer.SemiColonToken:
 		return p.EmptyStatement()
 	case tokenizer.OpenCurlyBrace:
 		return p.BlockStatement()
-	case tokenizer.LetKeyword:
+	case tokenizer.LetKeyword, tokenizer.ConstKeyword, tokenizer.VarKeyword:
 		return p.VariableStatement()
 	case tokenizer.IfKeyword:
 		return p.IfStatement()
+	case tokenizer.SwitchKeyword:
+		return p.SwitchStatement()
+	case tokenizer.BreakKeyword:
+		return p.BreakStatement()
+	case tokenizer.ReturnKeyword:
+		return p.ReturnStatement()
+	case tokenizer.ThrowKeyword:
+		return p.ThrowStatement()
+	case tokenizer.TryKeyword:
+		return p.TryStatement()
+	case tokenizer.ImportKeyword, tokenizer.ExportKeyword:
+		if p.depth > 1 {
+			return nil, p.unexpected(fmt.Sprintf("Unexpected token: %s, %s declarations may only appear at top level\n", p.lookAhead.Value, p.lookAhead.Value))
+		}
+		if p.lookAheadType() == tokenizer.ImportKeyword {
+			return p.ImportDeclaration()
+		}
+		return p.ExportDeclaration()
 	default:
 		return p.ExpressionStatement()
 	}
@@ -154,6 +450,7 @@ func (p *Parser) Statement() (*Node, error) {
 //	| 'if' '(' Expression ')' Statement 'else' Statement
 ///*
 func (p *Parser) IfStatement() (*Node, error) {
+	start := p.start()
 	_, err := p.eat(tokenizer.IfKeyword)
 	if err != nil {
 		return nil, err
@@ -191,273 +488,1298 @@ func (p *Parser) IfStatement() (*Node, error) {
 		}
 	}
 
-	return &Node{
+	return p.locate(&Node{
 		NodeType: IfStatement,
 		Body: &IfStatementValue{
 			Test:       test,
 			Consequent: consequent,
 			Alternate:  alternate,
 		},
-	}, nil
+	}, start), nil
 }
 
-// VariableStatement
-//	: 'let' VariableDeclarationList ';'
+// SwitchStatement
+//	: 'switch' '(' Expression ')' '{' OptCaseClauses '}'
+//
+// A switch has at most one default clause.
 ///*
-func (p *Parser) VariableStatement() (*Node, error) {
-	_, err := p.eat(tokenizer.LetKeyword)
+func (p *Parser) SwitchStatement() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.SwitchKeyword)
 	if err != nil {
 		return nil, err
 	}
-	declarationList, declarationListErr := p.VariableDeclarationList()
-	if declarationListErr != nil {
-		return nil, declarationListErr
-	}
-
-	_, err = p.eat(tokenizer.SemiColonToken)
+	_, err = p.eat(tokenizer.OpenParentheses)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Node{NodeType: VariableStatement, Body: declarationList}, nil
-}
+	discriminant, discriminantErr := p.Expression()
+	if discriminantErr != nil {
+		return nil, discriminantErr
+	}
 
-// VariableDeclarationList
-//	: VariableDeclarationList ',' VariableDeclaration
-///*
-func (p *Parser) VariableDeclarationList() ([]*Node, error) {
-	declarations := make([]*Node, 0)
+	_, err = p.eat(tokenizer.CloseParentheses)
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.eat(tokenizer.OpenCurlyBrace)
+	if err != nil {
+		return nil, err
+	}
 
-	for ok := true; ok; ok = p.lookAhead.TokenType == tokenizer.Comma {
-		if p.lookAhead.TokenType == tokenizer.Comma {
-			_, err := p.eat(tokenizer.Comma)
-			if err != nil {
-				return nil, err
+	cases := make([]*Node, 0)
+	hasDefault := false
+	for p.lookAheadType() == tokenizer.CaseKeyword || p.lookAheadType() == tokenizer.DefaultKeyword {
+		if p.lookAheadType() == tokenizer.DefaultKeyword {
+			if hasDefault {
+				return nil, p.unexpected("More than one default clause in switch statement")
 			}
+			hasDefault = true
 		}
-		declaration, declarationErr := p.VariableDeclaration()
-		if declarationErr != nil {
-			return nil, declarationErr
+		clause, clauseErr := p.SwitchCase()
+		if clauseErr != nil {
+			return nil, clauseErr
 		}
-		declarations = append(declarations, declaration)
+		cases = append(cases, clause)
 	}
 
-	return declarations, nil
+	_, err = p.eat(tokenizer.CloseCurlyBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.locate(&Node{
+		NodeType: SwitchStatement,
+		Body: &SwitchStatementValue{
+			Discriminant: discriminant,
+			Cases:        cases,
+		},
+	}, start), nil
 }
 
-// VariableDeclaration
-//	: Identifier OptVariableInitialization
+// SwitchCase
+//	: 'case' Expression ':' OptStatementList
+//	| 'default' ':' OptStatementList
 ///*
-func (p *Parser) VariableDeclaration() (*Node, error) {
-	identifier, err := p.Identifier()
+func (p *Parser) SwitchCase() (*Node, error) {
+	start := p.start()
+	var test *Node
+	if p.lookAheadType() == tokenizer.DefaultKeyword {
+		_, err := p.eat(tokenizer.DefaultKeyword)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err := p.eat(tokenizer.CaseKeyword)
+		if err != nil {
+			return nil, err
+		}
+		var testErr error
+		test, testErr = p.Expression()
+		if testErr != nil {
+			return nil, testErr
+		}
+	}
+
+	_, err := p.eat(tokenizer.Colon)
 	if err != nil {
 		return nil, err
 	}
 
-	var init *Node
-	var initErr error
-
-	if p.lookAhead.TokenType != tokenizer.SemiColonToken && p.lookAhead.TokenType != tokenizer.Comma {
-		init, initErr = p.VariableInitializer()
-		if initErr != nil {
-			return nil, initErr
+	consequent := make([]*Node, 0)
+	for p.lookAhead != nil && !isSwitchClauseEnd(p.lookAheadType()) {
+		statement, statementErr := p.Statement()
+		if statementErr != nil {
+			return nil, statementErr
 		}
+		consequent = append(consequent, statement)
 	}
 
-	return &Node{
-		NodeType: VariableDeclaration,
-		Body: &VariableDeclarationValue{
-			Id:   identifier,
-			Init: init,
+	return p.locate(&Node{
+		NodeType: SwitchCase,
+		Body: &SwitchCaseValue{
+			Test:       test,
+			Consequent: consequent,
 		},
-	}, nil
+	}, start), nil
 }
 
-// VariableInitializer
-//	: SIMPLE_ASSIGNMENT AssignmentExpression
+func isSwitchClauseEnd(tokenType string) bool {
+	return tokenType == tokenizer.CaseKeyword ||
+		tokenType == tokenizer.DefaultKeyword ||
+		tokenType == tokenizer.CloseCurlyBrace
+}
+
+// BreakStatement
+//	: 'break' OptIdentifier ';'
 ///*
-func (p *Parser) VariableInitializer() (*Node, error) {
-	_, err := p.eat(tokenizer.SimpleAssignment)
+func (p *Parser) BreakStatement() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.BreakKeyword)
 	if err != nil {
 		return nil, err
 	}
-	return p.AssignmentExpression()
+	label, labelErr := p.label()
+	if labelErr != nil {
+		return nil, labelErr
+	}
+	err = p.semicolon()
+	if err != nil {
+		return nil, err
+	}
+
+	if label == nil {
+		return p.locate(&Node{NodeType: BreakStatement, Body: nil}, start), nil
+	}
+	return p.locate(&Node{NodeType: BreakStatement, Body: label}, start), nil
 }
 
-// EmptyStatement
-//	: ';'
+// label reads the optional label of a break statement, with AutoSemicolons
+// it must be on the line of the keyword.
+func (p *Parser) label() (*Node, error) {
+	if p.lookAheadType() != tokenizer.Identifier || (p.autoSemi && p.newlineBefore()) {
+		return nil, nil
+	}
+	return p.Identifier()
+}
+
+// LabeledStatement
+//	: Identifier ':' Statement
+//
+// The label has already been read as the expression of an ExpressionStatement.
 ///*
-func (p *Parser) EmptyStatement() (*Node, error) {
-	_, err := p.eat(";")
+func (p *Parser) LabeledStatement(label *Node, start int) (*Node, error) {
+	_, err := p.eat(tokenizer.Colon)
 	if err != nil {
 		return nil, err
 	}
+	body, bodyErr := p.Statement()
+	if bodyErr != nil {
+		return nil, bodyErr
+	}
 
-	return &Node{NodeType: EmptyStatement, Body: nil}, nil
+	return p.locate(&Node{
+		NodeType: LabeledStatement,
+		Body: &LabeledStatementValue{
+			Label: label,
+			Body:  body,
+		},
+	}, start), nil
 }
 
-// BlockStatement
-//	: '{' OptStatementList '}'
+// ReturnStatement
+//	: 'return' OptExpression ';'
+//
+// With AutoSemicolons a return followed by a line break returns nothing.
 ///*
-func (p *Parser) BlockStatement() (*Node, error) {
-	_, err := p.eat("{")
+func (p *Parser) ReturnStatement() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.ReturnKeyword)
 	if err != nil {
 		return nil, err
 	}
-	if p.lookAhead.TokenType == tokenizer.CloseCurlyBrace {
-		_, err = p.eat(tokenizer.CloseCurlyBrace)
+
+	var argument *Node
+	if !p.isStatementEnd() {
+		argument, err = p.Expression()
 		if err != nil {
 			return nil, err
 		}
-		return &Node{NodeType: BlockStatement, Body: []*Node{}}, nil
-	}
-	statements, statementsErr := p.StatementList(tokenizer.CloseCurlyBrace)
-	if statementsErr != nil {
-		return nil, err
 	}
-	_, err = p.eat(tokenizer.CloseCurlyBrace)
+	err = p.semicolon()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Node{NodeType: BlockStatement, Body: statements}, nil
+	if argument == nil {
+		return p.locate(&Node{NodeType: ReturnStatement, Body: nil}, start), nil
+	}
+	return p.locate(&Node{NodeType: ReturnStatement, Body: argument}, start), nil
 }
 
-// ExpressionStatement
-//	: Expression ';'
+// isStatementEnd reports whether the statement can end before the look ahead token.
+func (p *Parser) isStatementEnd() bool {
+	switch p.lookAheadType() {
+	case tokenizer.SemiColonToken, tokenizer.CloseCurlyBrace, "":
+		return true
+	}
+	return p.autoSemi && p.newlineBefore()
+}
+
+// ThrowStatement
+//	: 'throw' Expression ';'
+//
+// With AutoSemicolons the expression must start on the line of 'throw'.
 ///*
-func (p *Parser) ExpressionStatement() (*Node, error) {
-	expression, err := p.Expression()
+func (p *Parser) ThrowStatement() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.ThrowKeyword)
 	if err != nil {
 		return nil, err
 	}
-	_, err = p.eat(";")
+	if p.autoSemi && p.newlineBefore() {
+		return nil, p.unexpected("Illegal newline after throw")
+	}
+
+	argument, argumentErr := p.Expression()
+	if argumentErr != nil {
+		return nil, argumentErr
+	}
+	err = p.semicolon()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Node{NodeType: ExpressionStatement, Body: expression}, nil
+	return p.locate(&Node{NodeType: ThrowStatement, Body: argument}, start), nil
 }
 
-// Expression
-//	: AssignmentExpression
-///*
-func (p *Parser) Expression() (*Node, error) {
-	return p.AssignmentExpression()
-}
-
-// AssignmentExpression
-//	: LogicalAndExpression
-//	| LeftHandSideExpression AssignmentOperator EqualityExpression
+// TryStatement
+//	: 'try' BlockStatement CatchClause
+//	| 'try' BlockStatement Finally
+//	| 'try' BlockStatement CatchClause Finally
+//
+// Finally
+//	: 'finally' BlockStatement
 ///*
-func (p *Parser) AssignmentExpression() (*Node, error) {
-	left, err := p.LogicalAndExpression()
+func (p *Parser) TryStatement() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.TryKeyword)
 	if err != nil {
 		return nil, err
 	}
 
-	if !isAssignmentOperator(p.lookAhead.TokenType) {
-		return left, nil
+	block, blockErr := p.BlockStatement()
+	if blockErr != nil {
+		return nil, blockErr
 	}
 
-	assignmentOperatorToken, assignmentOperatorTokenErr := p.AssignmentOperator()
-	if assignmentOperatorTokenErr != nil {
-		return nil, assignmentOperatorTokenErr
+	if p.lookAheadType() != tokenizer.CatchKeyword && p.lookAheadType() != tokenizer.FinallyKeyword {
+		return nil, p.unexpected("Missing catch or finally after try")
 	}
 
-	leftNode, leftNodeErr := checkValidAssignmentTarget(left)
-	if leftNodeErr != nil {
-		return nil, leftNodeErr
+	var handler *Node
+	if p.lookAheadType() == tokenizer.CatchKeyword {
+		var handlerErr error
+		handler, handlerErr = p.CatchClause()
+		if handlerErr != nil {
+			return nil, handlerErr
+		}
 	}
 
-	rightNode, rightNodeErr := p.AssignmentExpression()
-	if rightNodeErr != nil {
-		return nil, rightNodeErr
+	var finalizer *Node
+	if p.lookAheadType() == tokenizer.FinallyKeyword {
+		_, err = p.eat(tokenizer.FinallyKeyword)
+		if err != nil {
+			return nil, err
+		}
+		var finalizerErr error
+		finalizer, finalizerErr = p.BlockStatement()
+		if finalizerErr != nil {
+			return nil, finalizerErr
+		}
 	}
 
-	return &Node{
-		NodeType: AssignmentExpression,
-		Body: &BinaryExpressionNode{
-			Operator: assignmentOperatorToken.Value,
-			Left:     leftNode,
-			Right:    rightNode,
+	return p.locate(&Node{
+		NodeType: TryStatement,
+		Body: &TryStatementValue{
+			Block:     block,
+			Handler:   handler,
+			Finalizer: finalizer,
 		},
-	}, nil
-}
-
-// LeftHandSideExpression
-//	: Identifier
-///*
-func (p *Parser) LeftHandSideExpression() (*Node, error) {
-	return p.Identifier()
+	}, start), nil
 }
 
-// Identifier
-//	: IDENTIFIER
+// CatchClause
+//	: 'catch' '(' Identifier ')' BlockStatement
+//	| 'catch' BlockStatement
 ///*
-func (p *Parser) Identifier() (*Node, error) {
-	token, err := p.eat(tokenizer.Identifier)
+func (p *Parser) CatchClause() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.CatchKeyword)
 	if err != nil {
 		return nil, err
 	}
-	return &Node{
-		NodeType: Identifier,
-		Body:     &StringLiteralValue{token.Value},
-	}, nil
-}
-
-func isAssignmentOperator(tokenType string) bool {
-	return tokenType == tokenizer.SimpleAssignment || tokenType == tokenizer.ComplexAssignment
-}
 
-func checkValidAssignmentTarget(node *Node) (*Node, error) {
-	if node.NodeType == Identifier {
-		return node, nil
+	var param *Node
+	if p.lookAheadType() == tokenizer.OpenParentheses {
+		_, err = p.eat(tokenizer.OpenParentheses)
+		if err != nil {
+			return nil, err
+		}
+		var paramErr error
+		param, paramErr = p.Identifier()
+		if paramErr != nil {
+			return nil, paramErr
+		}
+		_, err = p.eat(tokenizer.CloseParentheses)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return nil, errors.New("invalid Left-hand side in assignment expression")
-}
 
-// AssignmentOperator
-//	: Simple Assignment Token
-//	| Complex Assignment Token
-///*
-func (p *Parser) AssignmentOperator() (*tokenizer.Token, error) {
-	if p.lookAhead.TokenType == tokenizer.SimpleAssignment {
-		return p.eat(tokenizer.SimpleAssignment)
+	body, bodyErr := p.BlockStatement()
+	if bodyErr != nil {
+		return nil, bodyErr
 	}
-	return p.eat(tokenizer.ComplexAssignment)
-}
 
-// LogicalAndExpression
-//	: LogicalOrExpression
-//	| LogicalOrExpression LOGICAL_AND LogicalAndExpression
-///*
-func (p *Parser) LogicalAndExpression() (*Node, error) {
-	return p.genericBinaryExpression(p.LogicalOrExpression, tokenizer.LogicalAnd)
+	return p.locate(&Node{
+		NodeType: CatchClause,
+		Body: &CatchClauseValue{
+			Param: param,
+			Body:  body,
+		},
+	}, start), nil
 }
 
-// LogicalOrExpression
-//	: EqualityExpression
-//	| EqualityExpression LOGICAL_OR LogicalOrExpression
+// ImportDeclaration
+//	: 'import' '{' OptImportSpecifierList '}' 'from' STRING ';'
 ///*
-func (p *Parser) LogicalOrExpression() (*Node, error) {
-	return p.genericBinaryExpression(p.EqualityExpression, tokenizer.LogicalOr)
-}
-
-// EqualityExpression
-//	: RelationalExpression
-//	| RelationalExpression RELATIONAL_OPERATOR EqualityExpression
+func (p *Parser) ImportDeclaration() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.ImportKeyword)
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.eat(tokenizer.OpenCurlyBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	specifiers := make([]*Node, 0)
+	for p.lookAheadType() != tokenizer.CloseCurlyBrace {
+		specifier, specifierErr := p.ImportSpecifier()
+		if specifierErr != nil {
+			return nil, specifierErr
+		}
+		specifiers = append(specifiers, specifier)
+		if p.lookAheadType() != tokenizer.Comma {
+			break
+		}
+		_, err = p.eat(tokenizer.Comma)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.eat(tokenizer.CloseCurlyBrace)
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.eatContextual("from")
+	if err != nil {
+		return nil, err
+	}
+	source, sourceErr := p.StringLiteral()
+	if sourceErr != nil {
+		return nil, sourceErr
+	}
+	err = p.semicolon()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.locate(&Node{
+		NodeType: ImportDeclaration,
+		Body: &ImportDeclarationValue{
+			Specifiers: specifiers,
+			Source:     source,
+		},
+	}, start), nil
+}
+
+// ImportSpecifier
+//	: Identifier
+//	| Identifier 'as' Identifier
+///*
+func (p *Parser) ImportSpecifier() (*Node, error) {
+	start := p.start()
+	imported, err := p.Identifier()
+	if err != nil {
+		return nil, err
+	}
+
+	local := imported
+	if p.lookAheadType() == tokenizer.Identifier && p.lookAhead.Value == "as" {
+		_, err = p.eatContextual("as")
+		if err != nil {
+			return nil, err
+		}
+		local, err = p.Identifier()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p.locate(&Node{
+		NodeType: ImportSpecifier,
+		Body: &ImportSpecifierValue{
+			Imported: imported,
+			Local:    local,
+		},
+	}, start), nil
+}
+
+// ExportDeclaration
+//	: 'export' VariableStatement
+///*
+func (p *Parser) ExportDeclaration() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.ExportKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p.lookAheadType() {
+	case tokenizer.LetKeyword, tokenizer.ConstKeyword, tokenizer.VarKeyword:
+	default:
+		if p.lookAhead == nil {
+			return nil, p.unexpected("Unexpected end of input, expected: declaration\n")
+		}
+		return nil, p.unexpected(fmt.Sprintf("Unexpected token: %s, expected: declaration\n", p.lookAhead.Value))
+	}
+
+	declaration, declarationErr := p.VariableStatement()
+	if declarationErr != nil {
+		return nil, declarationErr
+	}
+
+	return p.locate(&Node{NodeType: ExportDeclaration, Body: declaration}, start), nil
+}
+
+// VariableStatement
+//	: VariableKind VariableDeclarationList ';'
+//
+// VariableKind
+//	: 'let'
+//	| 'const'
+//	| 'var'
+///*
+func (p *Parser) VariableStatement() (*Node, error) {
+	start := p.start()
+	kind, err := p.eat(p.lookAheadType())
+	if err != nil {
+		return nil, err
+	}
+	declarationList, declarationListErr := p.VariableDeclarationList(kind.Value)
+	if declarationListErr != nil {
+		return nil, declarationListErr
+	}
+
+	err = p.semicolon()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.locate(&Node{
+		NodeType: VariableStatement,
+		Body: &VariableStatementValue{
+			Kind:         kind.Value,
+			Declarations: declarationList,
+		},
+	}, start), nil
+}
+
+// VariableDeclarationList
+//	: VariableDeclarationList ',' VariableDeclaration
+///*
+func (p *Parser) VariableDeclarationList(kind string) ([]*Node, error) {
+	declarations := make([]*Node, 0)
+
+	for ok := true; ok; ok = p.lookAheadType() == tokenizer.Comma {
+		if p.lookAheadType() == tokenizer.Comma {
+			_, err := p.eat(tokenizer.Comma)
+			if err != nil {
+				return nil, err
+			}
+		}
+		declaration, declarationErr := p.VariableDeclaration(kind)
+		if declarationErr != nil {
+			return nil, declarationErr
+		}
+		declarations = append(declarations, declaration)
+	}
+
+	return declarations, nil
+}
+
+// VariableDeclaration
+//	: BindingTarget OptTypeAnnotation OptVariableInitialization
+//
+// const declarations and patterns require the initializer.
+///*
+func (p *Parser) VariableDeclaration(kind string) (*Node, error) {
+	start := p.start()
+	identifier, err := p.BindingTarget()
+	if err != nil {
+		return nil, err
+	}
+
+	var annotation *Node
+	if p.lookAheadType() == tokenizer.Colon {
+		annotation, err = p.TypeAnnotation()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var init *Node
+	var initErr error
+
+	if p.hasInitializer() {
+		init, initErr = p.VariableInitializer()
+		if initErr != nil {
+			return nil, initErr
+		}
+	}
+	if init == nil && identifier.NodeType != Identifier {
+		return nil, &SyntaxError{
+			Message: "Missing initializer in destructuring declaration",
+			Loc:     Location{Start: start, End: p.end},
+		}
+	}
+	if init == nil && kind == KindConst {
+		return nil, &SyntaxError{
+			Message: "Missing initializer in const declaration",
+			Loc:     Location{Start: start, End: p.end},
+		}
+	}
+
+	return p.locate(&Node{
+		NodeType: VariableDeclaration,
+		Body: &VariableDeclarationValue{
+			Id:   identifier,
+			Type: annotation,
+			Init: init,
+		},
+	}, start), nil
+}
+
+// TypeAnnotation
+//	: ':' TypeName
+//	| TypeAnnotation '|' TypeName
+//
+// TypeName is an IDENTIFIER or null, the names are not checked here.
+///*
+func (p *Parser) TypeAnnotation() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.Colon)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]string, 0)
+	for ok := true; ok; ok = p.lookAheadType() == tokenizer.BitwiseOrOperator {
+		if len(types) > 0 {
+			_, err = p.eat(tokenizer.BitwiseOrOperator)
+			if err != nil {
+				return nil, err
+			}
+		}
+		tokenType := tokenizer.Identifier
+		if p.lookAheadType() == tokenizer.NullKeyword {
+			tokenType = tokenizer.NullKeyword
+		}
+		token, tokenErr := p.eat(tokenType)
+		if tokenErr != nil {
+			return nil, tokenErr
+		}
+		types = append(types, token.Value)
+	}
+
+	return p.locate(&Node{
+		NodeType: TypeAnnotation,
+		Body:     &TypeAnnotationValue{Types: types},
+	}, start), nil
+}
+
+// hasInitializer reports whether the declaration goes on with an initializer, with
+// AutoSemicolons anything other than '=' ends it.
+func (p *Parser) hasInitializer() bool {
+	if p.autoSemi {
+		return p.lookAheadType() == tokenizer.SimpleAssignment
+	}
+	return p.lookAheadType() != tokenizer.SemiColonToken && p.lookAheadType() != tokenizer.Comma
+}
+
+// VariableInitializer
+//	: SIMPLE_ASSIGNMENT AssignmentExpression
+///*
+func (p *Parser) VariableInitializer() (*Node, error) {
+	_, err := p.eat(tokenizer.SimpleAssignment)
+	if err != nil {
+		return nil, err
+	}
+	return p.AssignmentExpression()
+}
+
+// BindingTarget
+//	: Identifier
+//	| ObjectPattern
+//	| ArrayPattern
+///*
+func (p *Parser) BindingTarget() (*Node, error) {
+	switch p.lookAheadType() {
+	case tokenizer.OpenCurlyBrace:
+		return p.ObjectPattern()
+	case tokenizer.OpenBracket:
+		return p.ArrayPattern()
+	}
+	return p.Identifier()
+}
+
+// BindingElement
+//	: BindingTarget
+//	| BindingTarget VariableInitializer
+///*
+func (p *Parser) BindingElement() (*Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	start := p.start()
+	target, err := p.BindingTarget()
+	if err != nil {
+		return nil, err
+	}
+	if p.lookAheadType() != tokenizer.SimpleAssignment {
+		return target, nil
+	}
+	init, err := p.VariableInitializer()
+	if err != nil {
+		return nil, err
+	}
+	return p.assignmentPattern(target, init, start), nil
+}
+
+// ObjectPattern
+//	: '{' OptBindingPropertyList '}'
+//	| '{' BindingPropertyList ',' RestElement '}'
+//
+// BindingProperty
+//	: PropertyName ':' BindingElement
+//	| Identifier
+//	| Identifier VariableInitializer
+///*
+func (p *Parser) ObjectPattern() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.OpenCurlyBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make([]*Node, 0)
+	for p.lookAheadType() != tokenizer.CloseCurlyBrace {
+		if p.lookAheadType() == tokenizer.Spread {
+			rest, restErr := p.RestElement(p.Identifier, tokenizer.CloseCurlyBrace, "Rest element must be last element")
+			if restErr != nil {
+				return nil, restErr
+			}
+			properties = append(properties, rest)
+			break
+		}
+
+		propertyStart := p.start()
+		key, keyErr := p.PropertyName()
+		if keyErr != nil {
+			return nil, keyErr
+		}
+
+		var property *Node
+		if key.NodeType != Identifier || p.lookAheadType() == tokenizer.Colon {
+			_, err = p.eat(tokenizer.Colon)
+			if err != nil {
+				return nil, err
+			}
+			value, valueErr := p.BindingElement()
+			if valueErr != nil {
+				return nil, valueErr
+			}
+			property = p.property(key, value, false, propertyStart)
+		} else if p.lookAheadType() == tokenizer.SimpleAssignment {
+			init, initErr := p.VariableInitializer()
+			if initErr != nil {
+				return nil, initErr
+			}
+			property = p.property(key, p.assignmentPattern(key, init, propertyStart), true, propertyStart)
+		} else {
+			property = p.property(key, key, true, propertyStart)
+		}
+		properties = append(properties, property)
+
+		if p.lookAheadType() != tokenizer.Comma {
+			break
+		}
+		_, err = p.eat(tokenizer.Comma)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.eat(tokenizer.CloseCurlyBrace)
+	if err != nil {
+		return nil, err
+	}
+	return p.locate(&Node{NodeType: ObjectPattern, Body: properties}, start), nil
+}
+
+// ArrayPattern
+//	: '[' OptBindingElementList ']'
+//	| '[' OptBindingElementList RestElement ']'
+//
+// BindingElementList
+//	: OptBindingElement
+//	| BindingElementList ',' OptBindingElement
+//
+// A missing element skips a value and is nil.
+///*
+func (p *Parser) ArrayPattern() (*Node, error) {
+	start := p.start()
+	elements, err := p.elements(func() (*Node, error) {
+		if p.lookAheadType() == tokenizer.Spread {
+			return p.RestElement(p.BindingTarget, tokenizer.CloseBracket, "Rest element must be last element")
+		}
+		return p.BindingElement()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p.locate(&Node{NodeType: ArrayPattern, Body: elements}, start), nil
+}
+
+// elements parses the elements between brackets, a comma without an element before it
+// leaves a nil hole.
+func (p *Parser) elements(element func() (*Node, error)) ([]*Node, error) {
+	_, err := p.eat(tokenizer.OpenBracket)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]*Node, 0)
+	for p.lookAheadType() != tokenizer.CloseBracket {
+		if p.lookAheadType() == tokenizer.Comma {
+			_, err = p.eat(tokenizer.Comma)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, nil)
+			continue
+		}
+
+		node, nodeErr := element()
+		if nodeErr != nil {
+			return nil, nodeErr
+		}
+		elements = append(elements, node)
+
+		if p.lookAheadType() != tokenizer.Comma {
+			break
+		}
+		_, err = p.eat(tokenizer.Comma)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.eat(tokenizer.CloseBracket)
+	if err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
+// RestElement
+//	: '...' BindingTarget
+//
+// A rest element collects what is left and so must come right before the closing
+// token.
+///*
+func (p *Parser) RestElement(target func() (*Node, error), closing string, message string) (*Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	start := p.start()
+	_, err := p.eat(tokenizer.Spread)
+	if err != nil {
+		return nil, err
+	}
+	argument, err := target()
+	if err != nil {
+		return nil, err
+	}
+	if p.lookAheadType() != closing {
+		return nil, &SyntaxError{Message: message, Loc: Location{Start: start, End: p.end}}
+	}
+	return p.locate(&Node{NodeType: RestElement, Body: argument}, start), nil
+}
+
+// SpreadElement
+//	: '...' AssignmentExpression
+///*
+func (p *Parser) SpreadElement() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.Spread)
+	if err != nil {
+		return nil, err
+	}
+	argument, err := p.AssignmentExpression()
+	if err != nil {
+		return nil, err
+	}
+	return p.locate(&Node{NodeType: SpreadElement, Body: argument}, start), nil
+}
+
+// PropertyName
+//	: Identifier
+//	| StringLiteral
+//	| NumericLiteral
+///*
+func (p *Parser) PropertyName() (*Node, error) {
+	switch p.lookAheadType() {
+	case tokenizer.StringToken:
+		return p.StringLiteral()
+	case tokenizer.NumberToken:
+		return p.NumericLiteral()
+	}
+	return p.Identifier()
+}
+
+func (p *Parser) property(key *Node, value *Node, shorthand bool, start int) *Node {
+	return p.locate(&Node{
+		NodeType: Property,
+		Body: &PropertyValue{
+			Key:       key,
+			Value:     value,
+			Shorthand: shorthand,
+		},
+	}, start)
+}
+
+func (p *Parser) assignmentPattern(left *Node, right *Node, start int) *Node {
+	return p.locate(&Node{
+		NodeType: AssignmentPattern,
+		Body: &AssignmentPatternValue{
+			Left:  left,
+			Right: right,
+		},
+	}, start)
+}
+
+// EmptyStatement
+//	: ';'
+///*
+func (p *Parser) EmptyStatement() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(";")
+	if err != nil {
+		return nil, err
+	}
+
+	return p.locate(&Node{NodeType: EmptyStatement, Body: nil}, start), nil
+}
+
+// BlockStatement
+//	: '{' OptStatementList '}'
+///*
+func (p *Parser) BlockStatement() (*Node, error) {
+	start := p.start()
+	_, err := p.eat("{")
+	if err != nil {
+		return nil, err
+	}
+	statements := make([]*Node, 0)
+	if p.lookAheadType() != tokenizer.CloseCurlyBrace {
+		statements, err = p.StatementList(tokenizer.CloseCurlyBrace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	inner := p.innerComments(start)
+	_, err = p.eat(tokenizer.CloseCurlyBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	node := p.locate(&Node{NodeType: BlockStatement, Body: statements}, start)
+	if len(inner) > 0 {
+		node.Comments = &Comments{Inner: inner}
+	}
+	return node, nil
+}
+
+// ExpressionStatement
+//	: Expression ';'
+//
+// An Identifier followed by ':' starts a LabeledStatement instead.
+///*
+func (p *Parser) ExpressionStatement() (*Node, error) {
+	start := p.start()
+	startsWithIdentifier := p.lookAheadType() == tokenizer.Identifier
+	expression, err := p.Expression()
+	if err != nil {
+		return nil, err
+	}
+	if startsWithIdentifier && expression.NodeType == Identifier && p.lookAheadType() == tokenizer.Colon {
+		return p.LabeledStatement(expression, start)
+	}
+	err = p.semicolon()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.locate(&Node{NodeType: ExpressionStatement, Body: expression}, start), nil
+}
+
+// Expression
+//	: AssignmentExpression
+///*
+func (p *Parser) Expression() (*Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	return p.AssignmentExpression()
+}
+
+// AssignmentExpression
+//	: ConditionalExpression
+//	| LeftHandSideExpression AssignmentOperator EqualityExpression
+//
+// The left side of '=' may be an object or array literal destructuring the value, it
+// is reinterpreted as a pattern.
+///*
+func (p *Parser) AssignmentExpression() (*Node, error) {
+	allowCover := p.allowCover
+	p.allowCover = false
+	outerCover := p.cover
+	p.cover = nil
+
+	start := p.start()
+	left, err := p.ConditionalExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAssignmentOperator(p.lookAheadType()) {
+		if p.cover != nil && !allowCover {
+			return nil, &SyntaxError{Message: "Invalid shorthand property initializer", Loc: *p.cover}
+		}
+		if outerCover != nil {
+			p.cover = outerCover
+		}
+		return left, nil
+	}
+	p.cover = outerCover
+
+	loc := Location{Start: start, End: p.end}
+	var leftNode *Node
+	var leftNodeErr error
+	if p.lookAheadType() == tokenizer.SimpleAssignment {
+		leftNode, leftNodeErr = checkValidAssignmentPattern(left, loc)
+	} else {
+		leftNode, leftNodeErr = checkValidAssignmentTarget(left, loc, "assignment expression")
+	}
+	if leftNodeErr != nil {
+		return nil, leftNodeErr
+	}
+
+	assignmentOperatorToken, assignmentOperatorTokenErr := p.AssignmentOperator()
+	if assignmentOperatorTokenErr != nil {
+		return nil, assignmentOperatorTokenErr
+	}
+
+	rightNode, rightNodeErr := p.Expression()
+	if rightNodeErr != nil {
+		return nil, rightNodeErr
+	}
+
+	return p.locate(&Node{
+		NodeType: AssignmentExpression,
+		Body: &BinaryExpressionNode{
+			Operator: assignmentOperatorToken.Value,
+			Left:     leftNode,
+			Right:    rightNode,
+		},
+	}, start), nil
+}
+
+// ConditionalExpression
+//	: ShortCircuitExpression
+//	| ShortCircuitExpression '?' AssignmentExpression ':' AssignmentExpression
+///*
+func (p *Parser) ConditionalExpression() (*Node, error) {
+	start := p.start()
+	test, err := p.ShortCircuitExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.lookAheadType() != tokenizer.QuestionMark {
+		return test, nil
+	}
+	_, err = p.eat(tokenizer.QuestionMark)
+	if err != nil {
+		return nil, err
+	}
+
+	consequent, consequentErr := p.Expression()
+	if consequentErr != nil {
+		return nil, consequentErr
+	}
+
+	_, err = p.eat(tokenizer.Colon)
+	if err != nil {
+		return nil, err
+	}
+
+	alternate, alternateErr := p.Expression()
+	if alternateErr != nil {
+		return nil, alternateErr
+	}
+
+	return p.locate(&Node{
+		NodeType: ConditionalExpression,
+		Body: &ConditionalExpressionValue{
+			Test:       test,
+			Consequent: consequent,
+			Alternate:  alternate,
+		},
+	}, start), nil
+}
+
+// LeftHandSideExpression
+//	: Identifier
+///*
+func (p *Parser) LeftHandSideExpression() (*Node, error) {
+	return p.Identifier()
+}
+
+// Identifier
+//	: IDENTIFIER
+///*
+func (p *Parser) Identifier() (*Node, error) {
+	start := p.start()
+	token, err := p.eat(tokenizer.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	return p.locate(&Node{
+		NodeType: Identifier,
+		Body:     &StringLiteralValue{token.Value},
+	}, start), nil
+}
+
+func isAssignmentOperator(tokenType string) bool {
+	return tokenType == tokenizer.SimpleAssignment || tokenType == tokenizer.ComplexAssignment
+}
+
+// checkValidAssignmentTarget reports whether the node can be assigned to, operation
+// names the expression assigning to it in the error.
+func checkValidAssignmentTarget(node *Node, loc Location, operation string) (*Node, error) {
+	if node.NodeType == Identifier {
+		return node, nil
+	}
+	return nil, &SyntaxError{
+		Message: "invalid Left-hand side in " + operation,
+		Loc:     loc,
+	}
+}
+
+// checkValidAssignmentPattern reinterprets object and array literals on the left of '='
+// as the patterns they spell, with `x = 1` elements becoming defaults.
+func checkValidAssignmentPattern(node *Node, loc Location) (*Node, error) {
+	switch node.NodeType {
+	case ArrayExpression:
+		elements := make([]*Node, 0, len(node.Body.([]*Node)))
+		for index, element := range node.Body.([]*Node) {
+			if element == nil {
+				elements = append(elements, nil)
+				continue
+			}
+			if element.NodeType == SpreadElement {
+				if index != len(node.Body.([]*Node))-1 {
+					return nil, &SyntaxError{Message: "Rest element must be last element", Loc: loc}
+				}
+				rest, err := checkValidAssignmentPattern(element.Body.(*Node), loc)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, &Node{NodeType: RestElement, Body: rest, Loc: element.Loc})
+				continue
+			}
+			pattern, err := checkValidPatternElement(element, loc)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, pattern)
+		}
+		return &Node{NodeType: ArrayPattern, Body: elements, Loc: node.Loc}, nil
+	case ObjectExpression:
+		properties := make([]*Node, 0, len(node.Body.([]*Node)))
+		for index, property := range node.Body.([]*Node) {
+			if property.NodeType == SpreadElement {
+				if index != len(node.Body.([]*Node))-1 {
+					return nil, &SyntaxError{Message: "Rest element must be last element", Loc: loc}
+				}
+				rest, err := checkValidAssignmentTarget(property.Body.(*Node), loc, "assignment expression")
+				if err != nil {
+					return nil, err
+				}
+				properties = append(properties, &Node{NodeType: RestElement, Body: rest, Loc: property.Loc})
+				continue
+			}
+			value := property.Body.(*PropertyValue)
+			pattern, err := checkValidPatternElement(value.Value, loc)
+			if err != nil {
+				return nil, err
+			}
+			properties = append(properties, &Node{
+				NodeType: Property,
+				Body:     &PropertyValue{Key: value.Key, Value: pattern, Shorthand: value.Shorthand},
+				Loc:      property.Loc,
+			})
+		}
+		return &Node{NodeType: ObjectPattern, Body: properties, Loc: node.Loc}, nil
+	}
+	return checkValidAssignmentTarget(node, loc, "assignment expression")
+}
+
+// checkValidPatternElement converts an element of a literal that became a pattern, an
+// assignment inside it has already checked its own left side.
+func checkValidPatternElement(node *Node, loc Location) (*Node, error) {
+	switch node.NodeType {
+	case AssignmentPattern:
+		return node, nil
+	case AssignmentExpression:
+		value := node.Body.(*BinaryExpressionNode)
+		if value.Operator != "=" {
+			break
+		}
+		return &Node{
+			NodeType: AssignmentPattern,
+			Body:     &AssignmentPatternValue{Left: value.Left.(*Node), Right: value.Right.(*Node)},
+			Loc:      node.Loc,
+		}, nil
+	}
+	return checkValidAssignmentPattern(node, loc)
+}
+
+// AssignmentOperator
+//	: Simple Assignment Token
+//	| Complex Assignment Token
+///*
+func (p *Parser) AssignmentOperator() (*tokenizer.Token, error) {
+	if p.lookAheadType() == tokenizer.SimpleAssignment {
+		return p.eat(tokenizer.SimpleAssignment)
+	}
+	return p.eat(tokenizer.ComplexAssignment)
+}
+
+// ShortCircuitExpression
+//	: LogicalAndExpression
+//	| CoalesceExpression
+//
+// LogicalAndExpression
+//	: LogicalOrExpression
+//	| LogicalOrExpression LOGICAL_AND LogicalAndExpression
+//
+// CoalesceExpression
+//	: BitwiseOrExpression
+//	| CoalesceExpression '??' BitwiseOrExpression
+//
+// '??' cannot be mixed with LOGICAL_AND or LOGICAL_OR without parentheses, so both
+// start from the same BitwiseOrExpression and the first operator decides.
+///*
+func (p *Parser) ShortCircuitExpression() (*Node, error) {
+	start := p.start()
+	left, err := p.BitwiseOrExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.lookAheadType() == tokenizer.NullishCoalescing {
+		left, err = p.binaryExpressionTail(left, start, p.BitwiseOrExpression, tokenizer.NullishCoalescing)
+		if err != nil {
+			return nil, err
+		}
+		if p.lookAheadType() == tokenizer.LogicalAnd || p.lookAheadType() == tokenizer.LogicalOr {
+			return nil, p.unexpected("Cannot mix ?? with && or || without parentheses")
+		}
+		return left, nil
+	}
+
+	left, err = p.binaryExpressionTail(left, start, p.BitwiseOrExpression, tokenizer.LogicalOr)
+	if err != nil {
+		return nil, err
+	}
+	left, err = p.binaryExpressionTail(left, start, p.LogicalOrExpression, tokenizer.LogicalAnd)
+	if err != nil {
+		return nil, err
+	}
+	if p.lookAheadType() == tokenizer.NullishCoalescing {
+		return nil, p.unexpected("Cannot mix ?? with && or || without parentheses")
+	}
+	return left, nil
+}
+
+// LogicalOrExpression
+//	: BitwiseOrExpression
+//	| BitwiseOrExpression LOGICAL_OR LogicalOrExpression
+///*
+func (p *Parser) LogicalOrExpression() (*Node, error) {
+	return p.genericBinaryExpression(p.BitwiseOrExpression, tokenizer.LogicalOr)
+}
+
+// BitwiseOrExpression
+//	: BitwiseXorExpression
+//	| BitwiseXorExpression '|' BitwiseOrExpression
+///*
+func (p *Parser) BitwiseOrExpression() (*Node, error) {
+	return p.genericBinaryExpression(p.BitwiseXorExpression, tokenizer.BitwiseOrOperator)
+}
+
+// BitwiseXorExpression
+//	: BitwiseAndExpression
+//	| BitwiseAndExpression '^' BitwiseXorExpression
+///*
+func (p *Parser) BitwiseXorExpression() (*Node, error) {
+	return p.genericBinaryExpression(p.BitwiseAndExpression, tokenizer.BitwiseXorOperator)
+}
+
+// BitwiseAndExpression
+//	: EqualityExpression
+//	| EqualityExpression '&' BitwiseAndExpression
+///*
+func (p *Parser) BitwiseAndExpression() (*Node, error) {
+	return p.genericBinaryExpression(p.EqualityExpression, tokenizer.BitwiseAndOperator)
+}
+
+// EqualityExpression
+//	: RelationalExpression
+//	| RelationalExpression EQUALITY_OPERATOR EqualityExpression
 ///*
 func (p *Parser) EqualityExpression() (*Node, error) {
 	return p.genericBinaryExpression(p.RelationalExpression, tokenizer.EqualityOperator)
 }
 
 // RelationalExpression
-//	: AdditiveExpression
-//	| AdditiveExpression RELATIONAL_OPERATOR RelationalExpression
+//	: ShiftExpression
+//	| ShiftExpression RELATIONAL_OPERATOR RelationalExpression
 ///*
 func (p *Parser) RelationalExpression() (*Node, error) {
-	return p.genericBinaryExpression(p.AdditiveExpression, tokenizer.RelationalOperator)
+	return p.genericBinaryExpression(p.ShiftExpression, tokenizer.RelationalOperator)
+}
+
+// ShiftExpression
+//	: AdditiveExpression
+//	| AdditiveExpression SHIFT_OPERATOR ShiftExpression
+///*
+func (p *Parser) ShiftExpression() (*Node, error) {
+	return p.genericBinaryExpression(p.AdditiveExpression, tokenizer.ShiftOperator)
 }
 
 // AdditiveExpression
@@ -468,61 +1790,403 @@ func (p *Parser) AdditiveExpression() (*Node, error) {
 	return p.genericBinaryExpression(p.MultiplicativeExpression, tokenizer.AdditiveOperator)
 }
 
-// MultiplicativeExpression
-//	: PrimaryExpression
-//	| PrimaryExpression MultiplicativeOperator MultiplicativeExpression
+// MultiplicativeExpression
+//	: ExponentExpression
+//	| ExponentExpression MultiplicativeOperator MultiplicativeExpression
+///*
+func (p *Parser) MultiplicativeExpression() (*Node, error) {
+	return p.genericBinaryExpression(p.ExponentExpression, tokenizer.MultiplicativeOperator)
+}
+
+// ExponentExpression
+//	: UnaryExpression
+//	| UpdateExpression '**' ExponentExpression
+//
+// '**' is right-associative, an unparenthesized unary operand on its left is ambiguous
+// and rejected.
+///*
+func (p *Parser) ExponentExpression() (*Node, error) {
+	start := p.start()
+	unary := isUnaryOperator(p.lookAheadType())
+	left, err := p.UnaryExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.lookAheadType() != tokenizer.ExponentOperator {
+		return left, nil
+	}
+	if unary {
+		return nil, p.unexpected("Unary operator used immediately before exponentiation expression. Parenthesis must be used to disambiguate operator precedence")
+	}
+	operator, err := p.eat(tokenizer.ExponentOperator)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	right, err := p.ExponentExpression()
+	p.leave()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.locate(&Node{
+		NodeType: BinaryExpression,
+		Body: &BinaryExpressionNode{
+			Operator: operator.Value,
+			Left:     left,
+			Right:    right,
+		},
+	}, start), nil
+}
+
+// UnaryExpression
+//	: UpdateExpression
+//	| Additive_Operator UnaryExpression
+//	| '~' UnaryExpression
+///*
+func (p *Parser) UnaryExpression() (*Node, error) {
+	if !isUnaryOperator(p.lookAheadType()) {
+		return p.UpdateExpression()
+	}
+
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	start := p.start()
+	operator, err := p.eat(p.lookAheadType())
+	if err != nil {
+		return nil, err
+	}
+	argument, err := p.UnaryExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.locate(&Node{
+		NodeType: UnaryExpression,
+		Body: &UnaryExpressionValue{
+			Operator: operator.Value,
+			Argument: argument,
+		},
+	}, start), nil
+}
+
+func isUnaryOperator(tokenType string) bool {
+	return tokenType == tokenizer.AdditiveOperator || tokenType == tokenizer.BitwiseNotOperator
+}
+
+// UpdateExpression
+//	: CallExpression
+//	| CallExpression UPDATE_OPERATOR
+//	| UPDATE_OPERATOR UnaryExpression
+//
+// With AutoSemicolons a postfix operator must be on the line of its operand.
+///*
+func (p *Parser) UpdateExpression() (*Node, error) {
+	start := p.start()
+	if p.lookAheadType() == tokenizer.UpdateOperator {
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+
+		operator, err := p.eat(tokenizer.UpdateOperator)
+		if err != nil {
+			return nil, err
+		}
+		argumentStart := p.start()
+		// an operand starting with an operator is never assignable, reject it before
+		// reading the operators after it
+		if next := p.lookAheadType(); next == tokenizer.UpdateOperator || isUnaryOperator(next) {
+			end := argumentStart + len(p.lookAhead.Value)
+			return nil, &SyntaxError{Message: "invalid Left-hand side in prefix operation", Loc: Location{Start: argumentStart, End: end}}
+		}
+		argument, err := p.UnaryExpression()
+		if err != nil {
+			return nil, err
+		}
+		argument, err = checkValidAssignmentTarget(argument, Location{Start: argumentStart, End: p.end}, "prefix operation")
+		if err != nil {
+			return nil, err
+		}
+		return p.updateExpression(operator.Value, true, argument, start), nil
+	}
+
+	argument, err := p.CallExpression()
+	if err != nil {
+		return nil, err
+	}
+	if p.lookAheadType() != tokenizer.UpdateOperator || (p.autoSemi && p.newlineBefore()) {
+		return argument, nil
+	}
+
+	argument, err = checkValidAssignmentTarget(argument, Location{Start: start, End: p.end}, "postfix operation")
+	if err != nil {
+		return nil, err
+	}
+	operator, err := p.eat(tokenizer.UpdateOperator)
+	if err != nil {
+		return nil, err
+	}
+	return p.updateExpression(operator.Value, false, argument, start), nil
+}
+
+func (p *Parser) updateExpression(operator string, prefix bool, argument *Node, start int) *Node {
+	return p.locate(&Node{
+		NodeType: UpdateExpression,
+		Body: &UpdateExpressionValue{
+			Operator: operator,
+			Prefix:   prefix,
+			Argument: argument,
+		},
+	}, start)
+}
+
+func (p *Parser) genericBinaryExpression(expression func() (*Node, error), operatorToken string) (*Node, error) {
+	start := p.start()
+	left, err := expression()
+	if err != nil {
+		return nil, err
+	}
+	return p.binaryExpressionTail(left, start, expression, operatorToken)
+}
+
+// binaryExpressionTail continues a left associative chain of the operator from its
+// first operand.
+func (p *Parser) binaryExpressionTail(left *Node, start int, expression func() (*Node, error), operatorToken string) (*Node, error) {
+	for p.lookAheadType() == operatorToken {
+		operator, operatorErr := p.eat(operatorToken)
+		if operatorErr != nil {
+			return nil, operatorErr
+		}
+		right, rightErr := expression()
+		if rightErr != nil {
+			return nil, rightErr
+		}
+
+		left = p.locate(&Node{
+			NodeType: BinaryExpression,
+			Body: &BinaryExpressionNode{
+				Operator: operator.Value,
+				Left:     left,
+				Right:    right,
+			},
+		}, start)
+	}
+	return left, nil
+}
+
+// CallExpression
+//	: PrimaryExpression
+//	| CallExpression OptOptionalChaining Arguments
+//	| CallExpression OptOptionalChaining '[' Expression ']'
+//	| CallExpression '.' Identifier
+//	| CallExpression '?.' Identifier
+//
+// A call or member expression holding an optional link is wrapped in a ChainExpression,
+// the extent of what a null before '?.' skips.
+///*
+func (p *Parser) CallExpression() (*Node, error) {
+	start := p.start()
+	expression, err := p.PrimaryExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	chain := false
+	for {
+		optional := p.lookAheadType() == tokenizer.OptionalChaining
+		if optional {
+			_, err = p.eat(tokenizer.OptionalChaining)
+			if err != nil {
+				return nil, err
+			}
+			chain = true
+			if p.lookAheadType() != tokenizer.OpenParentheses && p.lookAheadType() != tokenizer.OpenBracket {
+				property, propertyErr := p.Identifier()
+				if propertyErr != nil {
+					return nil, propertyErr
+				}
+				expression = p.locate(&Node{
+					NodeType: MemberExpression,
+					Body: &MemberExpressionValue{
+						Object:   expression,
+						Property: property,
+						Optional: true,
+					},
+				}, start)
+				continue
+			}
+		}
+
+		switch p.lookAheadType() {
+		case tokenizer.OpenParentheses:
+			arguments, argumentsErr := p.Arguments()
+			if argumentsErr != nil {
+				return nil, argumentsErr
+			}
+			expression = p.locate(&Node{
+				NodeType: CallExpression,
+				Body: &CallExpressionValue{
+					Callee:    expression,
+					Arguments: arguments,
+					Optional:  optional,
+				},
+			}, start)
+		case tokenizer.OpenBracket:
+			_, err = p.eat(tokenizer.OpenBracket)
+			if err != nil {
+				return nil, err
+			}
+			property, propertyErr := p.Expression()
+			if propertyErr != nil {
+				return nil, propertyErr
+			}
+			_, err = p.eat(tokenizer.CloseBracket)
+			if err != nil {
+				return nil, err
+			}
+			expression = p.locate(&Node{
+				NodeType: MemberExpression,
+				Body: &MemberExpressionValue{
+					Object:   expression,
+					Property: property,
+					Computed: true,
+					Optional: optional,
+				},
+			}, start)
+		case tokenizer.Dot:
+			_, err = p.eat(tokenizer.Dot)
+			if err != nil {
+				return nil, err
+			}
+			property, propertyErr := p.Identifier()
+			if propertyErr != nil {
+				return nil, propertyErr
+			}
+			expression = p.locate(&Node{
+				NodeType: MemberExpression,
+				Body: &MemberExpressionValue{
+					Object:   expression,
+					Property: property,
+				},
+			}, start)
+		default:
+			if chain {
+				expression = p.locate(&Node{NodeType: ChainExpression, Body: expression}, start)
+			}
+			return expression, nil
+		}
+	}
+}
+
+// Arguments
+//	: '(' OptArgumentList ')'
+//
+// ArgumentList
+//	: Argument
+//	| ArgumentList ',' Argument
+//
+// Argument
+//	: AssignmentExpression
+//	| SpreadElement
 ///*
-func (p *Parser) MultiplicativeExpression() (*Node, error) {
-	return p.genericBinaryExpression(p.PrimaryExpression, tokenizer.MultiplicativeOperator)
-}
-
-func (p *Parser) genericBinaryExpression(expression func() (*Node, error), operatorToken string) (*Node, error) {
-	left, err := expression()
+func (p *Parser) Arguments() ([]*Node, error) {
+	_, err := p.eat(tokenizer.OpenParentheses)
 	if err != nil {
 		return nil, err
 	}
 
-	for p.lookAhead.TokenType == operatorToken {
-		operator, operatorErr := p.eat(operatorToken)
-		if operatorErr != nil {
-			return nil, operatorErr
+	arguments := make([]*Node, 0)
+	for p.lookAheadType() != tokenizer.CloseParentheses {
+		var argument *Node
+		var argumentErr error
+		if p.lookAheadType() == tokenizer.Spread {
+			argument, argumentErr = p.SpreadElement()
+		} else {
+			argument, argumentErr = p.Expression()
 		}
-		right, rightErr := expression()
-		if rightErr != nil {
-			return nil, rightErr
+		if argumentErr != nil {
+			return nil, argumentErr
 		}
-
-		left = &Node{
-			NodeType: BinaryExpression,
-			Body: &BinaryExpressionNode{
-				Operator: operator.Value,
-				Left:     left,
-				Right:    right,
-			},
+		arguments = append(arguments, argument)
+		if p.lookAheadType() != tokenizer.Comma {
+			break
+		}
+		_, err = p.eat(tokenizer.Comma)
+		if err != nil {
+			return nil, err
 		}
 	}
-	return left, nil
+
+	_, err = p.eat(tokenizer.CloseParentheses)
+	if err != nil {
+		return nil, err
+	}
+	return arguments, nil
 }
 
 // PrimaryExpression
 //	: Literal
 //	| ParenthesizedExpression
+//	| ArrayExpression
+//	| ObjectExpression
+//	| FunctionExpression
+//	| ArrowFunctionExpression
 //	| LeftHandSideExpression
 ///*
 func (p *Parser) PrimaryExpression() (*Node, error) {
-	if isLiteral(p.lookAhead.TokenType) {
+	if err := p.readRegExp(); err != nil {
+		return nil, err
+	}
+	if isLiteral(p.lookAheadType()) {
 		return p.Literal()
 	}
-	switch p.lookAhead.TokenType {
+	switch p.lookAheadType() {
 	case tokenizer.OpenParentheses:
 		return p.ParenthesizedExpression()
-	default:
-		return p.LeftHandSideExpression()
+	case tokenizer.OpenBracket:
+		return p.ArrayExpression()
+	case tokenizer.OpenCurlyBrace:
+		return p.ObjectExpression()
+	case tokenizer.FunctionKeyword:
+		return p.FunctionExpression()
+	}
+
+	start := p.start()
+	expression, err := p.LeftHandSideExpression()
+	if err != nil {
+		return nil, err
+	}
+	if p.lookAheadType() == tokenizer.Arrow {
+		return p.ArrowFunctionExpression([]*Node{expression}, start)
+	}
+	return expression, nil
+}
+
+// readRegExp turns a look ahead '/' or '/=' into the regular expression literal it starts,
+// the tokenizer reads them as operators after tokens that may end an operand.
+func (p *Parser) readRegExp() error {
+	if p.lookAhead == nil || !strings.HasPrefix(p.lookAhead.Value, "/") ||
+		(p.lookAhead.TokenType != tokenizer.MultiplicativeOperator && p.lookAhead.TokenType != tokenizer.ComplexAssignment) {
+		return nil
 	}
+	token, err := p.tokenizer.ReadRegExp(p.lookAhead)
+	if err != nil {
+		return p.unexpected(err.Error())
+	}
+	p.lookAhead = token
+	return nil
 }
 
 func isLiteral(tokenType string) bool {
 	return tokenType == tokenizer.StringToken ||
+		tokenType == tokenizer.RegExpToken ||
 		tokenType == tokenizer.NumberToken ||
 		tokenType == tokenizer.TrueKeyword ||
 		tokenType == tokenizer.FalseKeyword ||
@@ -532,33 +2196,328 @@ func isLiteral(tokenType string) bool {
 // ParenthesizedExpression
 //	: '(' Expression ')'
 //	;
+//
+// Followed by '=>' the parentheses hold the parameters of an ArrowFunctionExpression
+// instead, which may be empty, end with a comma or end with a rest parameter.
 ///*
 func (p *Parser) ParenthesizedExpression() (*Node, error) {
+	start := p.start()
 	_, err := p.eat(tokenizer.OpenParentheses)
 	if err != nil {
 		return nil, err
 	}
-	expression, expressionErr := p.Expression()
-	if expressionErr != nil {
-		return nil, expressionErr
+
+	expressions := make([]*Node, 0)
+	locations := make([]Location, 0)
+	trailingComma := false
+	rest := false
+	for p.lookAheadType() != tokenizer.CloseParentheses {
+		expressionStart := p.start()
+		if p.lookAheadType() == tokenizer.Spread {
+			param, paramErr := p.RestElement(p.Identifier, tokenizer.CloseParentheses, "Rest parameter must be last formal parameter")
+			if paramErr != nil {
+				return nil, paramErr
+			}
+			expressions = append(expressions, param)
+			rest = true
+			break
+		}
+		expression, expressionErr := p.Expression()
+		if expressionErr != nil {
+			return nil, expressionErr
+		}
+		expressions = append(expressions, expression)
+		locations = append(locations, Location{Start: expressionStart, End: p.end})
+		trailingComma = false
+		if p.lookAheadType() != tokenizer.Comma {
+			break
+		}
+		_, err = p.eat(tokenizer.Comma)
+		if err != nil {
+			return nil, err
+		}
+		trailingComma = true
 	}
+
 	_, err = p.eat(tokenizer.CloseParentheses)
 	if err != nil {
 		return nil, err
 	}
-	return expression, nil
+
+	if p.lookAheadType() == tokenizer.Arrow {
+		for index, expression := range expressions {
+			if expression.NodeType != Identifier && expression.NodeType != RestElement {
+				return nil, &SyntaxError{Message: "invalid arrow function parameter", Loc: locations[index]}
+			}
+		}
+		return p.ArrowFunctionExpression(expressions, start)
+	}
+	if len(expressions) != 1 || trailingComma || rest {
+		// only parameters may be empty, end with a comma or hold a rest parameter
+		_, err = p.eat(tokenizer.Arrow)
+		return nil, err
+	}
+	return expressions[0], nil
+}
+
+// ArrayExpression
+//	: '[' OptElementList ']'
+//
+// ElementList
+//	: OptElement
+//	| ElementList ',' OptElement
+//
+// Element
+//	: AssignmentExpression
+//	| SpreadElement
+//
+// A missing element is a nil hole.
+///*
+func (p *Parser) ArrayExpression() (*Node, error) {
+	start := p.start()
+	elements, err := p.elements(p.element)
+	if err != nil {
+		return nil, err
+	}
+	return p.locate(&Node{NodeType: ArrayExpression, Body: elements}, start), nil
+}
+
+// ObjectExpression
+//	: '{' OptPropertyList '}'
+//
+// PropertyDefinition
+//	: PropertyName ':' AssignmentExpression
+//	| Identifier
+//	| Identifier VariableInitializer
+//	| SpreadElement
+//
+// The initializer of a shorthand property is only valid once the object is
+// reinterpreted as a pattern.
+///*
+func (p *Parser) ObjectExpression() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.OpenCurlyBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make([]*Node, 0)
+	for p.lookAheadType() != tokenizer.CloseCurlyBrace {
+		property, propertyErr := p.PropertyDefinition()
+		if propertyErr != nil {
+			return nil, propertyErr
+		}
+		properties = append(properties, property)
+
+		if p.lookAheadType() != tokenizer.Comma {
+			break
+		}
+		_, err = p.eat(tokenizer.Comma)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.eat(tokenizer.CloseCurlyBrace)
+	if err != nil {
+		return nil, err
+	}
+	return p.locate(&Node{NodeType: ObjectExpression, Body: properties}, start), nil
+}
+
+func (p *Parser) PropertyDefinition() (*Node, error) {
+	if p.lookAheadType() == tokenizer.Spread {
+		return p.SpreadElement()
+	}
+
+	start := p.start()
+	key, err := p.PropertyName()
+	if err != nil {
+		return nil, err
+	}
+
+	if key.NodeType != Identifier || p.lookAheadType() == tokenizer.Colon {
+		_, err = p.eat(tokenizer.Colon)
+		if err != nil {
+			return nil, err
+		}
+		value, valueErr := p.element()
+		if valueErr != nil {
+			return nil, valueErr
+		}
+		return p.property(key, value, false, start), nil
+	}
+
+	if p.lookAheadType() != tokenizer.SimpleAssignment {
+		return p.property(key, key, true, start), nil
+	}
+	init, err := p.VariableInitializer()
+	if err != nil {
+		return nil, err
+	}
+	if p.cover == nil {
+		p.cover = &Location{Start: start, End: p.end}
+	}
+	return p.property(key, p.assignmentPattern(key, init, start), true, start), nil
+}
+
+// element parses an element of an array or object literal, which may become a pattern
+// and so may hold shorthand property initializers.
+func (p *Parser) element() (*Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if p.lookAheadType() == tokenizer.Spread {
+		return p.SpreadElement()
+	}
+	p.allowCover = true
+	return p.AssignmentExpression()
+}
+
+// ArrowFunctionExpression
+//	: ArrowParameters '=>' ArrowFunctionBody
+//
+// ArrowParameters
+//	: Identifier
+//	| '(' OptFormalParameterList ')'
+//
+// ArrowFunctionBody
+//	: BlockStatement
+//	| AssignmentExpression
+///*
+func (p *Parser) ArrowFunctionExpression(params []*Node, start int) (*Node, error) {
+	_, err := p.eat(tokenizer.Arrow)
+	if err != nil {
+		return nil, err
+	}
+
+	var body *Node
+	expression := p.lookAheadType() != tokenizer.OpenCurlyBrace
+	if expression {
+		body, err = p.Expression()
+	} else {
+		body, err = p.functionBody()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p.locate(&Node{
+		NodeType: ArrowFunctionExpression,
+		Body: &FunctionValue{
+			Params:     params,
+			Body:       body,
+			Expression: expression,
+		},
+	}, start), nil
+}
+
+// FunctionExpression
+//	: 'function' OptIdentifier '(' OptFormalParameterList ')' BlockStatement
+///*
+func (p *Parser) FunctionExpression() (*Node, error) {
+	start := p.start()
+	_, err := p.eat(tokenizer.FunctionKeyword)
+	if err != nil {
+		return nil, err
+	}
+
+	var id *Node
+	if p.lookAheadType() == tokenizer.Identifier {
+		id, err = p.Identifier()
+		if err != nil {
+			return nil, err
+		}
+	}
+	params, paramsErr := p.FormalParameters()
+	if paramsErr != nil {
+		return nil, paramsErr
+	}
+	body, bodyErr := p.functionBody()
+	if bodyErr != nil {
+		return nil, bodyErr
+	}
+
+	return p.locate(&Node{
+		NodeType: FunctionExpression,
+		Body: &FunctionValue{
+			Id:     id,
+			Params: params,
+			Body:   body,
+		},
+	}, start), nil
+}
+
+// FormalParameters
+//	: '(' OptFormalParameterList ')'
+//
+// FormalParameterList
+//	: Identifier
+//	| RestElement
+//	| FormalParameterList ',' Identifier
+//	| FormalParameterList ',' RestElement
+///*
+func (p *Parser) FormalParameters() ([]*Node, error) {
+	_, err := p.eat(tokenizer.OpenParentheses)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]*Node, 0)
+	for p.lookAheadType() != tokenizer.CloseParentheses {
+		var param *Node
+		var paramErr error
+		if p.lookAheadType() == tokenizer.Spread {
+			param, paramErr = p.RestElement(p.Identifier, tokenizer.CloseParentheses, "Rest parameter must be last formal parameter")
+		} else {
+			param, paramErr = p.Identifier()
+		}
+		if paramErr != nil {
+			return nil, paramErr
+		}
+		params = append(params, param)
+		if p.lookAheadType() != tokenizer.Comma {
+			break
+		}
+		_, err = p.eat(tokenizer.Comma)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.eat(tokenizer.CloseParentheses)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// functionBody parses the block of a function, it counts as a statement for the
+// nesting depth.
+func (p *Parser) functionBody() (*Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	return p.BlockStatement()
 }
 
 // Literal
 //	: NumericLiteral
 //	| StringLiteral
+//	| RegExpLiteral
 ///*
 func (p *Parser) Literal() (*Node, error) {
-	switch p.lookAhead.TokenType {
+	switch p.lookAheadType() {
 	case tokenizer.NumberToken:
 		return p.NumericLiteral()
 	case tokenizer.StringToken:
 		return p.StringLiteral()
+	case tokenizer.RegExpToken:
+		return p.RegExpLiteral()
 	case tokenizer.TrueKeyword:
 		return p.BooleanLiteral(true)
 	case tokenizer.FalseKeyword:
@@ -566,13 +2525,14 @@ func (p *Parser) Literal() (*Node, error) {
 	case tokenizer.NullKeyword:
 		return p.NullLiteral()
 	}
-	return nil, fmt.Errorf("Unexpected token: %s\n", p.lookAhead.TokenType)
+	return nil, p.unexpected(fmt.Sprintf("Unexpected token: %s\n", p.lookAhead.TokenType))
 }
 
 // NumericLiteral
 //	: NUMBER
 ///*
 func (p *Parser) NumericLiteral() (*Node, error) {
+	start := p.start()
 	token, tokenErr := p.eat(tokenizer.NumberToken)
 	if tokenErr != nil {
 		return nil, tokenErr
@@ -583,19 +2543,58 @@ func (p *Parser) NumericLiteral() (*Node, error) {
 		return nil, errors.New("invalid number token")
 	}
 
-	return &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{Value: num}}, nil
+	return p.locate(&Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{Value: num}}, start), nil
 }
 
 // StringLiteral
 //	: STRING
 ///*
 func (p *Parser) StringLiteral() (*Node, error) {
+	start := p.start()
 	token, tokenErr := p.eat(tokenizer.StringToken)
 	if tokenErr != nil {
 		return nil, tokenErr
 	}
 
-	return &Node{NodeType: StringLiteral, Body: &StringLiteralValue{token.Value[1 : len(token.Value)-1]}}, nil
+	return p.locate(&Node{NodeType: StringLiteral, Body: &StringLiteralValue{token.Value[1 : len(token.Value)-1]}}, start), nil
+}
+
+// RegExpLiteral
+//	: REGEXP
+//
+// The pattern must compile as a Go regular expression.
+///*
+func (p *Parser) RegExpLiteral() (*Node, error) {
+	start := p.start()
+	token, tokenErr := p.eat(tokenizer.RegExpToken)
+	if tokenErr != nil {
+		return nil, tokenErr
+	}
+
+	end := strings.LastIndex(token.Value, "/")
+	value := &RegExpLiteralValue{Pattern: token.Value[1:end], Flags: token.Value[end+1:]}
+	loc := Location{Start: start, End: p.end}
+	for index, flag := range value.Flags {
+		if !strings.ContainsRune("gims", flag) || strings.ContainsRune(value.Flags[:index], flag) {
+			return nil, &SyntaxError{Message: fmt.Sprintf("Invalid regular expression flags: %s", value.Flags), Loc: loc}
+		}
+	}
+	if _, err := value.Compile(); err != nil {
+		message := strings.TrimPrefix(err.Error(), "error parsing regexp: ")
+		return nil, &SyntaxError{Message: fmt.Sprintf("Invalid regular expression: %s: %s", token.Value, message), Loc: loc}
+	}
+
+	return p.locate(&Node{NodeType: RegExpLiteral, Body: value}, start), nil
+}
+
+// Compile compiles the pattern with the i, m and s flags turned into Go flags, g only
+// matters to the functions using the expression.
+func (v *RegExpLiteralValue) Compile() (*regexp.Regexp, error) {
+	flags := strings.ReplaceAll(v.Flags, "g", "")
+	if flags == "" {
+		return regexp.Compile(v.Pattern)
+	}
+	return regexp.Compile("(?" + flags + ")" + v.Pattern)
 }
 
 // BooleanLiteral
@@ -603,24 +2602,26 @@ func (p *Parser) StringLiteral() (*Node, error) {
 //	| 'false'
 ///*
 func (p *Parser) BooleanLiteral(value bool) (*Node, error) {
+	start := p.start()
 	token, tokenErr := p.eat(getBooleanToken(value))
 	if tokenErr != nil {
 		return nil, tokenErr
 	}
 
-	return &Node{NodeType: BooleanLiteral, Body: &StringLiteralValue{token.Value}}, nil
+	return p.locate(&Node{NodeType: BooleanLiteral, Body: &StringLiteralValue{token.Value}}, start), nil
 }
 
 // NullLiteral
 //	: 'null'
 ///*
 func (p *Parser) NullLiteral() (*Node, error) {
+	start := p.start()
 	token, tokenErr := p.eat(tokenizer.NullKeyword)
 	if tokenErr != nil {
 		return nil, tokenErr
 	}
 
-	return &Node{NodeType: NullLiteral, Body: &StringLiteralValue{token.Value}}, nil
+	return p.locate(&Node{NodeType: NullLiteral, Body: &StringLiteralValue{token.Value}}, start), nil
 }
 
 func getBooleanToken(value bool) string {
@@ -630,18 +2631,194 @@ func getBooleanToken(value bool) string {
 	return tokenizer.FalseKeyword
 }
 
+func (p *Parser) lookAheadType() string {
+	if p.lookAhead == nil {
+		return ""
+	}
+	return p.lookAhead.TokenType
+}
+
+func (p *Parser) enter() error {
+	p.depth++
+	if p.maxDepth > 0 && p.depth > p.maxDepth {
+		return ErrMaxDepthExceeded
+	}
+	return nil
+}
+
+func (p *Parser) leave() {
+	p.depth--
+}
+
+// start is the offset the node beginning at the look ahead token starts from.
+func (p *Parser) start() int {
+	if p.lookAhead == nil {
+		return p.end
+	}
+	return p.lookAhead.Start
+}
+
+// locate records the range from start to the end of the last eaten token
+// when locations are enabled.
+func (p *Parser) locate(node *Node, start int) *Node {
+	if p.locations {
+		node.Loc = &Location{Start: start, End: p.end}
+	}
+	return node
+}
+
+// unexpected builds a SyntaxError pointing at the look ahead token,
+// or at the end of the text once it is exhausted.
+func (p *Parser) unexpected(message string) error {
+	if p.lookAhead == nil {
+		return &SyntaxError{Message: message, Loc: Location{Start: len(p.text), End: len(p.text)}}
+	}
+	return &SyntaxError{
+		Message: message,
+		Loc: Location{
+			Start: p.lookAhead.Start,
+			End:   p.lookAhead.Start + len(p.lookAhead.Value),
+		},
+	}
+}
+
+// semicolon eats the ';' ending a statement, with AutoSemicolons it may be left out
+// when the next token is on a new line, is '}' or the input has ended.
+func (p *Parser) semicolon() error {
+	if p.lookAheadType() != tokenizer.SemiColonToken && p.canInsertSemicolon() {
+		return nil
+	}
+	_, err := p.eat(tokenizer.SemiColonToken)
+	return err
+}
+
+func (p *Parser) canInsertSemicolon() bool {
+	if !p.autoSemi {
+		return false
+	}
+	if p.lookAhead == nil {
+		return p.err == nil
+	}
+	return p.lookAheadType() == tokenizer.CloseCurlyBrace || p.newlineBefore()
+}
+
+// newlineBefore reports whether a line break separates the look ahead token from the
+// last eaten token, restricted productions end at such a line break.
+func (p *Parser) newlineBefore() bool {
+	if p.lookAhead == nil {
+		return false
+	}
+	return strings.ContainsAny(p.text[p.end:p.lookAhead.Start], "\n\r")
+}
+
+// eatContextual eats an identifier that acts as a keyword where it appears, like 'from'.
+func (p *Parser) eatContextual(keyword string) (*tokenizer.Token, error) {
+	if p.lookAhead == nil {
+		return p.eat(keyword)
+	}
+	if p.lookAhead.TokenType != tokenizer.Identifier || p.lookAhead.Value != keyword {
+		return nil, p.unexpected(fmt.Sprintf("Unexpected token: %s, expected: %s\n", p.lookAhead.Value, keyword))
+	}
+	return p.eat(tokenizer.Identifier)
+}
+
 func (p *Parser) eat(tokenType string) (*tokenizer.Token, error) {
 	token := p.lookAhead
 	if token == nil {
-		return nil, fmt.Errorf("Unexpected end of input, expected: %s\n", tokenType)
+		if p.err != nil {
+			return nil, p.err
+		}
+		return nil, p.unexpected(fmt.Sprintf("Unexpected end of input, expected: %s\n", tokenType))
 	}
 
 	if token.TokenType != tokenType {
-		return nil, fmt.Errorf("Unexpected token: %s, expected: %s\n", token.Value, tokenType)
+		return nil, p.unexpected(fmt.Sprintf("Unexpected token: %s, expected: %s\n", token.Value, tokenType))
 	}
 
-	nextToken, _ := p.tokenizer.GetNextToken()
-	p.lookAhead = nextToken
+	p.end = token.Start + len(token.Value)
+	p.advance()
 
 	return token, nil
 }
+
+// advance moves the look ahead to the next token, a token the tokenizer cannot read
+// ends the input and is reported by the next attempt to eat.
+func (p *Parser) advance() {
+	nextToken, err := p.tokenizer.GetNextToken()
+	if err != nil && !errors.Is(err, tokenizer.ErrNoTokens) {
+		cursor := p.tokenizer.Cursor()
+		p.err = &SyntaxError{Message: err.Error(), Loc: Location{Start: cursor, End: cursor + 1}}
+	}
+	p.lookAhead = p.collect(nextToken)
+}
+
+// collect moves the comments before the token to the pending comments, the EOF token
+// of a lossless tokenizer only carries trivia and ends the input.
+func (p *Parser) collect(token *tokenizer.Token) *tokenizer.Token {
+	if token == nil {
+		return nil
+	}
+	for _, trivia := range token.Leading {
+		if trivia.TokenType == tokenizer.CommentToken {
+			p.pending = append(p.pending, Comment{
+				Value: trivia.Value,
+				Loc:   Location{Start: trivia.Start, End: trivia.Start + len(trivia.Value)},
+			})
+		}
+	}
+	if token.TokenType == tokenizer.EOFToken {
+		return nil
+	}
+	return token
+}
+
+// takeComments removes and returns every pending comment.
+func (p *Parser) takeComments() []Comment {
+	if len(p.pending) == 0 {
+		return nil
+	}
+	comments := p.pending
+	p.pending = nil
+	return comments
+}
+
+// innerComments removes and returns the pending comments from the offset on, those
+// of a block that no statement in it took.
+func (p *Parser) innerComments(offset int) []Comment {
+	index := len(p.pending)
+	for index > 0 && p.pending[index-1].Loc.Start >= offset {
+		index--
+	}
+	if index == len(p.pending) {
+		return nil
+	}
+	inner := append([]Comment(nil), p.pending[index:]...)
+	p.pending = p.pending[:index]
+	return inner
+}
+
+// attach gives the statement that just ended the leading comments read before it,
+// the pending comments from inside it and those following it on the same line.
+func (p *Parser) attach(node *Node, leading []Comment) *Node {
+	count := 0
+	end := p.end
+	for _, comment := range p.pending {
+		if comment.Loc.Start >= p.end && strings.Contains(p.text[end:comment.Loc.Start], "\n") {
+			break
+		}
+		end = comment.Loc.End
+		count++
+	}
+	trailing := append([]Comment(nil), p.pending[:count]...)
+	p.pending = p.pending[count:]
+
+	if len(leading) == 0 && len(trailing) == 0 {
+		return node
+	}
+	if node.Comments == nil {
+		node.Comments = &Comments{}
+	}
+	node.Comments.Leading = leading
+	node.Comments.Trailing = trailing
+	return node
+}
@@ -1,7 +1,7 @@
 package parser
 
 import (
-	"errors"
+	"strings"
 	"testing"
 
 	"github.com/dlanell/go-rdparser/parser/tokenizer"
@@ -15,12 +15,22 @@ func TestNew(t *testing.T) {
 		assertion.Equal(&Parser{
 			text:      "hello",
 			tokenizer: tokenizer.New(tokenizer.Props{Text: "hello"}),
+			maxDepth:  DefaultMaxDepth,
+		}, parser)
+	})
+	t.Run("given New with MaxDepth, return new Parser", func(t *testing.T) {
+		parser := New(Props{Text: "hello", MaxDepth: 10})
+		assertion.Equal(&Parser{
+			text:      "hello",
+			tokenizer: tokenizer.New(tokenizer.Props{Text: "hello"}),
+			maxDepth:  10,
 		}, parser)
 	})
 }
 
 type test struct {
 	text            string
+	maxDepth        int
 	expectedProgram *Program
 	expectedError   error
 }
@@ -131,6 +141,41 @@ func TestRun(t *testing.T) {
 						}},
 					},
 				},
+				"given number without semicolon": {
+					text:          `123`,
+					expectedError: &SyntaxError{
+						Message: "Unexpected end of input, expected: ;\n",
+						Loc:     Location{Start: 3, End: 3},
+					},
+				},
+				"given unexpected token": {
+					text: `123 456;`,
+					expectedError: &SyntaxError{
+						Message: "Unexpected token: 456, expected: ;\n",
+						Loc:     Location{Start: 4, End: 7},
+					},
+				},
+				"given unreadable character": {
+					text: `123 @;`,
+					expectedError: &SyntaxError{
+						Message: "unexpected token: @",
+						Loc:     Location{Start: 4, End: 5},
+					},
+				},
+				"given unreadable character after last statement": {
+					text: "123;\n@",
+					expectedError: &SyntaxError{
+						Message: "unexpected token: @",
+						Loc:     Location{Start: 5, End: 6},
+					},
+				},
+				"given unterminated block": {
+					text:          `{ 123;`,
+					expectedError: &SyntaxError{
+						Message: "Unexpected end of input, expected: }\n",
+						Loc:     Location{Start: 6, End: 6},
+					},
+				},
 				"given null keyword": {
 					text: `null;`,
 					expectedProgram: &Program{
@@ -514,7 +559,10 @@ func TestRun(t *testing.T) {
 			tests := map[string]test{
 				"given 42 = 42": {
 					text:          `42 = 42;`,
-					expectedError: errors.New("invalid Left-hand side in assignment expression"),
+					expectedError: &SyntaxError{
+						Message: "invalid Left-hand side in assignment expression",
+						Loc:     Location{Start: 0, End: 2},
+					},
 				},
 				"given x = 42": {
 					text: `x = 42;`,
@@ -815,7 +863,7 @@ func TestRun(t *testing.T) {
 						Body: []*Node{
 							{
 								NodeType: VariableStatement,
-								Body: []*Node{
+								Body: &VariableStatementValue{Kind: KindLet, Declarations: []*Node{
 									{
 										NodeType: VariableDeclaration,
 										Body: &VariableDeclarationValue{
@@ -829,7 +877,7 @@ func TestRun(t *testing.T) {
 											},
 										},
 									},
-								},
+								}},
 							},
 						},
 					},
@@ -841,7 +889,7 @@ func TestRun(t *testing.T) {
 						Body: []*Node{
 							{
 								NodeType: VariableStatement,
-								Body: []*Node{
+								Body: &VariableStatementValue{Kind: KindLet, Declarations: []*Node{
 									{
 										NodeType: VariableDeclaration,
 										Body: &VariableDeclarationValue{
@@ -852,7 +900,7 @@ func TestRun(t *testing.T) {
 											Init: nil,
 										},
 									},
-								},
+								}},
 							},
 						},
 					},
@@ -864,7 +912,7 @@ func TestRun(t *testing.T) {
 						Body: []*Node{
 							{
 								NodeType: VariableStatement,
-								Body: []*Node{
+								Body: &VariableStatementValue{Kind: KindLet, Declarations: []*Node{
 									{
 										NodeType: VariableDeclaration,
 										Body: &VariableDeclarationValue{
@@ -885,7 +933,7 @@ func TestRun(t *testing.T) {
 											Init: nil,
 										},
 									},
-								},
+								}},
 							},
 						},
 					},
@@ -897,7 +945,7 @@ func TestRun(t *testing.T) {
 						Body: []*Node{
 							{
 								NodeType: VariableStatement,
-								Body: []*Node{
+								Body: &VariableStatementValue{Kind: KindLet, Declarations: []*Node{
 									{
 										NodeType: VariableDeclaration,
 										Body: &VariableDeclarationValue{
@@ -921,11 +969,64 @@ func TestRun(t *testing.T) {
 											},
 										},
 									},
-								},
+								}},
+							},
+						},
+					},
+				},
+				"given const x = 42": {
+					text: `const x = 42;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: VariableStatement,
+								Body: &VariableStatementValue{Kind: KindConst, Declarations: []*Node{
+									{
+										NodeType: VariableDeclaration,
+										Body: &VariableDeclarationValue{
+											Id: &Node{
+												NodeType: Identifier,
+												Body:     &StringLiteralValue{`x`},
+											},
+											Init: &Node{
+												NodeType: NumericLiteral,
+												Body:     &NumericLiteralValue{42},
+											},
+										},
+									},
+								}},
+							},
+						},
+					},
+				},
+				"given var x": {
+					text: `var x;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: VariableStatement,
+								Body: &VariableStatementValue{Kind: KindVar, Declarations: []*Node{
+									{
+										NodeType: VariableDeclaration,
+										Body: &VariableDeclarationValue{
+											Id: &Node{
+												NodeType: Identifier,
+												Body:     &StringLiteralValue{`x`},
+											},
+											Init: nil,
+										},
+									},
+								}},
 							},
 						},
 					},
 				},
+				"given const without initializer": {
+					text:          `const x = 1, y;`,
+					expectedError: &SyntaxError{Message: "Missing initializer in const declaration", Loc: Location{Start: 13, End: 14}},
+				},
 			}
 
 			for name, tc := range tests {
@@ -1065,6 +1166,287 @@ if (x) {
 				})
 			}
 		})
+		t.Run("SwitchStatement", func(t *testing.T) {
+			tests := map[string]test{
+				"given switch with case and default": {
+					text: `switch (x) { case 1: y; break; default: }`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: SwitchStatement,
+								Body: &SwitchStatementValue{
+									Discriminant: &Node{
+										NodeType: Identifier,
+										Body:     &StringLiteralValue{`x`},
+									},
+									Cases: []*Node{
+										{
+											NodeType: SwitchCase,
+											Body: &SwitchCaseValue{
+												Test: &Node{
+													NodeType: NumericLiteral,
+													Body:     &NumericLiteralValue{1},
+												},
+												Consequent: []*Node{
+													{
+														NodeType: ExpressionStatement,
+														Body: &Node{
+															NodeType: Identifier,
+															Body:     &StringLiteralValue{`y`},
+														},
+													},
+													{
+														NodeType: BreakStatement,
+														Body:     nil,
+													},
+												},
+											},
+										},
+										{
+											NodeType: SwitchCase,
+											Body: &SwitchCaseValue{
+												Test:       nil,
+												Consequent: []*Node{},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given empty switch": {
+					text: `switch (x) {}`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: SwitchStatement,
+								Body: &SwitchStatementValue{
+									Discriminant: &Node{
+										NodeType: Identifier,
+										Body:     &StringLiteralValue{`x`},
+									},
+									Cases: []*Node{},
+								},
+							},
+						},
+					},
+				},
+				"given duplicate default clauses": {
+					text:          `switch (x) { default: x; case 1: default: }`,
+					expectedError: &SyntaxError{Message: "More than one default clause in switch statement", Loc: Location{Start: 33, End: 40}},
+				},
+				"given case without colon": {
+					text:          `switch (x) { case 1 x; }`,
+					expectedError: &SyntaxError{Message: "Unexpected token: x, expected: :\n", Loc: Location{Start: 20, End: 21}},
+				},
+				"given statement outside of clause": {
+					text:          `switch (x) { x; }`,
+					expectedError: &SyntaxError{Message: "Unexpected token: x, expected: }\n", Loc: Location{Start: 13, End: 14}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("TryStatement", func(t *testing.T) {
+			tests := map[string]test{
+				"given try catch finally": {
+					text: `try { throw x; } catch (e) {} finally {}`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: TryStatement,
+								Body: &TryStatementValue{
+									Block: &Node{
+										NodeType: BlockStatement,
+										Body: []*Node{
+											{
+												NodeType: ThrowStatement,
+												Body: &Node{
+													NodeType: Identifier,
+													Body:     &StringLiteralValue{`x`},
+												},
+											},
+										},
+									},
+									Handler: &Node{
+										NodeType: CatchClause,
+										Body: &CatchClauseValue{
+											Param: &Node{
+												NodeType: Identifier,
+												Body:     &StringLiteralValue{`e`},
+											},
+											Body: &Node{
+												NodeType: BlockStatement,
+												Body:     []*Node{},
+											},
+										},
+									},
+									Finalizer: &Node{
+										NodeType: BlockStatement,
+										Body:     []*Node{},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given try catch without parameter": {
+					text: `try {} catch {}`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: TryStatement,
+								Body: &TryStatementValue{
+									Block: &Node{
+										NodeType: BlockStatement,
+										Body:     []*Node{},
+									},
+									Handler: &Node{
+										NodeType: CatchClause,
+										Body: &CatchClauseValue{
+											Param: nil,
+											Body: &Node{
+												NodeType: BlockStatement,
+												Body:     []*Node{},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given try without catch or finally": {
+					text:          `try {} x;`,
+					expectedError: &SyntaxError{Message: "Missing catch or finally after try", Loc: Location{Start: 7, End: 8}},
+				},
+				"given try without block": {
+					text:          `try x; catch {}`,
+					expectedError: &SyntaxError{Message: "Unexpected token: x, expected: {\n", Loc: Location{Start: 4, End: 5}},
+				},
+				"given throw without expression": {
+					text:          `throw;`,
+					expectedError: &SyntaxError{Message: "Unexpected token: ;, expected: IDENTIFIER\n", Loc: Location{Start: 5, End: 6}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("ModuleDeclarations", func(t *testing.T) {
+			tests := map[string]test{
+				"given import with alias": {
+					text: `import { a, b as c } from "./lib";`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ImportDeclaration,
+								Body: &ImportDeclarationValue{
+									Specifiers: []*Node{
+										{
+											NodeType: ImportSpecifier,
+											Body: &ImportSpecifierValue{
+												Imported: &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+												Local:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+											},
+										},
+										{
+											NodeType: ImportSpecifier,
+											Body: &ImportSpecifierValue{
+												Imported: &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+												Local:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+											},
+										},
+									},
+									Source: &Node{NodeType: StringLiteral, Body: &StringLiteralValue{`./lib`}},
+								},
+							},
+						},
+					},
+				},
+				"given import without specifiers": {
+					text: `import {} from "lib";`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ImportDeclaration,
+								Body: &ImportDeclarationValue{
+									Specifiers: []*Node{},
+									Source:     &Node{NodeType: StringLiteral, Body: &StringLiteralValue{`lib`}},
+								},
+							},
+						},
+					},
+				},
+				"given export declaration": {
+					text: `export const x = 1;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExportDeclaration,
+								Body: &Node{
+									NodeType: VariableStatement,
+									Body: &VariableStatementValue{
+										Kind: KindConst,
+										Declarations: []*Node{
+											{
+												NodeType: VariableDeclaration,
+												Body: &VariableDeclarationValue{
+													Id:   &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+													Init: &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{1}},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given import without from": {
+					text:          `import { a } "lib";`,
+					expectedError: &SyntaxError{Message: "Unexpected token: \"lib\", expected: from\n", Loc: Location{Start: 13, End: 18}},
+				},
+				"given export of expression": {
+					text:          `export x;`,
+					expectedError: &SyntaxError{Message: "Unexpected token: x, expected: declaration\n", Loc: Location{Start: 7, End: 8}},
+				},
+				"given import in block": {
+					text:          `{ import { a } from "lib"; }`,
+					expectedError: &SyntaxError{Message: "Unexpected token: import, import declarations may only appear at top level\n", Loc: Location{Start: 2, End: 8}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
 		t.Run("RelationalExpression", func(t *testing.T) {
 			tests := map[string]test{
 				"given valid if else statement with x + 5 > 10 as test": {
@@ -1439,5 +1821,1781 @@ x > 5 || y == 6 && z != 6;
 				})
 			}
 		})
+		t.Run("UnaryAndBitwiseExpressions", func(t *testing.T) {
+			tests := map[string]test{
+				"given x % 2": {
+					text: `x % 2;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "%",
+										Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+										Right:    &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given 2 ** 3 ** 2": {
+					text: `2 ** 3 ** 2;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "**",
+										Left:     &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+										Right: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "**",
+												Left:     &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{3}},
+												Right:    &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given 2 * 3 ** 2": {
+					text: `2 * 3 ** 2;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "*",
+										Left:     &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+										Right: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "**",
+												Left:     &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{3}},
+												Right:    &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given (-2) ** 2": {
+					text: `(-2) ** 2;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "**",
+										Left: &Node{
+											NodeType: UnaryExpression,
+											Body: &UnaryExpressionValue{
+												Operator: "-",
+												Argument: &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+											},
+										},
+										Right: &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given a | b ^ c & d": {
+					text: `a | b ^ c & d;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "|",
+										Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+										Right: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "^",
+												Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+												Right: &Node{
+													NodeType: BinaryExpression,
+													Body: &BinaryExpressionNode{
+														Operator: "&",
+														Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+														Right:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`d`}},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given a & b == c": {
+					text: `a & b == c;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "&",
+										Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+										Right: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "==",
+												Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+												Right:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given 1 << 2 + 3 < x >>> 1": {
+					text: `1 << 2 + 3 < x >>> 1;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "<",
+										Left: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "<<",
+												Left:     &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{1}},
+												Right: &Node{
+													NodeType: BinaryExpression,
+													Body: &BinaryExpressionNode{
+														Operator: "+",
+														Left:     &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+														Right:    &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{3}},
+													},
+												},
+											},
+										},
+										Right: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: ">>>",
+												Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+												Right:    &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{1}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given a === b !== c": {
+					text: `a === b !== c;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "!==",
+										Left: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "===",
+												Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+												Right:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+											},
+										},
+										Right: &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given -~x": {
+					text: `-~x;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: UnaryExpression,
+									Body: &UnaryExpressionValue{
+										Operator: "-",
+										Argument: &Node{
+											NodeType: UnaryExpression,
+											Body: &UnaryExpressionValue{
+												Operator: "~",
+												Argument: &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given x++ + --y": {
+					text: `x++ + --y;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "+",
+										Left: &Node{
+											NodeType: UpdateExpression,
+											Body: &UpdateExpressionValue{
+												Operator: "++",
+												Prefix:   false,
+												Argument: &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+											},
+										},
+										Right: &Node{
+											NodeType: UpdateExpression,
+											Body: &UpdateExpressionValue{
+												Operator: "--",
+												Prefix:   true,
+												Argument: &Node{NodeType: Identifier, Body: &StringLiteralValue{`y`}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given - -x": {
+					text: `- -x;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: UnaryExpression,
+									Body: &UnaryExpressionValue{
+										Operator: "-",
+										Argument: &Node{
+											NodeType: UnaryExpression,
+											Body: &UnaryExpressionValue{
+												Operator: "-",
+												Argument: &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given unary operand of exponent": {
+					text:          `-2 ** 2;`,
+					expectedError: &SyntaxError{Message: "Unary operator used immediately before exponentiation expression. Parenthesis must be used to disambiguate operator precedence", Loc: Location{Start: 3, End: 5}},
+				},
+				"given postfix update of literal": {
+					text:          `1++;`,
+					expectedError: &SyntaxError{Message: "invalid Left-hand side in postfix operation", Loc: Location{Start: 0, End: 1}},
+				},
+				"given prefix update of unary expression": {
+					text:          `++-x;`,
+					expectedError: &SyntaxError{Message: "invalid Left-hand side in prefix operation", Loc: Location{Start: 2, End: 3}},
+				},
+				"given prefix update of expression": {
+					text:          `++(x + 1);`,
+					expectedError: &SyntaxError{Message: "invalid Left-hand side in prefix operation", Loc: Location{Start: 2, End: 9}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("Functions", func(t *testing.T) {
+			tests := map[string]test{
+				"given x => x * 2;": {
+					text: `x => x * 2;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: ArrowFunctionExpression,
+									Body: &FunctionValue{
+										Params: []*Node{
+											{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+										},
+										Body: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "*",
+												Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+												Right:    &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+											},
+										},
+										Expression: true,
+									},
+								},
+							},
+						},
+					},
+				},
+				"given (a, b,) => a + b;": {
+					text: `(a, b,) => a + b;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: ArrowFunctionExpression,
+									Body: &FunctionValue{
+										Params: []*Node{
+											{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+											{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+										},
+										Body: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "+",
+												Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+												Right:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+											},
+										},
+										Expression: true,
+									},
+								},
+							},
+						},
+					},
+				},
+				"given () => { return; };": {
+					text: `() => { return; };`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: ArrowFunctionExpression,
+									Body: &FunctionValue{
+										Params: []*Node{},
+										Body: &Node{
+											NodeType: BlockStatement,
+											Body: []*Node{
+												{NodeType: ReturnStatement, Body: nil},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given function (x) { return x; };": {
+					text: `function (x) { return x; };`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: FunctionExpression,
+									Body: &FunctionValue{
+										Params: []*Node{
+											{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+										},
+										Body: &Node{
+											NodeType: BlockStatement,
+											Body: []*Node{
+												{
+													NodeType: ReturnStatement,
+													Body:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given function fact(n) {};": {
+					text: `function fact(n) {};`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: FunctionExpression,
+									Body: &FunctionValue{
+										Id: &Node{NodeType: Identifier, Body: &StringLiteralValue{`fact`}},
+										Params: []*Node{
+											{NodeType: Identifier, Body: &StringLiteralValue{`n`}},
+										},
+										Body: &Node{
+											NodeType: BlockStatement,
+											Body:     []*Node{},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given filter(items, x => x.active);": {
+					text: `filter(items, x => x.active);`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: CallExpression,
+									Body: &CallExpressionValue{
+										Callee: &Node{NodeType: Identifier, Body: &StringLiteralValue{`filter`}},
+										Arguments: []*Node{
+											{NodeType: Identifier, Body: &StringLiteralValue{`items`}},
+											{
+												NodeType: ArrowFunctionExpression,
+												Body: &FunctionValue{
+													Params: []*Node{
+														{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+													},
+													Body: &Node{
+														NodeType: MemberExpression,
+														Body: &MemberExpressionValue{
+															Object:   &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+															Property: &Node{NodeType: Identifier, Body: &StringLiteralValue{`active`}},
+														},
+													},
+													Expression: true,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given a.b(1)(2);": {
+					text: `a.b(1)(2);`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: CallExpression,
+									Body: &CallExpressionValue{
+										Callee: &Node{
+											NodeType: CallExpression,
+											Body: &CallExpressionValue{
+												Callee: &Node{
+													NodeType: MemberExpression,
+													Body: &MemberExpressionValue{
+														Object:   &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+														Property: &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+													},
+												},
+												Arguments: []*Node{
+													{NodeType: NumericLiteral, Body: &NumericLiteralValue{1}},
+												},
+											},
+										},
+										Arguments: []*Node{
+											{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given (x => x)(1);": {
+					text: `(x => x)(1);`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: CallExpression,
+									Body: &CallExpressionValue{
+										Callee: &Node{
+											NodeType: ArrowFunctionExpression,
+											Body: &FunctionValue{
+												Params: []*Node{
+													{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+												},
+												Body:       &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+												Expression: true,
+											},
+										},
+										Arguments: []*Node{
+											{NodeType: NumericLiteral, Body: &NumericLiteralValue{1}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given expression as arrow parameter": {
+					text:          `(a + 1) => a;`,
+					expectedError: &SyntaxError{Message: "invalid arrow function parameter", Loc: Location{Start: 1, End: 6}},
+				},
+				"given parenthesized list without arrow": {
+					text:          `(a, b);`,
+					expectedError: &SyntaxError{Message: "Unexpected token: ;, expected: =>\n", Loc: Location{Start: 6, End: 7}},
+				},
+				"given empty parentheses without arrow": {
+					text:          `();`,
+					expectedError: &SyntaxError{Message: "Unexpected token: ;, expected: =>\n", Loc: Location{Start: 2, End: 3}},
+				},
+				"given unterminated arguments": {
+					text:          `f(a;`,
+					expectedError: &SyntaxError{Message: "Unexpected token: ;, expected: )\n", Loc: Location{Start: 3, End: 4}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("Destructuring", func(t *testing.T) {
+			tests := map[string]test{
+				"given let { a, b: c = 1 } = o;": {
+					text: `let { a, b: c = 1 } = o;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: VariableStatement,
+								Body: &VariableStatementValue{
+									Kind: KindLet,
+									Declarations: []*Node{
+										{
+											NodeType: VariableDeclaration,
+											Body: &VariableDeclarationValue{
+												Id: &Node{
+													NodeType: ObjectPattern,
+													Body: []*Node{
+														{
+															NodeType: Property,
+															Body: &PropertyValue{
+																Key:       &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+																Value:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+																Shorthand: true,
+															},
+														},
+														{
+															NodeType: Property,
+															Body: &PropertyValue{
+																Key: &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+																Value: &Node{
+																	NodeType: AssignmentPattern,
+																	Body: &AssignmentPatternValue{
+																		Left:  &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+																		Right: &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{1}},
+																	},
+																},
+															},
+														},
+													},
+												},
+												Init: &Node{NodeType: Identifier, Body: &StringLiteralValue{`o`}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given let [x, , y] = arr;": {
+					text: `let [x, , y] = arr;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: VariableStatement,
+								Body: &VariableStatementValue{
+									Kind: KindLet,
+									Declarations: []*Node{
+										{
+											NodeType: VariableDeclaration,
+											Body: &VariableDeclarationValue{
+												Id: &Node{
+													NodeType: ArrayPattern,
+													Body: []*Node{
+														{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+														nil,
+														{NodeType: Identifier, Body: &StringLiteralValue{`y`}},
+													},
+												},
+												Init: &Node{NodeType: Identifier, Body: &StringLiteralValue{`arr`}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given [a, b] = [b, a[0]];": {
+					text: `[a, b] = [b, a[0]];`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: AssignmentExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "=",
+										Left: &Node{
+											NodeType: ArrayPattern,
+											Body: []*Node{
+												{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+												{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+											},
+										},
+										Right: &Node{
+											NodeType: ArrayExpression,
+											Body: []*Node{
+												{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+												{
+													NodeType: MemberExpression,
+													Body: &MemberExpressionValue{
+														Object:   &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+														Property: &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{0}},
+														Computed: true,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given ({ a = 1, b: [c] } = o);": {
+					text: `({ a = 1, b: [c] } = o);`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: AssignmentExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "=",
+										Left: &Node{
+											NodeType: ObjectPattern,
+											Body: []*Node{
+												{
+													NodeType: Property,
+													Body: &PropertyValue{
+														Key: &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+														Value: &Node{
+															NodeType: AssignmentPattern,
+															Body: &AssignmentPatternValue{
+																Left:  &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+																Right: &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{1}},
+															},
+														},
+														Shorthand: true,
+													},
+												},
+												{
+													NodeType: Property,
+													Body: &PropertyValue{
+														Key: &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+														Value: &Node{
+															NodeType: ArrayPattern,
+															Body: []*Node{
+																{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+															},
+														},
+													},
+												},
+											},
+										},
+										Right: &Node{NodeType: Identifier, Body: &StringLiteralValue{`o`}},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given shorthand initializer outside of pattern": {
+					text:          `({ a = 1 });`,
+					expectedError: &SyntaxError{Message: "Invalid shorthand property initializer", Loc: Location{Start: 3, End: 8}},
+				},
+				"given pattern without initializer": {
+					text:          `let { a };`,
+					expectedError: &SyntaxError{Message: "Missing initializer in destructuring declaration", Loc: Location{Start: 4, End: 9}},
+				},
+				"given expression in assignment pattern": {
+					text:          `[a + 1] = x;`,
+					expectedError: &SyntaxError{Message: "invalid Left-hand side in assignment expression", Loc: Location{Start: 0, End: 7}},
+				},
+				"given compound assignment to pattern": {
+					text:          `[a] += x;`,
+					expectedError: &SyntaxError{Message: "invalid Left-hand side in assignment expression", Loc: Location{Start: 0, End: 3}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("SpreadAndRest", func(t *testing.T) {
+			tests := map[string]test{
+				"given f(...a, b);": {
+					text: `f(...a, b);`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: CallExpression,
+									Body: &CallExpressionValue{
+										Callee: &Node{NodeType: Identifier, Body: &StringLiteralValue{`f`}},
+										Arguments: []*Node{
+											{NodeType: SpreadElement, Body: &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}}},
+											{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given ({ ...a, b: [...c] });": {
+					text: `({ ...a, b: [...c] });`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: ObjectExpression,
+									Body: []*Node{
+										{NodeType: SpreadElement, Body: &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}}},
+										{
+											NodeType: Property,
+											Body: &PropertyValue{
+												Key: &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+												Value: &Node{
+													NodeType: ArrayExpression,
+													Body: []*Node{
+														{NodeType: SpreadElement, Body: &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}}},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given let [x, ...{ length }] = a;": {
+					text: `let [x, ...{ length }] = a;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: VariableStatement,
+								Body: &VariableStatementValue{
+									Kind: KindLet,
+									Declarations: []*Node{
+										{
+											NodeType: VariableDeclaration,
+											Body: &VariableDeclarationValue{
+												Id: &Node{
+													NodeType: ArrayPattern,
+													Body: []*Node{
+														{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+														{
+															NodeType: RestElement,
+															Body: &Node{
+																NodeType: ObjectPattern,
+																Body: []*Node{
+																	{
+																		NodeType: Property,
+																		Body: &PropertyValue{
+																			Key:       &Node{NodeType: Identifier, Body: &StringLiteralValue{`length`}},
+																			Value:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`length`}},
+																			Shorthand: true,
+																		},
+																	},
+																},
+															},
+														},
+													},
+												},
+												Init: &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given ({ a, ...rest } = o);": {
+					text: `({ a, ...rest } = o);`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: AssignmentExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "=",
+										Left: &Node{
+											NodeType: ObjectPattern,
+											Body: []*Node{
+												{
+													NodeType: Property,
+													Body: &PropertyValue{
+														Key:       &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+														Value:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+														Shorthand: true,
+													},
+												},
+												{NodeType: RestElement, Body: &Node{NodeType: Identifier, Body: &StringLiteralValue{`rest`}}},
+											},
+										},
+										Right: &Node{NodeType: Identifier, Body: &StringLiteralValue{`o`}},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given (a, ...b) => b;": {
+					text: `(a, ...b) => b;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: ArrowFunctionExpression,
+									Body: &FunctionValue{
+										Params: []*Node{
+											{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+											{NodeType: RestElement, Body: &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}}},
+										},
+										Body:       &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+										Expression: true,
+									},
+								},
+							},
+						},
+					},
+				},
+				"given rest parameter before other parameter": {
+					text:          `(function (...a, b) {});`,
+					expectedError: &SyntaxError{Message: "Rest parameter must be last formal parameter", Loc: Location{Start: 11, End: 15}},
+				},
+				"given rest parameter without arrow": {
+					text:          `(...a);`,
+					expectedError: &SyntaxError{Message: "Unexpected token: ;, expected: =>\n", Loc: Location{Start: 6, End: 7}},
+				},
+				"given rest element before other element": {
+					text:          `let [...a, b] = c;`,
+					expectedError: &SyntaxError{Message: "Rest element must be last element", Loc: Location{Start: 5, End: 9}},
+				},
+				"given spread before other element in assignment pattern": {
+					text:          `[...a, b] = c;`,
+					expectedError: &SyntaxError{Message: "Rest element must be last element", Loc: Location{Start: 0, End: 9}},
+				},
+				"given spread of expression in object pattern": {
+					text:          `({ ...a.b } = c);`,
+					expectedError: &SyntaxError{Message: "invalid Left-hand side in assignment expression", Loc: Location{Start: 1, End: 11}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("OptionalChainingAndNullishCoalescing", func(t *testing.T) {
+			tests := map[string]test{
+				"given a?.b.c;": {
+					text: `a?.b.c;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: ChainExpression,
+									Body: &Node{
+										NodeType: MemberExpression,
+										Body: &MemberExpressionValue{
+											Object: &Node{
+												NodeType: MemberExpression,
+												Body: &MemberExpressionValue{
+													Object:   &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+													Property: &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+													Optional: true,
+												},
+											},
+											Property: &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given f?.(x)?.[k];": {
+					text: `f?.(x)?.[k];`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: ChainExpression,
+									Body: &Node{
+										NodeType: MemberExpression,
+										Body: &MemberExpressionValue{
+											Object: &Node{
+												NodeType: CallExpression,
+												Body: &CallExpressionValue{
+													Callee: &Node{NodeType: Identifier, Body: &StringLiteralValue{`f`}},
+													Arguments: []*Node{
+														{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+													},
+													Optional: true,
+												},
+											},
+											Property: &Node{NodeType: Identifier, Body: &StringLiteralValue{`k`}},
+											Computed: true,
+											Optional: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given (a?.b).c;": {
+					text: `(a?.b).c;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: MemberExpression,
+									Body: &MemberExpressionValue{
+										Object: &Node{
+											NodeType: ChainExpression,
+											Body: &Node{
+												NodeType: MemberExpression,
+												Body: &MemberExpressionValue{
+													Object:   &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+													Property: &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+													Optional: true,
+												},
+											},
+										},
+										Property: &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given a ?? b ?? c;": {
+					text: `a ?? b ?? c;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "??",
+										Left: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "??",
+												Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+												Right:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+											},
+										},
+										Right: &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given x ??= (a || b) ?? c | d;": {
+					text: `x ??= (a || b) ?? c | d;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: AssignmentExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "??=",
+										Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+										Right: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "??",
+												Left: &Node{
+													NodeType: BinaryExpression,
+													Body: &BinaryExpressionNode{
+														Operator: "||",
+														Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+														Right:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+													},
+												},
+												Right: &Node{
+													NodeType: BinaryExpression,
+													Body: &BinaryExpressionNode{
+														Operator: "|",
+														Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+														Right:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`d`}},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given ?? followed by ||": {
+					text:          `a ?? b || c;`,
+					expectedError: &SyntaxError{Message: "Cannot mix ?? with && or || without parentheses", Loc: Location{Start: 7, End: 9}},
+				},
+				"given && followed by ??": {
+					text:          `a && b ?? c;`,
+					expectedError: &SyntaxError{Message: "Cannot mix ?? with && or || without parentheses", Loc: Location{Start: 7, End: 9}},
+				},
+				"given assignment to optional member": {
+					text:          `a?.b = 1;`,
+					expectedError: &SyntaxError{Message: "invalid Left-hand side in assignment expression", Loc: Location{Start: 0, End: 4}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("RegExpLiteral", func(t *testing.T) {
+			tests := map[string]test{
+				"given /^[a-z]+$/i;": {
+					text: `/^[a-z]+$/i;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body:     &Node{NodeType: RegExpLiteral, Body: &RegExpLiteralValue{Pattern: `^[a-z]+$`, Flags: `i`}},
+							},
+						},
+					},
+				},
+				"given regular expression starting with =": {
+					text: `x = /=+/;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: AssignmentExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "=",
+										Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+										Right:    &Node{NodeType: RegExpLiteral, Body: &RegExpLiteralValue{Pattern: `=+`}},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given regular expression after block": {
+					text: `{} /a/g;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{NodeType: BlockStatement, Body: []*Node{}},
+							{
+								NodeType: ExpressionStatement,
+								Body:     &Node{NodeType: RegExpLiteral, Body: &RegExpLiteralValue{Pattern: `a`, Flags: `g`}},
+							},
+						},
+					},
+				},
+				"given division after identifier": {
+					text: `a / b / c;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: BinaryExpression,
+									Body: &BinaryExpressionNode{
+										Operator: "/",
+										Left: &Node{
+											NodeType: BinaryExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "/",
+												Left:     &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+												Right:    &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+											},
+										},
+										Right: &Node{NodeType: Identifier, Body: &StringLiteralValue{`c`}},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given invalid flags": {
+					text:          `/a/gg;`,
+					expectedError: &SyntaxError{Message: "Invalid regular expression flags: gg", Loc: Location{Start: 0, End: 5}},
+				},
+				"given invalid pattern": {
+					text:          `/a(/;`,
+					expectedError: &SyntaxError{Message: "Invalid regular expression: /a(/: missing closing ): `a(`", Loc: Location{Start: 0, End: 4}},
+				},
+				"given unterminated regular expression": {
+					text:          `x = /abc;`,
+					expectedError: &SyntaxError{Message: "unterminated regular expression literal", Loc: Location{Start: 4, End: 5}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("LabeledStatement", func(t *testing.T) {
+			tests := map[string]test{
+				"given outer: { break outer; }": {
+					text: `outer: { break outer; }`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: LabeledStatement,
+								Body: &LabeledStatementValue{
+									Label: &Node{NodeType: Identifier, Body: &StringLiteralValue{`outer`}},
+									Body: &Node{
+										NodeType: BlockStatement,
+										Body: []*Node{
+											{NodeType: BreakStatement, Body: &Node{NodeType: Identifier, Body: &StringLiteralValue{`outer`}}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given a: b: break;": {
+					text: `a: b: break;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: LabeledStatement,
+								Body: &LabeledStatementValue{
+									Label: &Node{NodeType: Identifier, Body: &StringLiteralValue{`a`}},
+									Body: &Node{
+										NodeType: LabeledStatement,
+										Body: &LabeledStatementValue{
+											Label: &Node{NodeType: Identifier, Body: &StringLiteralValue{`b`}},
+											Body:  &Node{NodeType: BreakStatement, Body: nil},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given parenthesized label": {
+					text:          `(a): b;`,
+					expectedError: &SyntaxError{Message: "Unexpected token: :, expected: ;\n", Loc: Location{Start: 3, End: 4}},
+				},
+				"given label that is not an identifier": {
+					text:          `a.b: c;`,
+					expectedError: &SyntaxError{Message: "Unexpected token: :, expected: ;\n", Loc: Location{Start: 3, End: 4}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("TypeAnnotation", func(t *testing.T) {
+			tests := map[string]test{
+				"given let x: number = 1;": {
+					text: `let x: number = 1;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: VariableStatement,
+								Body: &VariableStatementValue{
+									Kind: KindLet,
+									Declarations: []*Node{
+										{
+											NodeType: VariableDeclaration,
+											Body: &VariableDeclarationValue{
+												Id:   &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+												Type: &Node{NodeType: TypeAnnotation, Body: &TypeAnnotationValue{Types: []string{"number"}}},
+												Init: &Node{NodeType: NumericLiteral, Body: &NumericLiteralValue{1}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given union with null and no initializer": {
+					text: `let x: string | null;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: VariableStatement,
+								Body: &VariableStatementValue{
+									Kind: KindLet,
+									Declarations: []*Node{
+										{
+											NodeType: VariableDeclaration,
+											Body: &VariableDeclarationValue{
+												Id:   &Node{NodeType: Identifier, Body: &StringLiteralValue{`x`}},
+												Type: &Node{NodeType: TypeAnnotation, Body: &TypeAnnotationValue{Types: []string{"string", "null"}}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given missing type name": {
+					text:          `let x: = 1;`,
+					expectedError: &SyntaxError{Message: "Unexpected token: =, expected: IDENTIFIER\n", Loc: Location{Start: 7, End: 8}},
+				},
+				"given trailing union operator": {
+					text:          `const x: number | = 1;`,
+					expectedError: &SyntaxError{Message: "Unexpected token: =, expected: IDENTIFIER\n", Loc: Location{Start: 18, End: 19}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("ConditionalExpression", func(t *testing.T) {
+			tests := map[string]test{
+				"given x ? 1 : 2;": {
+					text: `x ? 1 : 2;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: ConditionalExpression,
+									Body: &ConditionalExpressionValue{
+										Test: &Node{
+											NodeType: Identifier,
+											Body:     &StringLiteralValue{`x`},
+										},
+										Consequent: &Node{
+											NodeType: NumericLiteral,
+											Body:     &NumericLiteralValue{1},
+										},
+										Alternate: &Node{
+											NodeType: NumericLiteral,
+											Body:     &NumericLiteralValue{2},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given a ? b : c ? d : e;": {
+					text: `a ? b : c ? d : e;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: ConditionalExpression,
+									Body: &ConditionalExpressionValue{
+										Test: &Node{
+											NodeType: Identifier,
+											Body:     &StringLiteralValue{`a`},
+										},
+										Consequent: &Node{
+											NodeType: Identifier,
+											Body:     &StringLiteralValue{`b`},
+										},
+										Alternate: &Node{
+											NodeType: ConditionalExpression,
+											Body: &ConditionalExpressionValue{
+												Test: &Node{
+													NodeType: Identifier,
+													Body:     &StringLiteralValue{`c`},
+												},
+												Consequent: &Node{
+													NodeType: Identifier,
+													Body:     &StringLiteralValue{`d`},
+												},
+												Alternate: &Node{
+													NodeType: Identifier,
+													Body:     &StringLiteralValue{`e`},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given x = a && b ? 1 : 2;": {
+					text: `x = a && b ? 1 : 2;`,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{
+							{
+								NodeType: ExpressionStatement,
+								Body: &Node{
+									NodeType: AssignmentExpression,
+									Body: &BinaryExpressionNode{
+										Operator: `=`,
+										Left: &Node{
+											NodeType: Identifier,
+											Body:     &StringLiteralValue{`x`},
+										},
+										Right: &Node{
+											NodeType: ConditionalExpression,
+											Body: &ConditionalExpressionValue{
+												Test: &Node{
+													NodeType: BinaryExpression,
+													Body: &BinaryExpressionNode{
+														Operator: `&&`,
+														Left: &Node{
+															NodeType: Identifier,
+															Body:     &StringLiteralValue{`a`},
+														},
+														Right: &Node{
+															NodeType: Identifier,
+															Body:     &StringLiteralValue{`b`},
+														},
+													},
+												},
+												Consequent: &Node{
+													NodeType: NumericLiteral,
+													Body:     &NumericLiteralValue{1},
+												},
+												Alternate: &Node{
+													NodeType: NumericLiteral,
+													Body:     &NumericLiteralValue{2},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given missing alternate": {
+					text:          `x ? 1;`,
+					expectedError: &SyntaxError{Message: "Unexpected token: ;, expected: :\n", Loc: Location{Start: 5, End: 6}},
+				},
+				"given assignment to conditional expression": {
+					text:          `(a ? b : c) = 1;`,
+					expectedError: &SyntaxError{Message: "invalid Left-hand side in assignment expression", Loc: Location{Start: 0, End: 11}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("MaxDepth", func(t *testing.T) {
+			tests := map[string]test{
+				"given nesting within max depth": {
+					text:     `((1));`,
+					maxDepth: 4,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: []*Node{{
+							NodeType: ExpressionStatement,
+							Body: &Node{
+								NodeType: NumericLiteral,
+								Body:     &NumericLiteralValue{1},
+							},
+						}},
+					},
+				},
+				"given nested parentheses beyond max depth": {
+					text:          `(((1)));`,
+					maxDepth:      4,
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given nested blocks beyond max depth": {
+					text:          `{{{{}}}}`,
+					maxDepth:      3,
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given nested if statements beyond max depth": {
+					text:          `if (x) if (x) if (x) x;`,
+					maxDepth:      4,
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given chained assignments beyond max depth": {
+					text:          `a = b = c = 1;`,
+					maxDepth:      3,
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given nested rest elements beyond max depth": {
+					text:          `let [...[...[...a]]] = b;`,
+					maxDepth:      3,
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given nested rest elements beyond default max depth": {
+					text:          "let " + strings.Repeat("[...", 1000) + "a" + strings.Repeat("]", 1000) + " = b;",
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given prefix update beyond max depth": {
+					text:          `++x;`,
+					maxDepth:      2,
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given repeated prefix updates": {
+					text:          strings.Repeat("++", 1000) + "x;",
+					expectedError: &SyntaxError{Message: "invalid Left-hand side in prefix operation", Loc: Location{Start: 2, End: 4}},
+				},
+				"given nesting beyond default max depth": {
+					text:          strings.Repeat("(", 100000),
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given nesting with max depth disabled": {
+					text:     strings.Repeat("{", 300) + strings.Repeat("}", 300),
+					maxDepth: -1,
+					expectedProgram: func() *Program {
+						block := &Node{NodeType: BlockStatement, Body: []*Node{}}
+						for index := 1; index < 300; index++ {
+							block = &Node{NodeType: BlockStatement, Body: []*Node{block}}
+						}
+						return &Program{NodeType: ProgramEnum, Body: []*Node{block}}
+					}(),
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := New(Props{Text: tc.text, MaxDepth: tc.maxDepth})
+					node, err := parser.Run()
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
+		t.Run("AutoSemicolons", func(t *testing.T) {
+			tests := map[string]struct {
+				text          string
+				equivalent    string
+				expectedError error
+			}{
+				"given statements on separate lines": {
+					text:       "let x = 1\nx = 2\nx",
+					equivalent: "let x = 1; x = 2; x;",
+				},
+				"given declarations without initializers on separate lines": {
+					text:       "let x, y\nlet z\n",
+					equivalent: "let x, y; let z;",
+				},
+				"given statement before closing brace": {
+					text:       "if (x) { x = 1 } else { x }",
+					equivalent: "if (x) { x = 1; } else { x; }",
+				},
+				"given expression continued on next line": {
+					text:       "let x = 1\n+ 2\nx\n= 3",
+					equivalent: "let x = 1 + 2; x = 3;",
+				},
+				"given line break inside comment": {
+					text:       "x /*\n*/ y",
+					equivalent: "x; y;",
+				},
+				"given explicit semicolons": {
+					text:       "let x = 1; x;",
+					equivalent: "let x = 1; x;",
+				},
+				"given throw with expression on the next line": {
+					text:          "throw\nx",
+					expectedError: &SyntaxError{Message: "Illegal newline after throw", Loc: Location{Start: 6, End: 7}},
+				},
+				"given update operator on the next line": {
+					text:       "x\n++y",
+					equivalent: "x; ++y;",
+				},
+				"given return followed by line break": {
+					text:       "() => { return\nx }",
+					equivalent: "() => { return; x; };",
+				},
+				"given break followed by line break": {
+					text:       "a: { break\na }",
+					equivalent: "a: { break; a; }",
+				},
+				"given throw without semicolon": {
+					text:       "throw x\ny",
+					equivalent: "throw x; y;",
+				},
+				"given statements on the same line": {
+					text:          "let x = 1 x = 2",
+					expectedError: &SyntaxError{Message: "Unexpected token: x, expected: ;\n", Loc: Location{Start: 10, End: 11}},
+				},
+				"given invalid character after statement": {
+					text:          "x @",
+					expectedError: &SyntaxError{Message: "unexpected token: @", Loc: Location{Start: 2, End: 3}},
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					node, err := New(Props{Text: tc.text, AutoSemicolons: true}).Run()
+					assert.Equal(t, tc.expectedError, err)
+					if tc.expectedError != nil {
+						return
+					}
+					expected, err := New(Props{Text: tc.equivalent}).Run()
+					assert.NoError(t, err)
+					assert.Equal(t, expected, node)
+				})
+			}
+			t.Run("given no AutoSemicolons, require semicolons", func(t *testing.T) {
+				_, err := New(Props{Text: "let x = 1\nx"}).Run()
+				assert.Equal(t, &SyntaxError{Message: "Unexpected token: x, expected: ;\n", Loc: Location{Start: 10, End: 11}}, err)
+			})
+		})
+		t.Run("Locations", func(t *testing.T) {
+			t.Run("given Locations, record the range of every node", func(t *testing.T) {
+				parser := New(Props{Text: "let x = 1;\nif (x) { x += 2; }", Locations: true})
+				node, err := parser.Run()
+				assert.NoError(t, err)
+				assert.Equal(t, &Program{
+					NodeType: ProgramEnum,
+					Body: []*Node{
+						{
+							NodeType: VariableStatement,
+							Body: &VariableStatementValue{Kind: KindLet, Declarations: []*Node{{
+								NodeType: VariableDeclaration,
+								Body: &VariableDeclarationValue{
+									Id: &Node{
+										NodeType: Identifier,
+										Body:     &StringLiteralValue{"x"},
+										Loc:      &Location{Start: 4, End: 5},
+									},
+									Init: &Node{
+										NodeType: NumericLiteral,
+										Body:     &NumericLiteralValue{1},
+										Loc:      &Location{Start: 8, End: 9},
+									},
+								},
+								Loc: &Location{Start: 4, End: 9},
+							}}},
+							Loc: &Location{Start: 0, End: 10},
+						},
+						{
+							NodeType: IfStatement,
+							Body: &IfStatementValue{
+								Test: &Node{
+									NodeType: Identifier,
+									Body:     &StringLiteralValue{"x"},
+									Loc:      &Location{Start: 15, End: 16},
+								},
+								Consequent: &Node{
+									NodeType: BlockStatement,
+									Body: []*Node{{
+										NodeType: ExpressionStatement,
+										Body: &Node{
+											NodeType: AssignmentExpression,
+											Body: &BinaryExpressionNode{
+												Operator: "+=",
+												Left: &Node{
+													NodeType: Identifier,
+													Body:     &StringLiteralValue{"x"},
+													Loc:      &Location{Start: 20, End: 21},
+												},
+												Right: &Node{
+													NodeType: NumericLiteral,
+													Body:     &NumericLiteralValue{2},
+													Loc:      &Location{Start: 25, End: 26},
+												},
+											},
+											Loc: &Location{Start: 20, End: 26},
+										},
+										Loc: &Location{Start: 20, End: 27},
+									}},
+									Loc: &Location{Start: 18, End: 29},
+								},
+							},
+							Loc: &Location{Start: 11, End: 29},
+						},
+					},
+				}, node)
+			})
+		})
+		t.Run("Comments", func(t *testing.T) {
+			text := "/** The answer. */\nlet x = 42; // inline\n{\n  // first\n  x = /* inside */ 1;\n  // dangling\n}\n// tail\n"
+			t.Run("given Comments, attach comments to the statements around them", func(t *testing.T) {
+				program, err := New(Props{Text: text, Comments: true}).Run()
+				assert.NoError(t, err)
+				assert.Equal(t, &Comments{
+					Leading:  []Comment{{Value: "/** The answer. */", Loc: Location{Start: 0, End: 18}}},
+					Trailing: []Comment{{Value: "// inline", Loc: Location{Start: 31, End: 40}}},
+				}, program.Body[0].Comments)
+				block := program.Body[1]
+				assert.Equal(t, &Comments{
+					Inner: []Comment{{Value: "// dangling", Loc: Location{Start: 78, End: 89}}},
+				}, block.Comments)
+				assert.Equal(t, &Comments{
+					Leading:  []Comment{{Value: "// first", Loc: Location{Start: 45, End: 53}}},
+					Trailing: []Comment{{Value: "/* inside */", Loc: Location{Start: 60, End: 72}}},
+				}, block.Body.([]*Node)[0].Comments)
+				assert.Equal(t, []Comment{{Value: "// tail", Loc: Location{Start: 92, End: 99}}}, program.Comments)
+			})
+			t.Run("given Comments and empty block, keep comments inside block", func(t *testing.T) {
+				program, err := New(Props{Text: "/* before */ { /* inside */ } // after", Comments: true}).Run()
+				assert.NoError(t, err)
+				assert.Equal(t, &Comments{
+					Leading:  []Comment{{Value: "/* before */", Loc: Location{Start: 0, End: 12}}},
+					Trailing: []Comment{{Value: "// after", Loc: Location{Start: 30, End: 38}}},
+					Inner:    []Comment{{Value: "/* inside */", Loc: Location{Start: 15, End: 27}}},
+				}, program.Body[0].Comments)
+			})
+			t.Run("given no Comments, skip comments", func(t *testing.T) {
+				program, err := New(Props{Text: text}).Run()
+				assert.NoError(t, err)
+				assert.Nil(t, program.Body[0].Comments)
+				assert.Nil(t, program.Comments)
+			})
+			t.Run("given Comments and only comments, return error", func(t *testing.T) {
+				_, err := New(Props{Text: "// nothing\n", Comments: true}).Run()
+				assert.Equal(t, tokenizer.ErrNoTokens, err)
+			})
+			t.Run("given Comments and syntax error, return error", func(t *testing.T) {
+				_, err := New(Props{Text: "// one\nx +;", Comments: true}).Run()
+				assert.Equal(t, &SyntaxError{Message: "Unexpected token: ;, expected: IDENTIFIER\n", Loc: Location{Start: 10, End: 11}}, err)
+			})
+		})
 	})
 }
@@ -0,0 +1,703 @@
+package printer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/parser"
+)
+
+type Printer struct {
+	indent  string
+	builder *strings.Builder
+	level   int
+}
+
+type Props struct {
+	Indent string
+}
+
+const DefaultIndent = "  "
+
+// precedence mirrors the order in which the parser descends through binary expressions,
+// a higher value binds tighter.
+var precedence = map[string]int{
+	"&&":  3,
+	"AND": 3,
+	"||":  4,
+	"OR":  4,
+	"??":  3,
+	"|":   5,
+	"^":   6,
+	"&":   7,
+	"==":  8,
+	"!=":  8,
+	"===": 8,
+	"!==": 8,
+	">":   9,
+	">=":  9,
+	"<":   9,
+	"<=":  9,
+	"<<":  10,
+	">>":  10,
+	">>>": 10,
+	"+":   11,
+	"-":   11,
+	"*":   12,
+	"/":   12,
+	"%":   12,
+	"**":  13,
+}
+
+const (
+	assignmentPrecedence  int = 1
+	conditionalPrecedence     = 2
+	exponentPrecedence        = 13
+	unaryPrecedence           = 14
+	updatePrecedence          = 15
+	callPrecedence            = 16
+	primaryPrecedence         = 17
+)
+
+func New(props Props) *Printer {
+	indent := props.Indent
+	if indent == "" {
+		indent = DefaultIndent
+	}
+	return &Printer{
+		indent: indent,
+	}
+}
+
+// Run renders the program back to source, one statement per line.
+func (p *Printer) Run(program *parser.Program) (string, error) {
+	p.builder = &strings.Builder{}
+	p.level = 0
+
+	for _, statement := range program.Body {
+		if err := p.statement(statement); err != nil {
+			return "", err
+		}
+		p.builder.WriteString("\n")
+	}
+	for _, comment := range program.Comments {
+		p.builder.WriteString(comment.Value + "\n")
+	}
+	return p.builder.String(), nil
+}
+
+// statement writes the node between its comments, leading comments go on their own
+// lines and trailing comments follow the statement on its last line up to the first
+// line comment, the ones after it go on their own lines.
+func (p *Printer) statement(node *parser.Node) error {
+	if node.Comments != nil {
+		for _, comment := range node.Comments.Leading {
+			p.builder.WriteString(comment.Value + "\n")
+			p.writeIndent()
+		}
+	}
+	if err := p.statementBody(node); err != nil {
+		return err
+	}
+	if node.Comments != nil {
+		ownLine := false
+		for _, comment := range node.Comments.Trailing {
+			if ownLine {
+				p.builder.WriteString("\n")
+				p.writeIndent()
+			} else {
+				p.builder.WriteString(" ")
+			}
+			p.builder.WriteString(comment.Value)
+			ownLine = ownLine || strings.HasPrefix(comment.Value, "//")
+		}
+	}
+	return nil
+}
+
+func (p *Printer) statementBody(node *parser.Node) error {
+	switch node.NodeType {
+	case parser.EmptyStatement:
+		p.builder.WriteString(";")
+	case parser.ExpressionStatement:
+		if err := p.braceSafeExpression(node.Body.(*parser.Node), 0); err != nil {
+			return err
+		}
+		p.builder.WriteString(";")
+	case parser.BlockStatement:
+		return p.blockStatement(node)
+	case parser.VariableStatement:
+		return p.variableStatement(node.Body.(*parser.VariableStatementValue))
+	case parser.IfStatement:
+		return p.ifStatement(node.Body.(*parser.IfStatementValue))
+	case parser.SwitchStatement:
+		return p.switchStatement(node.Body.(*parser.SwitchStatementValue))
+	case parser.BreakStatement:
+		p.jumpStatement("break", node)
+	case parser.LabeledStatement:
+		value := node.Body.(*parser.LabeledStatementValue)
+		p.builder.WriteString(value.Label.Body.(*parser.StringLiteralValue).Value + ": ")
+		return p.statement(value.Body)
+	case parser.ReturnStatement:
+		p.builder.WriteString("return")
+		if argument, ok := node.Body.(*parser.Node); ok {
+			p.builder.WriteString(" ")
+			if err := p.expression(argument, 0); err != nil {
+				return err
+			}
+		}
+		p.builder.WriteString(";")
+	case parser.ThrowStatement:
+		p.builder.WriteString("throw ")
+		if err := p.expression(node.Body.(*parser.Node), 0); err != nil {
+			return err
+		}
+		p.builder.WriteString(";")
+	case parser.TryStatement:
+		return p.tryStatement(node.Body.(*parser.TryStatementValue))
+	case parser.ImportDeclaration:
+		p.importDeclaration(node.Body.(*parser.ImportDeclarationValue))
+	case parser.ExportDeclaration:
+		p.builder.WriteString("export ")
+		return p.statement(node.Body.(*parser.Node))
+	default:
+		return fmt.Errorf("unsupported statement: %s", node.NodeType)
+	}
+	return nil
+}
+
+// jumpStatement writes a break statement with its optional label.
+func (p *Printer) jumpStatement(keyword string, node *parser.Node) {
+	p.builder.WriteString(keyword)
+	if label, ok := node.Body.(*parser.Node); ok {
+		p.builder.WriteString(" " + label.Body.(*parser.StringLiteralValue).Value)
+	}
+	p.builder.WriteString(";")
+}
+
+// blockStatement writes the statements of the block and the comments after the last
+// one on lines of their own, a block without either is written as {}.
+func (p *Printer) blockStatement(node *parser.Node) error {
+	statements := node.Body.([]*parser.Node)
+	var inner []parser.Comment
+	if node.Comments != nil {
+		inner = node.Comments.Inner
+	}
+	if len(statements) == 0 && len(inner) == 0 {
+		p.builder.WriteString("{}")
+		return nil
+	}
+
+	p.builder.WriteString("{\n")
+	p.level++
+	for _, statement := range statements {
+		p.writeIndent()
+		if err := p.statement(statement); err != nil {
+			return err
+		}
+		p.builder.WriteString("\n")
+	}
+	for _, comment := range inner {
+		p.writeIndent()
+		p.builder.WriteString(comment.Value + "\n")
+	}
+	p.level--
+	p.writeIndent()
+	p.builder.WriteString("}")
+	return nil
+}
+
+func (p *Printer) variableStatement(node *parser.VariableStatementValue) error {
+	p.builder.WriteString(node.Kind + " ")
+	for index, declaration := range node.Declarations {
+		if index > 0 {
+			p.builder.WriteString(", ")
+		}
+		value := declaration.Body.(*parser.VariableDeclarationValue)
+		if err := p.expression(value.Id, primaryPrecedence); err != nil {
+			return err
+		}
+		if value.Type != nil {
+			p.builder.WriteString(": " + strings.Join(value.Type.Body.(*parser.TypeAnnotationValue).Types, " | "))
+		}
+		if value.Init != nil {
+			p.builder.WriteString(" = ")
+			if err := p.expression(value.Init, assignmentPrecedence); err != nil {
+				return err
+			}
+		}
+	}
+	p.builder.WriteString(";")
+	return nil
+}
+
+func (p *Printer) ifStatement(node *parser.IfStatementValue) error {
+	p.builder.WriteString("if (")
+	if err := p.expression(node.Test, 0); err != nil {
+		return err
+	}
+	p.builder.WriteString(") ")
+	if err := p.statement(node.Consequent); err != nil {
+		return err
+	}
+
+	if node.Alternate == nil {
+		return nil
+	}
+	if node.Consequent.NodeType == parser.BlockStatement && !hasTrailingComments(node.Consequent) {
+		p.builder.WriteString(" ")
+	} else {
+		p.builder.WriteString("\n")
+		p.writeIndent()
+	}
+	p.builder.WriteString("else ")
+	return p.statement(node.Alternate)
+}
+
+func (p *Printer) switchStatement(node *parser.SwitchStatementValue) error {
+	p.builder.WriteString("switch (")
+	if err := p.expression(node.Discriminant, 0); err != nil {
+		return err
+	}
+	p.builder.WriteString(") {\n")
+
+	p.level++
+	for _, clause := range node.Cases {
+		value := clause.Body.(*parser.SwitchCaseValue)
+		p.writeIndent()
+		if value.Test == nil {
+			p.builder.WriteString("default:\n")
+		} else {
+			p.builder.WriteString("case ")
+			if err := p.expression(value.Test, 0); err != nil {
+				return err
+			}
+			p.builder.WriteString(":\n")
+		}
+
+		p.level++
+		for _, statement := range value.Consequent {
+			p.writeIndent()
+			if err := p.statement(statement); err != nil {
+				return err
+			}
+			p.builder.WriteString("\n")
+		}
+		p.level--
+	}
+	p.level--
+
+	p.writeIndent()
+	p.builder.WriteString("}")
+	return nil
+}
+
+func (p *Printer) importDeclaration(node *parser.ImportDeclarationValue) {
+	p.builder.WriteString("import {")
+	for index, specifier := range node.Specifiers {
+		if index > 0 {
+			p.builder.WriteString(",")
+		}
+		value := specifier.Body.(*parser.ImportSpecifierValue)
+		imported := value.Imported.Body.(*parser.StringLiteralValue).Value
+		local := value.Local.Body.(*parser.StringLiteralValue).Value
+		p.builder.WriteString(" " + imported)
+		if local != imported {
+			p.builder.WriteString(" as " + local)
+		}
+	}
+	if len(node.Specifiers) > 0 {
+		p.builder.WriteString(" ")
+	}
+	p.builder.WriteString("} from " + quote(node.Source.Body.(*parser.StringLiteralValue).Value) + ";")
+}
+
+func (p *Printer) tryStatement(node *parser.TryStatementValue) error {
+	p.builder.WriteString("try ")
+	if err := p.statement(node.Block); err != nil {
+		return err
+	}
+
+	if node.Handler != nil {
+		handler := node.Handler.Body.(*parser.CatchClauseValue)
+		p.builder.WriteString(" catch ")
+		if handler.Param != nil {
+			p.builder.WriteString("(" + handler.Param.Body.(*parser.StringLiteralValue).Value + ") ")
+		}
+		if err := p.statement(handler.Body); err != nil {
+			return err
+		}
+	}
+
+	if node.Finalizer != nil {
+		p.builder.WriteString(" finally ")
+		if err := p.statement(node.Finalizer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expression writes the node, wrapping it in parentheses when it binds looser than
+// the surrounding context requires.
+func (p *Printer) expression(node *parser.Node, minPrecedence int) error {
+	switch node.NodeType {
+	case parser.AssignmentExpression:
+		return p.binaryExpression(node.Body.(*parser.BinaryExpressionNode), assignmentPrecedence, minPrecedence, true)
+	case parser.ConditionalExpression:
+		return p.conditionalExpression(node.Body.(*parser.ConditionalExpressionValue), minPrecedence)
+	case parser.BinaryExpression:
+		binary := node.Body.(*parser.BinaryExpressionNode)
+		return p.binaryExpression(binary, precedence[binary.Operator], minPrecedence, binary.Operator == "**")
+	case parser.FunctionExpression, parser.ArrowFunctionExpression:
+		return p.function(node, minPrecedence)
+	case parser.ChainExpression:
+		return p.expression(node.Body.(*parser.Node), minPrecedence)
+	case parser.CallExpression:
+		call := node.Body.(*parser.CallExpressionValue)
+		if err := p.chainLink(call.Callee); err != nil {
+			return err
+		}
+		if call.Optional {
+			p.builder.WriteString("?.")
+		}
+		return p.list(call.Arguments)
+	case parser.MemberExpression:
+		member := node.Body.(*parser.MemberExpressionValue)
+		if err := p.chainLink(member.Object); err != nil {
+			return err
+		}
+		if member.Optional {
+			p.builder.WriteString("?.")
+		}
+		if !member.Computed {
+			if !member.Optional {
+				p.builder.WriteString(".")
+			}
+			p.builder.WriteString(member.Property.Body.(*parser.StringLiteralValue).Value)
+			return nil
+		}
+		p.builder.WriteString("[")
+		if err := p.expression(member.Property, 0); err != nil {
+			return err
+		}
+		p.builder.WriteString("]")
+	case parser.ArrayExpression, parser.ArrayPattern:
+		return p.elements(node.Body.([]*parser.Node))
+	case parser.ObjectExpression, parser.ObjectPattern:
+		return p.properties(node.Body.([]*parser.Node))
+	case parser.SpreadElement, parser.RestElement:
+		p.builder.WriteString("...")
+		return p.expression(node.Body.(*parser.Node), assignmentPrecedence)
+	case parser.AssignmentPattern:
+		pattern := node.Body.(*parser.AssignmentPatternValue)
+		if err := p.expression(pattern.Left, primaryPrecedence); err != nil {
+			return err
+		}
+		p.builder.WriteString(" = ")
+		return p.expression(pattern.Right, assignmentPrecedence)
+	case parser.UnaryExpression:
+		return p.unaryExpression(node.Body.(*parser.UnaryExpressionValue), minPrecedence)
+	case parser.UpdateExpression:
+		update := node.Body.(*parser.UpdateExpressionValue)
+		if update.Prefix {
+			p.builder.WriteString(update.Operator)
+		}
+		if err := p.expression(update.Argument, primaryPrecedence); err != nil {
+			return err
+		}
+		if !update.Prefix {
+			p.builder.WriteString(update.Operator)
+		}
+	case parser.Identifier, parser.BooleanLiteral, parser.NullLiteral:
+		p.builder.WriteString(node.Body.(*parser.StringLiteralValue).Value)
+	case parser.NumericLiteral:
+		p.builder.WriteString(strconv.Itoa(node.Body.(*parser.NumericLiteralValue).Value))
+	case parser.StringLiteral:
+		p.builder.WriteString(quote(node.Body.(*parser.StringLiteralValue).Value))
+	case parser.RegExpLiteral:
+		value := node.Body.(*parser.RegExpLiteralValue)
+		p.builder.WriteString("/" + value.Pattern + "/" + value.Flags)
+	default:
+		return fmt.Errorf("unsupported expression: %s", node.NodeType)
+	}
+	return nil
+}
+
+func (p *Printer) binaryExpression(node *parser.BinaryExpressionNode, nodePrecedence int, minPrecedence int, rightAssociative bool) error {
+	parenthesize := nodePrecedence < minPrecedence
+	if parenthesize {
+		p.builder.WriteString("(")
+	}
+
+	leftPrecedence, rightPrecedence := nodePrecedence, nodePrecedence+1
+	if rightAssociative {
+		leftPrecedence, rightPrecedence = nodePrecedence+1, nodePrecedence
+	}
+	if nodePrecedence == exponentPrecedence {
+		// a unary operand on the left of ** must be parenthesized
+		leftPrecedence = updatePrecedence
+	}
+
+	if err := p.expression(node.Left.(*parser.Node), operandPrecedence(node.Operator, node.Left.(*parser.Node), leftPrecedence)); err != nil {
+		return err
+	}
+	p.builder.WriteString(" " + node.Operator + " ")
+	if err := p.expression(node.Right.(*parser.Node), operandPrecedence(node.Operator, node.Right.(*parser.Node), rightPrecedence)); err != nil {
+		return err
+	}
+
+	if parenthesize {
+		p.builder.WriteString(")")
+	}
+	return nil
+}
+
+// operandPrecedence parenthesizes a ?? operand of && or || and the other way round,
+// the parser rejects them mixed.
+func operandPrecedence(operator string, operand *parser.Node, minPrecedence int) int {
+	binary, ok := operand.Body.(*parser.BinaryExpressionNode)
+	if !ok || operand.NodeType != parser.BinaryExpression {
+		return minPrecedence
+	}
+	if isLogical(operator) && isLogical(binary.Operator) && (operator == "??") != (binary.Operator == "??") {
+		return primaryPrecedence
+	}
+	return minPrecedence
+}
+
+func isLogical(operator string) bool {
+	switch operator {
+	case "&&", "AND", "||", "OR", "??":
+		return true
+	}
+	return false
+}
+
+// chainLink writes the object of a member or call expression, a ChainExpression there
+// is parenthesized so the link does not join the chain.
+func (p *Printer) chainLink(node *parser.Node) error {
+	if node.NodeType != parser.ChainExpression {
+		return p.expression(node, callPrecedence)
+	}
+	p.builder.WriteString("(")
+	if err := p.expression(node, 0); err != nil {
+		return err
+	}
+	p.builder.WriteString(")")
+	return nil
+}
+
+// function writes function expressions as `function name(a) {...}` and arrow functions
+// as `(a) => ...`, an arrow function binds as loosely as an assignment.
+func (p *Printer) function(node *parser.Node, minPrecedence int) error {
+	value := node.Body.(*parser.FunctionValue)
+	arrow := node.NodeType == parser.ArrowFunctionExpression
+	parenthesize := arrow && assignmentPrecedence < minPrecedence
+	if parenthesize {
+		p.builder.WriteString("(")
+	}
+
+	if !arrow {
+		p.builder.WriteString("function ")
+		if value.Id != nil {
+			p.builder.WriteString(value.Id.Body.(*parser.StringLiteralValue).Value)
+		}
+	}
+	if err := p.list(value.Params); err != nil {
+		return err
+	}
+	p.builder.WriteString(" ")
+	if arrow {
+		p.builder.WriteString("=> ")
+	}
+	if value.Expression {
+		if err := p.braceSafeExpression(value.Body, assignmentPrecedence); err != nil {
+			return err
+		}
+	} else if err := p.blockStatement(value.Body); err != nil {
+		return err
+	}
+
+	if parenthesize {
+		p.builder.WriteString(")")
+	}
+	return nil
+}
+
+// list writes the expressions comma separated between parentheses.
+func (p *Printer) list(expressions []*parser.Node) error {
+	p.builder.WriteString("(")
+	for index, expression := range expressions {
+		if index > 0 {
+			p.builder.WriteString(", ")
+		}
+		if err := p.expression(expression, assignmentPrecedence); err != nil {
+			return err
+		}
+	}
+	p.builder.WriteString(")")
+	return nil
+}
+
+// elements writes an array literal or pattern, a hole at the end needs its own comma.
+func (p *Printer) elements(elements []*parser.Node) error {
+	p.builder.WriteString("[")
+	for index, element := range elements {
+		if index > 0 {
+			p.builder.WriteString(", ")
+		}
+		if element == nil {
+			if index == len(elements)-1 {
+				p.builder.WriteString(",")
+			}
+			continue
+		}
+		if err := p.expression(element, assignmentPrecedence); err != nil {
+			return err
+		}
+	}
+	p.builder.WriteString("]")
+	return nil
+}
+
+// properties writes an object literal or pattern as `{ a, b: c }`.
+func (p *Printer) properties(properties []*parser.Node) error {
+	if len(properties) == 0 {
+		p.builder.WriteString("{}")
+		return nil
+	}
+
+	p.builder.WriteString("{ ")
+	for index, property := range properties {
+		if index > 0 {
+			p.builder.WriteString(", ")
+		}
+		if property.NodeType != parser.Property {
+			if err := p.expression(property, assignmentPrecedence); err != nil {
+				return err
+			}
+			continue
+		}
+		value := property.Body.(*parser.PropertyValue)
+		if !value.Shorthand {
+			if err := p.expression(value.Key, primaryPrecedence); err != nil {
+				return err
+			}
+			p.builder.WriteString(": ")
+		}
+		if err := p.expression(value.Value, assignmentPrecedence); err != nil {
+			return err
+		}
+	}
+	p.builder.WriteString(" }")
+	return nil
+}
+
+// braceSafeExpression parenthesizes an expression that would begin with `{` where the
+// parser reads a block.
+func (p *Printer) braceSafeExpression(node *parser.Node, minPrecedence int) error {
+	if !startsWithBrace(node) {
+		return p.expression(node, minPrecedence)
+	}
+	p.builder.WriteString("(")
+	if err := p.expression(node, 0); err != nil {
+		return err
+	}
+	p.builder.WriteString(")")
+	return nil
+}
+
+// startsWithBrace reports whether the leftmost operand of the printed node is an object.
+func startsWithBrace(node *parser.Node) bool {
+	switch body := node.Body.(type) {
+	case *parser.BinaryExpressionNode:
+		return startsWithBrace(body.Left.(*parser.Node))
+	case *parser.ConditionalExpressionValue:
+		return startsWithBrace(body.Test)
+	case *parser.CallExpressionValue:
+		return startsWithBrace(body.Callee)
+	case *parser.MemberExpressionValue:
+		return startsWithBrace(body.Object)
+	case *parser.UpdateExpressionValue:
+		return !body.Prefix && startsWithBrace(body.Argument)
+	case *parser.Node:
+		return node.NodeType == parser.ChainExpression && startsWithBrace(body)
+	}
+	return node.NodeType == parser.ObjectExpression || node.NodeType == parser.ObjectPattern
+}
+
+func (p *Printer) unaryExpression(node *parser.UnaryExpressionValue, minPrecedence int) error {
+	parenthesize := unaryPrecedence < minPrecedence
+	if parenthesize {
+		p.builder.WriteString("(")
+	}
+
+	p.builder.WriteString(node.Operator)
+	if startsWithOperator(node.Argument, node.Operator) {
+		// keep - -x from reading as --x
+		p.builder.WriteString(" ")
+	}
+	if err := p.expression(node.Argument, unaryPrecedence); err != nil {
+		return err
+	}
+
+	if parenthesize {
+		p.builder.WriteString(")")
+	}
+	return nil
+}
+
+// startsWithOperator reports whether the printed node begins with the first character
+// of the operator.
+func startsWithOperator(node *parser.Node, operator string) bool {
+	switch body := node.Body.(type) {
+	case *parser.UnaryExpressionValue:
+		return body.Operator[:1] == operator[:1]
+	case *parser.UpdateExpressionValue:
+		return body.Prefix && body.Operator[:1] == operator[:1]
+	}
+	return false
+}
+
+func hasTrailingComments(node *parser.Node) bool {
+	return node.Comments != nil && len(node.Comments.Trailing) > 0
+}
+
+func (p *Printer) conditionalExpression(node *parser.ConditionalExpressionValue, minPrecedence int) error {
+	parenthesize := conditionalPrecedence < minPrecedence
+	if parenthesize {
+		p.builder.WriteString("(")
+	}
+
+	if err := p.expression(node.Test, conditionalPrecedence+1); err != nil {
+		return err
+	}
+	p.builder.WriteString(" ? ")
+	if err := p.expression(node.Consequent, assignmentPrecedence); err != nil {
+		return err
+	}
+	p.builder.WriteString(" : ")
+	if err := p.expression(node.Alternate, assignmentPrecedence); err != nil {
+		return err
+	}
+
+	if parenthesize {
+		p.builder.WriteString(")")
+	}
+	return nil
+}
+
+func (p *Printer) writeIndent() {
+	p.builder.WriteString(strings.Repeat(p.indent, p.level))
+}
+
+// quote prefers double quotes, the tokenizer has no escapes so strings containing
+// a double quote keep single quotes.
+func quote(value string) string {
+	if strings.Contains(value, `"`) {
+		return "'" + value + "'"
+	}
+	return `"` + value + `"`
+}
@@ -0,0 +1,225 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	text           string
+	indent         string
+	expectedOutput string
+	expectedError  error
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Statements", func(t *testing.T) {
+		tests := map[string]test{
+			"given literals": {
+				text:           `42; 'sith'; "darth 'vader'"; 'say "hi"'; true; null;`,
+				expectedOutput: "42;\n\"sith\";\n\"darth 'vader'\";\n'say \"hi\"';\ntrue;\nnull;\n",
+			},
+			"given empty statement": {
+				text:           `;`,
+				expectedOutput: ";\n",
+			},
+			"given variable statement": {
+				text:           `let x=1,y,z=x+y;`,
+				expectedOutput: "let x = 1, y, z = x + y;\n",
+			},
+			"given const and var statements": {
+				text:           `const x=1; var y,z=x;`,
+				expectedOutput: "const x = 1;\nvar y, z = x;\n",
+			},
+			"given empty block": {
+				text:           `{}`,
+				expectedOutput: "{}\n",
+			},
+			"given nested blocks": {
+				text:           `{ let x = 1; { x = 2; } }`,
+				expectedOutput: "{\n  let x = 1;\n  {\n    x = 2;\n  }\n}\n",
+			},
+			"given custom indent": {
+				text:           `{ x; }`,
+				indent:         "\t",
+				expectedOutput: "{\n\tx;\n}\n",
+			},
+			"given if else statement": {
+				text:           `if (x) { x = 1; } else if (y) { x = 2; } else { x = 3; }`,
+				expectedOutput: "if (x) {\n  x = 1;\n} else if (y) {\n  x = 2;\n} else {\n  x = 3;\n}\n",
+			},
+			"given switch statement": {
+				text:           `switch(x){case 1:case 2:y=1;break;default:{y=2;}}`,
+				expectedOutput: "switch (x) {\n  case 1:\n  case 2:\n    y = 1;\n    break;\n  default:\n    {\n      y = 2;\n    }\n}\n",
+			},
+			"given labeled statements": {
+				text:           `outer:{inner:if(x)break outer;else break inner;}`,
+				expectedOutput: "outer: {\n  inner: if (x) break outer;\n  else break inner;\n}\n",
+			},
+			"given try statements": {
+				text:           `try{throw "bad";}catch(e){e;}finally{} try{}catch{}`,
+				expectedOutput: "try {\n  throw \"bad\";\n} catch (e) {\n  e;\n} finally {}\ntry {} catch {}\n",
+			},
+			"given import and export declarations": {
+				text:           `import{a,b as c}from "./lib";import{}from "x";export const d=a;`,
+				expectedOutput: "import { a, b as c } from \"./lib\";\nimport {} from \"x\";\nexport const d = a;\n",
+			},
+			"given unary, update and bitwise operators": {
+				text:           `x=-(-y)+ -(--y)-~z++;a=(b|c)&d^e<<2;`,
+				expectedOutput: "x = - -y + - --y - ~z++;\na = (b | c) & d ^ e << 2;\n",
+			},
+			"given exponent operators": {
+				text:           `(2**3)**2;2**3**2;(-2)**2;2**-1;-(2**2);x**=2;`,
+				expectedOutput: "(2 ** 3) ** 2;\n2 ** 3 ** 2;\n(-2) ** 2;\n2 ** -1;\n-(2 ** 2);\nx **= 2;\n",
+			},
+			"given functions and calls": {
+				text:           `let f=function fact(n){if(n<2)return 1;return n*fact(n-1);};filter(items,x=>x.active,(a,b)=>{});(()=>1)();a.b(c)(d).e;x=>y=>x+y;`,
+				expectedOutput: "let f = function fact(n) {\n  if (n < 2) return 1;\n  return n * fact(n - 1);\n};\nfilter(items, (x) => x.active, (a, b) => {});\n(() => 1)();\na.b(c)(d).e;\n(x) => (y) => x + y;\n",
+			},
+			"given destructuring and literals": {
+				text:           `let{a,b:c=1,"d":[e,,f]}=o;[a,b]=[b,a[0]];({a=1}=o);let g=()=>({});[,];[a,,];({}).x;`,
+				expectedOutput: "let { a, b: c = 1, \"d\": [e, , f] } = o;\n[a, b] = [b, a[0]];\n({ a = 1 } = o);\nlet g = () => ({});\n[,];\n[a, ,];\n({}.x);\n",
+			},
+			"given spread and rest": {
+				text:           `f(...a,b);let c={...d,e,...{f}};let[g,...h]=[...i];({j,...k}=l);let m=function(n,...o){};(...p)=>p;`,
+				expectedOutput: "f(...a, b);\nlet c = { ...d, e, ...{ f } };\nlet [g, ...h] = [...i];\n({ j, ...k } = l);\nlet m = function (n, ...o) {};\n(...p) => p;\n",
+			},
+			"given optional chaining and nullish coalescing": {
+				text:           `a?.b.c;f?.(x)?.[k];(a?.b).c;(a?.b)();x??=a??b??c;(a||b)??(c&&d);a&&(b??c);a??(b??c);({}?.a);`,
+				expectedOutput: "a?.b.c;\nf?.(x)?.[k];\n(a?.b).c;\n(a?.b)();\nx ??= a ?? b ?? c;\n(a || b) ?? (c && d);\na && (b ?? c);\na ?? (b ?? c);\n({}?.a);\n",
+			},
+			"given if else statement without blocks": {
+				text:           `if (x) x = 1; else x = 2;`,
+				expectedOutput: "if (x) x = 1;\nelse x = 2;\n",
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, tc)
+			})
+		}
+	})
+	t.Run("Expressions", func(t *testing.T) {
+		tests := map[string]test{
+			"given precedence without parentheses": {
+				text:           `1 + 2 * 3;`,
+				expectedOutput: "1 + 2 * 3;\n",
+			},
+			"given parentheses changing precedence": {
+				text:           `(1 + 2) * 3;`,
+				expectedOutput: "(1 + 2) * 3;\n",
+			},
+			"given redundant parentheses": {
+				text:           `((1 * 2)) + (3);`,
+				expectedOutput: "1 * 2 + 3;\n",
+			},
+			"given right grouping of left associative operator": {
+				text:           `1 - (2 - 3);`,
+				expectedOutput: "1 - (2 - 3);\n",
+			},
+			"given left grouping of left associative operator": {
+				text:           `(1 - 2) - 3;`,
+				expectedOutput: "1 - 2 - 3;\n",
+			},
+			"given chained assignment": {
+				text:           `x = y += 2;`,
+				expectedOutput: "x = y += 2;\n",
+			},
+			"given logical expressions": {
+				text:           `x > 1 && (y || z == 2);`,
+				expectedOutput: "x > 1 && y || z == 2;\n",
+			},
+			"given conditional expressions": {
+				text:           `x = (a && b ? 1 : c ? 2 : 3);`,
+				expectedOutput: "x = a && b ? 1 : c ? 2 : 3;\n",
+			},
+			"given conditional expressions requiring parentheses": {
+				text:           `((a ? b : c) ? d : e) + 1;`,
+				expectedOutput: "((a ? b : c) ? d : e) + 1;\n",
+			},
+			"given logical expressions requiring parentheses": {
+				text:           `(x && y) || z;`,
+				expectedOutput: "(x && y) || z;\n",
+			},
+			"given regular expression": {
+				text:           `/^[a-z]+$/i.test(x);`,
+				expectedOutput: "/^[a-z]+$/i.test(x);\n",
+			},
+			"given type annotations": {
+				text:           `let x:number|null=1, y :string;`,
+				expectedOutput: "let x: number | null = 1, y: string;\n",
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				run(t, tc)
+			})
+		}
+	})
+	t.Run("Comments", func(t *testing.T) {
+		tests := map[string]test{
+			"given leading and trailing comments": {
+				text:           "/** The answer. */\nlet x=42; // inline\n// tail",
+				expectedOutput: "/** The answer. */\nlet x = 42; // inline\n// tail\n",
+			},
+			"given comments in blocks": {
+				text:           "{\n// first\nx = /* inside */ 1;\n// dangling\n}",
+				expectedOutput: "{\n  // first\n  x = 1; /* inside */\n  // dangling\n}\n",
+			},
+			"given comments in empty blocks": {
+				text:           "{ // nothing\n}\nlet f = () => { /* todo */ };",
+				expectedOutput: "{\n  // nothing\n}\nlet f = () => {\n  /* todo */\n};\n",
+			},
+			"given consecutive trailing line comments": {
+				text:           "let x = { a: 1, // first\n b: 2 // second\n};\nx;",
+				expectedOutput: "let x = { a: 1, b: 2 }; // first\n// second\nx;\n",
+			},
+			"given trailing line comments in block": {
+				text:           "{ f(1, // one\n2 /* two */, // three\n); }",
+				expectedOutput: "{\n  f(1, 2); // one\n  /* two */\n  // three\n}\n",
+			},
+			"given comment inside try statement": {
+				text:           "try { x; } // risky\nfinally {}",
+				expectedOutput: "try {\n  x;\n} finally {} // risky\n",
+			},
+			"given comment after consequent block": {
+				text:           "if (x) { y; } // then\nelse { z; }",
+				expectedOutput: "if (x) {\n  y;\n} // then\nelse {\n  z;\n}\n",
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				program, err := parser.New(parser.Props{Text: tc.text, Comments: true}).Run()
+				assert.NoError(t, err)
+
+				output, err := New(Props{}).Run(program)
+				assert.Equal(t, tc.expectedOutput, output)
+				assert.NoError(t, err)
+
+				reparsed, err := parser.New(parser.Props{Text: output, Comments: true}).Run()
+				assert.NoError(t, err)
+				reformatted, err := New(Props{}).Run(reparsed)
+				assert.NoError(t, err)
+				assert.Equal(t, output, reformatted)
+			})
+		}
+	})
+}
+
+func run(t *testing.T, tc test) {
+	program, err := parser.New(parser.Props{Text: tc.text}).Run()
+	assert.NoError(t, err)
+
+	output, err := New(Props{Indent: tc.indent}).Run(program)
+	assert.Equal(t, tc.expectedOutput, output)
+	assert.Equal(t, tc.expectedError, err)
+
+	reparsed, err := parser.New(parser.Props{Text: output}).Run()
+	assert.NoError(t, err)
+	assert.Equal(t, program, reparsed)
+}
@@ -6,31 +6,65 @@ import (
 	"regexp"
 )
 
+// Tokenizer
+// afterOperand is set when the last token read ends an operand, a '/' then divides
+// instead of starting a regular expression literal.
 type Tokenizer struct {
-	text   string
-	cursor int
+	text         string
+	cursor       int
+	emitTrivia   bool
+	lossless     bool
+	done         bool
+	afterOperand bool
 }
 
+// Props
+// EmitTrivia returns whitespace and comments as tokens instead of skipping them.
+// Lossless attaches whitespace and comments to the Leading trivia of the token that
+// follows them and ends the stream with an EOFToken holding the trailing trivia, so
+// the Text of every token concatenated reproduces the input. It takes precedence
+// over EmitTrivia.
 type Props struct {
-	Text string
+	Text       string
+	EmitTrivia bool
+	Lossless   bool
 }
 
 type Token struct {
 	TokenType string
 	Value     string
+	Start     int
+	Leading   []*Token `json:",omitempty"`
 }
 
 const (
 	NumberToken            string = "NUMBER"
 	StringToken                   = "STRING"
+	RegExpToken                   = "REGEXP"
 	SemiColonToken                = ";"
 	AdditiveOperator              = "+"
 	MultiplicativeOperator        = "*"
+	ExponentOperator              = "**"
+	UpdateOperator                = "UPDATE_OPERATOR"
+	ShiftOperator                 = "SHIFT_OPERATOR"
+	BitwiseAndOperator            = "&"
+	BitwiseOrOperator             = "|"
+	BitwiseXorOperator            = "^"
+	BitwiseNotOperator            = "~"
 	OpenCurlyBrace                = "{"
 	CloseCurlyBrace               = "}"
 	OpenParentheses               = "("
 	CloseParentheses              = ")"
+	OpenBracket                   = "["
+	CloseBracket                  = "]"
 	Comma                         = ","
+	QuestionMark                  = "?"
+	OptionalChaining              = "?."
+	NullishCoalescing             = "??"
+	Colon                         = ":"
+	Dot                           = "."
+	Spread                        = "..."
+	Arrow                         = "=>"
 	RelationalOperator            = "RELATIONAL_OPERATOR"
 	LogicalAnd                    = "LOGICAL_AND"
 	LogicalOr                     = "LOGICAL_Or"
@@ -39,18 +73,41 @@ const (
 	SimpleAssignment              = "SIMPLE_ASSIGNMENT"
 	ComplexAssignment             = "COMPLEX_ASSIGNMENT"
 	LetKeyword                    = "let"
+	ConstKeyword                  = "const"
+	VarKeyword                    = "var"
 	IfKeyword                     = "if"
 	ElseKeyword                   = "else"
+	SwitchKeyword                 = "switch"
+	CaseKeyword                   = "case"
+	DefaultKeyword                = "default"
+	BreakKeyword                  = "break"
+	ThrowKeyword                  = "throw"
+	TryKeyword                    = "try"
+	CatchKeyword                  = "catch"
+	FinallyKeyword                = "finally"
+	ImportKeyword                 = "import"
+	ExportKeyword                 = "export"
+	FunctionKeyword               = "function"
+	ReturnKeyword                 = "return"
 	TrueKeyword                   = "true"
 	FalseKeyword                  = "false"
 	NullKeyword                   = "null"
-	SkipToken                     = ""
+	WhitespaceToken               = "WHITESPACE"
+	CommentToken                  = "COMMENT"
+	EOFToken                      = "EOF"
+	// Deprecated: whitespace and comments are no longer skipped, they are read as
+	// WhitespaceToken and CommentToken trivia.
+	SkipToken = ""
 )
 
+var ErrNoTokens = errors.New("no tokens present")
+
 func New(props Props) *Tokenizer {
 	tokenizer := &Tokenizer{
-		text:   props.Text,
-		cursor: 0,
+		text:       props.Text,
+		cursor:     0,
+		emitTrivia: props.EmitTrivia,
+		lossless:   props.Lossless,
 	}
 	return tokenizer
 }
@@ -59,6 +116,11 @@ func (t *Tokenizer) hasMoreTokens() bool {
 	return t.cursor < len(t.text)
 }
 
+// Cursor is the offset in the text the next token is read from.
+func (t *Tokenizer) Cursor() int {
+	return t.cursor
+}
+
 func (t *Tokenizer) isEOF() bool {
 	return t.cursor == len(t.text)
 }
@@ -67,15 +129,15 @@ var spec = [][]string{
 	//---------------------------------------------------
 	// Whitespace
 
-	{`^\s+`, SkipToken},
+	{`^\s+`, WhitespaceToken},
 
 	//---------------------------------------------------
 	// Comments
 
-	// skip single-line comment
-	{`^//.*`, SkipToken},
-	// skip multi-line comment
-	{`^/\*[\s\S]*?\*/`, SkipToken},
+	// single-line comment, emitted as a comment token
+	{`^//.*`, CommentToken},
+	// multi-line comment, emitted as a comment token
+	{`^/\*[\s\S]*?\*/`, CommentToken},
 
 	//---------------------------------------------------
 	// Symbols, Delimiters
@@ -85,14 +147,38 @@ var spec = [][]string{
 	{`^}`, CloseCurlyBrace},
 	{`^\(`, OpenParentheses},
 	{`^\)`, CloseParentheses},
+	{`^\[`, OpenBracket},
+	{`^\]`, CloseBracket},
 	{`^\,`, Comma},
+	{`^\?\?=`, ComplexAssignment},
+	{`^\?\?`, NullishCoalescing},
+	{`^\?\.`, OptionalChaining},
+	{`^\?`, QuestionMark},
+	{`^:`, Colon},
+	{`^\.\.\.`, Spread},
+	{`^\.`, Dot},
+	{`^=>`, Arrow},
 
 	//---------------------------------------------------
 	// Keywords
 
 	{`^\blet\b`, LetKeyword},
+	{`^\bconst\b`, ConstKeyword},
+	{`^\bvar\b`, VarKeyword},
 	{`^\bif\b`, IfKeyword},
 	{`^\belse\b`, ElseKeyword},
+	{`^\bswitch\b`, SwitchKeyword},
+	{`^\bcase\b`, CaseKeyword},
+	{`^\bdefault\b`, DefaultKeyword},
+	{`^\bbreak\b`, BreakKeyword},
+	{`^\bthrow\b`, ThrowKeyword},
+	{`^\btry\b`, TryKeyword},
+	{`^\bcatch\b`, CatchKeyword},
+	{`^\bfinally\b`, FinallyKeyword},
+	{`^\bimport\b`, ImportKeyword},
+	{`^\bexport\b`, ExportKeyword},
+	{`^\bfunction\b`, FunctionKeyword},
+	{`^\breturn\b`, ReturnKeyword},
 	{`^\btrue\b`, TrueKeyword},
 	{`^\bfalse\b`, FalseKeyword},
 	{`^\bnull\b`, NullKeyword},
@@ -114,26 +200,41 @@ var spec = [][]string{
 	{`^\w+`, Identifier},
 
 	//---------------------------------------------------
-	// Equality Operator
+	// Equality operators ==, !=, ===, !==
 
-	{`^[=!]=`, EqualityOperator},
+	{`^[=!]==?`, EqualityOperator},
 
 	//---------------------------------------------------
-	// Assignment operators =, +=, -=, *=, /=
+	// Assignment operators =, +=, -=, *=, /=, %=, **=, <<=, >>=, >>>=, &=, |=, ^=
 
 	{`^=`, SimpleAssignment},
-	{`^[+\-*/]=`, ComplexAssignment},
+	{`^(\*\*|<<|>>>|>>|[+\-*/%&|^])=`, ComplexAssignment},
+
+	//---------------------------------------------------
+	// Update operators ++, --
+
+	{`^(\+\+|--)`, UpdateOperator},
 
 	//---------------------------------------------------
-	// Math operators +, -, *, /
+	// Math operators +, -, *, /, %, **
 
-	{`^[+|-]`, AdditiveOperator},
-	{`^[*|/]`, MultiplicativeOperator},
+	{`^\*\*`, ExponentOperator},
+	{`^[+\-]`, AdditiveOperator},
+	{`^[*/%]`, MultiplicativeOperator},
+
+	//---------------------------------------------------
+	// Bitwise operators <<, >>, >>>, &, |, ^, ~
+
+	{`^(<<|>>>|>>)`, ShiftOperator},
+	{`^&`, BitwiseAndOperator},
+	{`^\|`, BitwiseOrOperator},
+	{`^\^`, BitwiseXorOperator},
+	{`^~`, BitwiseNotOperator},
 
 	//---------------------------------------------------
 	// Relational operators >, >=, <, <=
 
-	{`^[>|<]=?`, RelationalOperator},
+	{`^[<>]=?`, RelationalOperator},
 
 	//---------------------------------------------------
 	// Strings
@@ -142,30 +243,165 @@ var spec = [][]string{
 	{`^'[^']*'`, StringToken},
 }
 
+// IsTrivia reports whether tokens of the type carry no meaning for the grammar.
+func IsTrivia(tokenType string) bool {
+	return tokenType == WhitespaceToken || tokenType == CommentToken
+}
+
 func (t *Tokenizer) GetNextToken() (*Token, error) {
+	if t.lossless {
+		return t.nextWithTrivia()
+	}
+	for {
+		token, err := t.next()
+		if err != nil {
+			return nil, err
+		}
+		if IsTrivia(token.TokenType) && !t.emitTrivia {
+			continue
+		}
+		return token, nil
+	}
+}
+
+// Tokens reads every remaining token of the text.
+func (t *Tokenizer) Tokens() ([]*Token, error) {
+	tokens := make([]*Token, 0)
+	for {
+		token, err := t.GetNextToken()
+		if errors.Is(err, ErrNoTokens) {
+			return tokens, nil
+		}
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+// Text is the source text of the token, its leading trivia included.
+func (token *Token) Text() string {
+	text := ""
+	for _, trivia := range token.Leading {
+		text += trivia.Value
+	}
+	return text + token.Value
+}
+
+func (t *Tokenizer) nextWithTrivia() (*Token, error) {
+	if t.done {
+		return nil, ErrNoTokens
+	}
+
+	leading := make([]*Token, 0)
+	for {
+		token, err := t.next()
+		if errors.Is(err, ErrNoTokens) {
+			t.done = true
+			return &Token{TokenType: EOFToken, Start: t.cursor, Leading: leading}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if IsTrivia(token.TokenType) {
+			leading = append(leading, token)
+			continue
+		}
+		if len(leading) > 0 {
+			token.Leading = leading
+		}
+		return token, nil
+	}
+}
+
+// regExpLiteral matches a regular expression literal, a '/' inside a character class
+// or escaped does not end it.
+var regExpLiteral = regexp.MustCompile(`^/(?:[^/\\\[\n]|\\.|\[(?:[^\]\\\n]|\\.)*\])+/[A-Za-z]*`)
+
+func (t *Tokenizer) next() (*Token, error) {
+	token, err := t.read()
+	if err == nil && !IsTrivia(token.TokenType) {
+		t.afterOperand = endsOperand(token.TokenType)
+	}
+	return token, err
+}
+
+func (t *Tokenizer) read() (*Token, error) {
 	if !t.hasMoreTokens() {
-		return nil, errors.New("no tokens present")
+		return nil, ErrNoTokens
 	}
 
 	characters := []byte(t.text)[t.cursor:]
 
+	if !t.afterOperand && isRegExpStart(characters) {
+		// without a closing '/' on the line it is read as an operator
+		start := t.cursor
+		if tokenValue := t.match(regExpLiteral, string(characters)); tokenValue != "" {
+			return &Token{TokenType: RegExpToken, Value: tokenValue, Start: start}, nil
+		}
+	}
+
 	for _, spec := range spec {
 		regexText := spec[0]
 		tokenType := spec[1]
 		regex := regexp.MustCompile(regexText)
+		start := t.cursor
 		tokenValue := t.match(regex, string(characters))
 		if tokenValue == "" {
 			continue
 		}
-		if tokenType == SkipToken {
-			return t.GetNextToken()
-		}
-		return &Token{TokenType: tokenType, Value: tokenValue}, nil
+		return &Token{TokenType: tokenType, Value: tokenValue, Start: start}, nil
 	}
 
 	return nil, fmt.Errorf(`unexpected token: %s`, string(characters[0]))
 }
 
+// ReadRegExp reads the token again as the start of a regular expression literal, for
+// a '/' the tokenizer took for division where the parser expects an operand. The
+// token must be the last one read.
+func (t *Tokenizer) ReadRegExp(token *Token) (*Token, error) {
+	t.cursor = token.Start
+	tokenValue := t.match(regExpLiteral, t.text[t.cursor:])
+	if tokenValue == "" {
+		t.cursor = token.Start + len(token.Value)
+		return nil, errors.New("unterminated regular expression literal")
+	}
+	t.afterOperand = true
+	return &Token{TokenType: RegExpToken, Value: tokenValue, Start: token.Start, Leading: token.Leading}, nil
+}
+
+// isRegExpStart reports whether the text starts with a '/' that is not a comment.
+func isRegExpStart(characters []byte) bool {
+	return len(characters) > 0 && characters[0] == '/' &&
+		(len(characters) == 1 || characters[1] != '/' && characters[1] != '*')
+}
+
+// endsOperand reports whether a token of the type can end an operand, where a '/' is
+// the division operator. A '}' is taken to close an object literal and ')' to close
+// a parenthesized expression.
+func endsOperand(tokenType string) bool {
+	switch tokenType {
+	case Identifier, NumberToken, StringToken, RegExpToken, TrueKeyword, FalseKeyword, NullKeyword,
+		CloseParentheses, CloseBracket, CloseCurlyBrace, UpdateOperator:
+		return true
+	}
+	return false
+}
+
+// ContainsComment reports whether the text has a comment outside of a string literal.
+func ContainsComment(text string) bool {
+	t := New(Props{Text: text, EmitTrivia: true})
+	for {
+		token, err := t.GetNextToken()
+		if err != nil {
+			return false
+		}
+		if token.TokenType == CommentToken {
+			return true
+		}
+	}
+}
+
 func (t *Tokenizer) match(regex *regexp.Regexp, text string) string {
 	matchedToken := regex.FindString(text)
 	if matchedToken == "" {
@@ -69,6 +69,69 @@ func TestGetNextToken(t *testing.T) {
 					Value:     ",",
 				},
 			},
+			"given ?": {
+				tokenizerText: `?`,
+				expectedToken: &Token{
+					TokenType: QuestionMark,
+					Value:     "?",
+				},
+			},
+			"given .": {
+				tokenizerText: `.`,
+				expectedToken: &Token{
+					TokenType: Dot,
+					Value:     `.`,
+				},
+			},
+			"given ...": {
+				tokenizerText: `...`,
+				expectedToken: &Token{
+					TokenType: Spread,
+					Value:     `...`,
+				},
+			},
+			"given ?.": {
+				tokenizerText: `?.`,
+				expectedToken: &Token{
+					TokenType: OptionalChaining,
+					Value:     `?.`,
+				},
+			},
+			"given ??": {
+				tokenizerText: `??`,
+				expectedToken: &Token{
+					TokenType: NullishCoalescing,
+					Value:     `??`,
+				},
+			},
+			"given =>": {
+				tokenizerText: `=>`,
+				expectedToken: &Token{
+					TokenType: Arrow,
+					Value:     `=>`,
+				},
+			},
+			"given [": {
+				tokenizerText: `[`,
+				expectedToken: &Token{
+					TokenType: OpenBracket,
+					Value:     `[`,
+				},
+			},
+			"given ]": {
+				tokenizerText: `]`,
+				expectedToken: &Token{
+					TokenType: CloseBracket,
+					Value:     `]`,
+				},
+			},
+			"given :": {
+				tokenizerText: `:`,
+				expectedToken: &Token{
+					TokenType: Colon,
+					Value:     ":",
+				},
+			},
 		}
 
 		for name, tc := range tests {
@@ -110,6 +173,34 @@ func TestGetNextToken(t *testing.T) {
 					Value:     "/",
 				},
 			},
+			"given %": {
+				tokenizerText: `%`,
+				expectedToken: &Token{
+					TokenType: MultiplicativeOperator,
+					Value:     `%`,
+				},
+			},
+			"given **": {
+				tokenizerText: `**`,
+				expectedToken: &Token{
+					TokenType: ExponentOperator,
+					Value:     `**`,
+				},
+			},
+			"given ++": {
+				tokenizerText: `++`,
+				expectedToken: &Token{
+					TokenType: UpdateOperator,
+					Value:     `++`,
+				},
+			},
+			"given --": {
+				tokenizerText: `--`,
+				expectedToken: &Token{
+					TokenType: UpdateOperator,
+					Value:     `--`,
+				},
+			},
 		}
 
 		for name, tc := range tests {
@@ -121,6 +212,42 @@ func TestGetNextToken(t *testing.T) {
 			})
 		}
 	})
+	t.Run("RegExpToken", func(t *testing.T) {
+		tests := map[string]test{
+			"given regular expression": {
+				tokenizerText: `/^[a-z]+$/i`,
+				expectedToken: &Token{
+					TokenType: RegExpToken,
+					Value:     `/^[a-z]+$/i`,
+				},
+			},
+			"given slash in character class": {
+				tokenizerText: `/[/]\//`,
+				expectedToken: &Token{
+					TokenType: RegExpToken,
+					Value:     `/[/]\//`,
+				},
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				tokenizer := New(Props{Text: tc.tokenizerText})
+				token, err := tokenizer.GetNextToken()
+				assert.Equal(t, tc.expectedToken, token)
+				assert.Equal(t, tc.expectedError, err)
+			})
+		}
+		t.Run("given slash after operand, return division", func(t *testing.T) {
+			tokens, err := New(Props{Text: `a /b/ 2`}).Tokens()
+			assert.Nil(t, err)
+			types := make([]string, 0)
+			for _, token := range tokens {
+				types = append(types, token.TokenType)
+			}
+			assert.Equal(t, []string{Identifier, MultiplicativeOperator, Identifier, MultiplicativeOperator, NumberToken}, types)
+		})
+	})
 	t.Run("Comment", func(t *testing.T) {
 		tests := map[string]test{
 			"given number after single line comment": {
@@ -131,6 +258,7 @@ func TestGetNextToken(t *testing.T) {
 				expectedToken: &Token{
 					TokenType: NumberToken,
 					Value:     "1",
+					Start:     12,
 				},
 			},
 			"given number after multi line comment": {
@@ -143,6 +271,7 @@ comment
 				expectedToken: &Token{
 					TokenType: NumberToken,
 					Value:     "1",
+					Start:     16,
 				},
 			},
 		}
@@ -174,6 +303,7 @@ comment
 				expectedToken: &Token{
 					TokenType: NumberToken,
 					Value:     `123`,
+					Start:     8,
 				},
 			},
 			"given non numeric characters after number": {
@@ -220,6 +350,7 @@ comment
 					expectedToken: &Token{
 						TokenType: StringToken,
 						Value:     `"sith"`,
+						Start:     8,
 					},
 				},
 				"given characters after end of string": {
@@ -272,6 +403,7 @@ comment
 					expectedToken: &Token{
 						TokenType: StringToken,
 						Value:     `'sith'`,
+						Start:     6,
 					},
 				},
 				"given characters after end of string": {
@@ -364,6 +496,48 @@ comment
 					Value:     `/=`,
 				},
 			},
+			"given %=": {
+				tokenizerText: `%=`,
+				expectedToken: &Token{
+					TokenType: ComplexAssignment,
+					Value:     `%=`,
+				},
+			},
+			"given ??=": {
+				tokenizerText: `??=`,
+				expectedToken: &Token{
+					TokenType: ComplexAssignment,
+					Value:     `??=`,
+				},
+			},
+			"given **=": {
+				tokenizerText: `**=`,
+				expectedToken: &Token{
+					TokenType: ComplexAssignment,
+					Value:     `**=`,
+				},
+			},
+			"given >>>=": {
+				tokenizerText: `>>>=`,
+				expectedToken: &Token{
+					TokenType: ComplexAssignment,
+					Value:     `>>>=`,
+				},
+			},
+			"given &=": {
+				tokenizerText: `&=`,
+				expectedToken: &Token{
+					TokenType: ComplexAssignment,
+					Value:     `&=`,
+				},
+			},
+			"given |=": {
+				tokenizerText: `|=`,
+				expectedToken: &Token{
+					TokenType: ComplexAssignment,
+					Value:     `|=`,
+				},
+			},
 		}
 
 		for name, tc := range tests {
@@ -398,6 +572,90 @@ comment
 					Value:     `else`,
 				},
 			},
+			"given switch": {
+				tokenizerText: `switch`,
+				expectedToken: &Token{
+					TokenType: SwitchKeyword,
+					Value:     `switch`,
+				},
+			},
+			"given case": {
+				tokenizerText: `case`,
+				expectedToken: &Token{
+					TokenType: CaseKeyword,
+					Value:     `case`,
+				},
+			},
+			"given default": {
+				tokenizerText: `default`,
+				expectedToken: &Token{
+					TokenType: DefaultKeyword,
+					Value:     `default`,
+				},
+			},
+			"given break": {
+				tokenizerText: `break`,
+				expectedToken: &Token{
+					TokenType: BreakKeyword,
+					Value:     `break`,
+				},
+			},
+			"given throw": {
+				tokenizerText: `throw`,
+				expectedToken: &Token{
+					TokenType: ThrowKeyword,
+					Value:     `throw`,
+				},
+			},
+			"given try": {
+				tokenizerText: `try`,
+				expectedToken: &Token{
+					TokenType: TryKeyword,
+					Value:     `try`,
+				},
+			},
+			"given catch": {
+				tokenizerText: `catch`,
+				expectedToken: &Token{
+					TokenType: CatchKeyword,
+					Value:     `catch`,
+				},
+			},
+			"given finally": {
+				tokenizerText: `finally`,
+				expectedToken: &Token{
+					TokenType: FinallyKeyword,
+					Value:     `finally`,
+				},
+			},
+			"given import": {
+				tokenizerText: `import`,
+				expectedToken: &Token{
+					TokenType: ImportKeyword,
+					Value:     `import`,
+				},
+			},
+			"given export": {
+				tokenizerText: `export`,
+				expectedToken: &Token{
+					TokenType: ExportKeyword,
+					Value:     `export`,
+				},
+			},
+			"given function": {
+				tokenizerText: `function`,
+				expectedToken: &Token{
+					TokenType: FunctionKeyword,
+					Value:     `function`,
+				},
+			},
+			"given return": {
+				tokenizerText: `return`,
+				expectedToken: &Token{
+					TokenType: ReturnKeyword,
+					Value:     `return`,
+				},
+			},
 			"given true": {
 				tokenizerText: `true`,
 				expectedToken: &Token{
@@ -471,6 +729,68 @@ comment
 			})
 		}
 	})
+	t.Run("Bitwise Operator", func(t *testing.T) {
+		tests := map[string]test{
+			"given &": {
+				tokenizerText: `&`,
+				expectedToken: &Token{
+					TokenType: BitwiseAndOperator,
+					Value:     `&`,
+				},
+			},
+			"given |": {
+				tokenizerText: `|`,
+				expectedToken: &Token{
+					TokenType: BitwiseOrOperator,
+					Value:     `|`,
+				},
+			},
+			"given ^": {
+				tokenizerText: `^`,
+				expectedToken: &Token{
+					TokenType: BitwiseXorOperator,
+					Value:     `^`,
+				},
+			},
+			"given ~": {
+				tokenizerText: `~`,
+				expectedToken: &Token{
+					TokenType: BitwiseNotOperator,
+					Value:     `~`,
+				},
+			},
+			"given <<": {
+				tokenizerText: `<<`,
+				expectedToken: &Token{
+					TokenType: ShiftOperator,
+					Value:     `<<`,
+				},
+			},
+			"given >>": {
+				tokenizerText: `>>`,
+				expectedToken: &Token{
+					TokenType: ShiftOperator,
+					Value:     `>>`,
+				},
+			},
+			"given >>>": {
+				tokenizerText: `>>>`,
+				expectedToken: &Token{
+					TokenType: ShiftOperator,
+					Value:     `>>>`,
+				},
+			},
+		}
+
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				tokenizer := New(Props{Text: tc.tokenizerText})
+				token, err := tokenizer.GetNextToken()
+				assert.Equal(t, tc.expectedToken, token)
+				assert.Equal(t, tc.expectedError, err)
+			})
+		}
+	})
 	t.Run("Equality Operator", func(t *testing.T) {
 		tests := map[string]test{
 			"given ==": {
@@ -487,6 +807,20 @@ comment
 					Value:     `!=`,
 				},
 			},
+			"given ===": {
+				tokenizerText: `===`,
+				expectedToken: &Token{
+					TokenType: EqualityOperator,
+					Value:     `===`,
+				},
+			},
+			"given !==": {
+				tokenizerText: `!==`,
+				expectedToken: &Token{
+					TokenType: EqualityOperator,
+					Value:     `!==`,
+				},
+			},
 		}
 
 		for name, tc := range tests {
@@ -540,3 +874,90 @@ comment
 		}
 	})
 }
+
+func TestContainsComment(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected bool
+	}{
+		"given no comment":                {text: `let x = 1;`, expected: false},
+		"given single line comment":       {text: "let x = 1; // one", expected: true},
+		"given multi line comment":        {text: "/* one */\nlet x = 1;", expected: true},
+		"given comment markers in string": {text: `let x = "// /* */";`, expected: false},
+		"given trailing whitespace":       {text: "let x = 1;\n\n", expected: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ContainsComment(tc.text))
+		})
+	}
+}
+
+func TestEmitTrivia(t *testing.T) {
+	t.Run("given EmitTrivia, return whitespace and comments as tokens", func(t *testing.T) {
+		tokenizer := New(Props{Text: "x // one\n/* two */1", EmitTrivia: true})
+		tokens := make([]*Token, 0)
+		for {
+			token, err := tokenizer.GetNextToken()
+			if err != nil {
+				assert.Equal(t, ErrNoTokens, err)
+				break
+			}
+			tokens = append(tokens, token)
+		}
+		assert.Equal(t, []*Token{
+			{TokenType: Identifier, Value: "x", Start: 0},
+			{TokenType: WhitespaceToken, Value: " ", Start: 1},
+			{TokenType: CommentToken, Value: "// one", Start: 2},
+			{TokenType: WhitespaceToken, Value: "\n", Start: 8},
+			{TokenType: CommentToken, Value: "/* two */", Start: 9},
+			{TokenType: NumberToken, Value: "1", Start: 18},
+		}, tokens)
+	})
+}
+
+func TestLossless(t *testing.T) {
+	t.Run("given Lossless, attach trivia to the following token", func(t *testing.T) {
+		tokens, err := New(Props{Text: "/** doc */\nlet x; // end\n", Lossless: true}).Tokens()
+		assert.NoError(t, err)
+		assert.Equal(t, []*Token{
+			{TokenType: LetKeyword, Value: "let", Start: 11, Leading: []*Token{
+				{TokenType: CommentToken, Value: "/** doc */", Start: 0},
+				{TokenType: WhitespaceToken, Value: "\n", Start: 10},
+			}},
+			{TokenType: Identifier, Value: "x", Start: 15, Leading: []*Token{
+				{TokenType: WhitespaceToken, Value: " ", Start: 14},
+			}},
+			{TokenType: SemiColonToken, Value: ";", Start: 16},
+			{TokenType: EOFToken, Value: "", Start: 25, Leading: []*Token{
+				{TokenType: WhitespaceToken, Value: " ", Start: 17},
+				{TokenType: CommentToken, Value: "// end", Start: 18},
+				{TokenType: WhitespaceToken, Value: "\n", Start: 24},
+			}},
+		}, tokens)
+	})
+	t.Run("given Lossless, concatenated token text reproduces the input", func(t *testing.T) {
+		texts := []string{
+			"",
+			"   ",
+			"let x = 'a // b';\n/* c\n */ if (x) { x += 1; } else {}\t\n",
+			"// only a comment",
+		}
+		for _, text := range texts {
+			tokens, err := New(Props{Text: text, Lossless: true}).Tokens()
+			assert.NoError(t, err)
+			source := ""
+			for _, token := range tokens {
+				source += token.Text()
+			}
+			assert.Equal(t, text, source)
+			assert.Equal(t, EOFToken, tokens[len(tokens)-1].TokenType)
+		}
+	})
+	t.Run("given Lossless and unknown character, return error", func(t *testing.T) {
+		tokens, err := New(Props{Text: "x @", Lossless: true}).Tokens()
+		assert.Equal(t, errors.New("unexpected token: @"), err)
+		assert.Equal(t, []*Token{{TokenType: Identifier, Value: "x", Start: 0}}, tokens)
+	})
+}
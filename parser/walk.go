@@ -0,0 +1,140 @@
+package parser
+
+// Children returns the nodes directly beneath node in source order.
+func Children(node *Node) []*Node {
+	children := make([]*Node, 0)
+	appendNode := func(child interface{}) {
+		if childNode, ok := child.(*Node); ok && childNode != nil {
+			children = append(children, childNode)
+		}
+	}
+
+	switch body := node.Body.(type) {
+	case *Node:
+		appendNode(body)
+	case []*Node:
+		for _, child := range body {
+			appendNode(child)
+		}
+	case *VariableStatementValue:
+		for _, child := range body.Declarations {
+			appendNode(child)
+		}
+	case *BinaryExpressionNode:
+		appendNode(body.Left)
+		appendNode(body.Right)
+	case *VariableDeclarationValue:
+		appendNode(body.Id)
+		appendNode(body.Type)
+		appendNode(body.Init)
+	case *IfStatementValue:
+		appendNode(body.Test)
+		appendNode(body.Consequent)
+		appendNode(body.Alternate)
+	case *LabeledStatementValue:
+		appendNode(body.Label)
+		appendNode(body.Body)
+	case *SwitchStatementValue:
+		appendNode(body.Discriminant)
+		for _, child := range body.Cases {
+			appendNode(child)
+		}
+	case *SwitchCaseValue:
+		appendNode(body.Test)
+		for _, child := range body.Consequent {
+			appendNode(child)
+		}
+	case *TryStatementValue:
+		appendNode(body.Block)
+		appendNode(body.Handler)
+		appendNode(body.Finalizer)
+	case *CatchClauseValue:
+		appendNode(body.Param)
+		appendNode(body.Body)
+	case *ImportDeclarationValue:
+		for _, child := range body.Specifiers {
+			appendNode(child)
+		}
+		appendNode(body.Source)
+	case *ImportSpecifierValue:
+		appendNode(body.Imported)
+		if body.Local != body.Imported {
+			appendNode(body.Local)
+		}
+	case *FunctionValue:
+		appendNode(body.Id)
+		for _, child := range body.Params {
+			appendNode(child)
+		}
+		appendNode(body.Body)
+	case *CallExpressionValue:
+		appendNode(body.Callee)
+		for _, child := range body.Arguments {
+			appendNode(child)
+		}
+	case *MemberExpressionValue:
+		appendNode(body.Object)
+		appendNode(body.Property)
+	case *PropertyValue:
+		if !body.Shorthand {
+			appendNode(body.Key)
+		}
+		appendNode(body.Value)
+	case *AssignmentPatternValue:
+		appendNode(body.Left)
+		appendNode(body.Right)
+	case *UnaryExpressionValue:
+		appendNode(body.Argument)
+	case *UpdateExpressionValue:
+		appendNode(body.Argument)
+	case *ConditionalExpressionValue:
+		appendNode(body.Test)
+		appendNode(body.Consequent)
+		appendNode(body.Alternate)
+	}
+	return children
+}
+
+// BindingIdentifiers returns the Identifiers a binding target or assignment pattern
+// binds, in source order. Defaults are left out.
+func BindingIdentifiers(target *Node) []*Node {
+	identifiers := make([]*Node, 0)
+	var collect func(node *Node)
+	collect = func(node *Node) {
+		if node == nil {
+			return
+		}
+		switch node.NodeType {
+		case Identifier:
+			identifiers = append(identifiers, node)
+		case ArrayPattern:
+			for _, element := range node.Body.([]*Node) {
+				collect(element)
+			}
+		case ObjectPattern:
+			for _, property := range node.Body.([]*Node) {
+				if property.NodeType == RestElement {
+					collect(property)
+					continue
+				}
+				collect(property.Body.(*PropertyValue).Value)
+			}
+		case AssignmentPattern:
+			collect(node.Body.(*AssignmentPatternValue).Left)
+		case RestElement:
+			collect(node.Body.(*Node))
+		}
+	}
+	collect(target)
+	return identifiers
+}
+
+// Walk calls fn for node and, for as long as fn returns true, for every node beneath it.
+func Walk(node *Node, fn func(node *Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+	for _, child := range Children(node) {
+		Walk(child, fn)
+	}
+}
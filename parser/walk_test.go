@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	t.Run("given program, visit every node in source order", func(t *testing.T) {
+		program, err := New(Props{Text: `let x = 1; if (x) { x = x + 2; } else ;`}).Run()
+		assert.NoError(t, err)
+
+		nodeTypes := make([]string, 0)
+		for _, statement := range program.Body {
+			Walk(statement, func(node *Node) bool {
+				nodeTypes = append(nodeTypes, node.NodeType)
+				return true
+			})
+		}
+		assert.Equal(t, []string{
+			VariableStatement, VariableDeclaration, Identifier, NumericLiteral,
+			IfStatement, Identifier, BlockStatement, ExpressionStatement, AssignmentExpression,
+			Identifier, BinaryExpression, Identifier, NumericLiteral, EmptyStatement,
+		}, nodeTypes)
+	})
+	t.Run("given fn returning false, skip children", func(t *testing.T) {
+		program, err := New(Props{Text: `{ x; } y;`}).Run()
+		assert.NoError(t, err)
+
+		nodeTypes := make([]string, 0)
+		for _, statement := range program.Body {
+			Walk(statement, func(node *Node) bool {
+				nodeTypes = append(nodeTypes, node.NodeType)
+				return node.NodeType != BlockStatement
+			})
+		}
+		assert.Equal(t, []string{BlockStatement, ExpressionStatement, Identifier}, nodeTypes)
+	})
+}
+
+func TestBindingIdentifiers(t *testing.T) {
+	t.Run("given pattern, return bound identifiers in source order", func(t *testing.T) {
+		program, err := New(Props{Text: `let { a, b: [c, , ...d] = e, ...f } = g;`}).Run()
+		assert.NoError(t, err)
+
+		id := program.Body[0].Body.(*VariableStatementValue).Declarations[0].Body.(*VariableDeclarationValue).Id
+		names := make([]string, 0)
+		for _, identifier := range BindingIdentifiers(id) {
+			names = append(names, identifier.Body.(*StringLiteralValue).Value)
+		}
+		assert.Equal(t, []string{"a", "c", "d", "f"}, names)
+	})
+}
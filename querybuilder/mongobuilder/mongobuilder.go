@@ -13,8 +13,11 @@ type MongoFilterBuilder struct {
 	parser *queryparser.QueryParser
 }
 
+// Props
+// MaxDepth bounds how deeply the functions of a query may nest, see queryparser.Props.
 type Props struct {
 	LiteralComparisonFields []string
+	MaxDepth                int
 }
 
 var OperatorMap = map[string]string {
@@ -30,7 +33,7 @@ var OperatorMap = map[string]string {
 func New(props Props) *MongoFilterBuilder {
 	return &MongoFilterBuilder{
 		literalComparisonFields: props.LiteralComparisonFields,
-		parser: queryparser.New(),
+		parser: queryparser.NewWithProps(queryparser.Props{MaxDepth: props.MaxDepth}),
 		parseTree:      nil,
 	}
 }
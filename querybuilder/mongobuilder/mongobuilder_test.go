@@ -76,7 +76,7 @@ func TestRun(t *testing.T) {
 
 		for name, tc := range tests {
 			t.Run(name, func(t *testing.T) {
-				queryBuilder := New(Props{literalComparisonFields})
+				queryBuilder := New(Props{LiteralComparisonFields: literalComparisonFields})
 				query, err := queryBuilder.Run(tc.filterParam)
 
 				assert.Equal(t, tc.expectedQuery, query)
@@ -160,7 +160,7 @@ func TestRun(t *testing.T) {
 
 		for name, tc := range tests {
 			t.Run(name, func(t *testing.T) {
-				queryBuilder := New(Props{literalComparisonFields})
+				queryBuilder := New(Props{LiteralComparisonFields: literalComparisonFields})
 				query, err := queryBuilder.Run(tc.filterParam)
 
 				assert.Equal(t, tc.expectedQuery, query)
@@ -264,7 +264,7 @@ func TestRun(t *testing.T) {
 
 		for name, tc := range tests {
 			t.Run(name, func(t *testing.T) {
-				queryBuilder := New(Props{literalComparisonFields})
+				queryBuilder := New(Props{LiteralComparisonFields: literalComparisonFields})
 				query, err := queryBuilder.Run(tc.filterParam)
 
 				assert.Equal(t, tc.expectedQuery, query)
@@ -57,10 +57,16 @@ BooleanLiteral
 type QueryParser struct {
 	lookAhead *querytokenizer.Token
 	tokenizer *querytokenizer.Tokenizer
+	maxDepth  int
+	depth     int
 }
 
+// Props
+// MaxDepth bounds how deeply functions may nest, DefaultMaxDepth is used when it is
+// left at zero and a negative value disables the limit.
 type Props struct {
-	Text string
+	Text     string
+	MaxDepth int
 }
 
 type Program struct {
@@ -112,15 +118,30 @@ const (
 	LogicalAndOperator                = "and"
 )
 
+const DefaultMaxDepth = 64
+
+var ErrMaxDepthExceeded = errors.New("maximum nesting depth exceeded")
+
 func New() *QueryParser {
+	return NewWithProps(Props{})
+}
+
+// NewWithProps returns a QueryParser configured by the props.
+func NewWithProps(props Props) *QueryParser {
+	maxDepth := props.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
+	}
 	return &QueryParser{
 		tokenizer: nil,
 		lookAhead: nil,
+		maxDepth:  maxDepth,
 	}
 }
 
 func (q *QueryParser) Run(text string) (*Program, error) {
 	q.tokenizer = querytokenizer.New(querytokenizer.Props{Text: text})
+	q.depth = 0
 	token, err := q.tokenizer.GetNextToken()
 	if err != nil {
 		return nil, err
@@ -153,6 +174,15 @@ func (q *QueryParser) Program() (*Program, error) {
 //	;
 ///*
 func (q *QueryParser) Expression() (*Node, error) {
+	if err := q.enter(); err != nil {
+		return nil, err
+	}
+	defer q.leave()
+
+	if q.lookAhead == nil {
+		return nil, errors.New("unexpected end of input, expected: expression\n")
+	}
+
 	switch q.lookAhead.TokenType {
 	case querytokenizer.LogicalOperator:
 		return q.Function()
@@ -300,6 +330,10 @@ func (q *QueryParser) Arguments() ([]*Node, error) {
 //	;
 ///*
 func (q *QueryParser) Literal() (*Node, error) {
+	if q.lookAhead == nil {
+		return nil, errors.New("unexpected end of input, expected: literal\n")
+	}
+
 	switch q.lookAhead.TokenType {
 	case querytokenizer.BooleanToken:
 		return q.BooleanLiteral()
@@ -383,6 +417,18 @@ func (q *QueryParser) Identifier() (*Node, error) {
 	return &Node{NodeType: Identifier, Body: &StringLiteralValue{token.Value}}, nil
 }
 
+func (q *QueryParser) enter() error {
+	q.depth++
+	if q.maxDepth > 0 && q.depth > q.maxDepth {
+		return ErrMaxDepthExceeded
+	}
+	return nil
+}
+
+func (q *QueryParser) leave() {
+	q.depth--
+}
+
 func (q *QueryParser) eat(tokenType string) (*querytokenizer.Token, error) {
 	token := q.lookAhead
 	if token == nil {
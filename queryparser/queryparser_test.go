@@ -3,6 +3,7 @@ package queryparser
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,12 +16,22 @@ func TestNew(t *testing.T) {
 		assertion.Equal(&QueryParser{
 			tokenizer: nil,
 			lookAhead: nil,
+			maxDepth:  DefaultMaxDepth,
+		}, parser)
+	})
+	t.Run("given NewWithProps with MaxDepth, return new QueryParser", func(t *testing.T) {
+		parser := NewWithProps(Props{MaxDepth: 10})
+		assertion.Equal(&QueryParser{
+			tokenizer: nil,
+			lookAhead: nil,
+			maxDepth:  10,
 		}, parser)
 	})
 }
 
 type test struct {
 	text            string
+	maxDepth        int
 	expectedProgram *Program
 	expectedError   error
 }
@@ -668,5 +679,71 @@ func TestRun(t *testing.T) {
 				})
 			}
 		})
+		t.Run("MaxDepth", func(t *testing.T) {
+			tests := map[string]test{
+				"given nesting within max depth": {
+					text:     `and(eq(a, 1), eq(b, 2))`,
+					maxDepth: 2,
+					expectedProgram: &Program{
+						NodeType: ProgramEnum,
+						Body: &Node{
+							NodeType: LogicalFunction,
+							Body: &FunctionNode{
+								Operator: "and",
+								Arguments: []*Node{
+									{
+										NodeType: RelationalFunction,
+										Body: &FunctionNode{
+											Operator: "eq",
+											Arguments: []*Node{
+												{NodeType: Identifier, Body: &StringLiteralValue{"a"}},
+												{NodeType: NumericLiteral, Body: &NumericLiteralValue{1}},
+											},
+										},
+									},
+									{
+										NodeType: RelationalFunction,
+										Body: &FunctionNode{
+											Operator: "eq",
+											Arguments: []*Node{
+												{NodeType: Identifier, Body: &StringLiteralValue{"b"}},
+												{NodeType: NumericLiteral, Body: &NumericLiteralValue{2}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"given nesting beyond max depth": {
+					text:          `and(and(1, 2), 3)`,
+					maxDepth:      2,
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given nesting beyond default max depth": {
+					text:          strings.Repeat("and(", 100000),
+					expectedError: ErrMaxDepthExceeded,
+				},
+				"given nesting with max depth disabled": {
+					text:          strings.Repeat("and(", 1000),
+					maxDepth:      -1,
+					expectedError: errors.New("unexpected end of input, expected: expression\n"),
+				},
+				"given unterminated arguments": {
+					text:          `and(1,`,
+					expectedError: errors.New("unexpected end of input, expected: expression\n"),
+				},
+			}
+
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					parser := NewWithProps(Props{MaxDepth: tc.maxDepth})
+					node, err := parser.Run(tc.text)
+					assert.Equal(t, tc.expectedProgram, node)
+					assert.Equal(t, tc.expectedError, err)
+				})
+			}
+		})
 	})
 }
@@ -7,12 +7,16 @@ import (
 )
 
 type Tokenizer struct {
-	text   string
-	cursor int
+	text       string
+	cursor     int
+	emitTrivia bool
 }
 
+// Props
+// EmitTrivia returns whitespace as tokens instead of skipping it.
 type Props struct {
-	Text string
+	Text       string
+	EmitTrivia bool
 }
 
 type Token struct {
@@ -31,13 +35,17 @@ const (
 	RelationalOperator        = "RELATIONAL_OPERATOR"
 	LogicalOperator           = "LOGICAL_OPERATOR"
 	Identifier                = "IDENTIFIER"
-	SkipToken                 = ""
+	WhitespaceToken           = "WHITESPACE"
+	// Deprecated: whitespace is no longer skipped, it is read as WhitespaceToken
+	// trivia.
+	SkipToken = ""
 )
 
 func New(props Props) *Tokenizer {
 	tokenizer := &Tokenizer{
-		text:   props.Text,
-		cursor: 0,
+		text:       props.Text,
+		cursor:     0,
+		emitTrivia: props.EmitTrivia,
 	}
 	return tokenizer
 }
@@ -54,7 +62,7 @@ var spec = [][]string{
 	//---------------------------------------------------
 	// Whitespace
 
-	{`^\s+`, SkipToken},
+	{`^\s+`, WhitespaceToken},
 
 	//---------------------------------------------------
 	// Symbols, Delimiters
@@ -107,6 +115,11 @@ var spec = [][]string{
 	{`^'[^']*'`, StringToken},
 }
 
+// IsTrivia reports whether tokens of the type carry no meaning for the grammar.
+func IsTrivia(tokenType string) bool {
+	return tokenType == WhitespaceToken
+}
+
 func (t *Tokenizer) GetNextToken() (*Token, error) {
 	if !t.hasMoreTokens() {
 		return nil, errors.New("no tokens present")
@@ -122,7 +135,7 @@ func (t *Tokenizer) GetNextToken() (*Token, error) {
 		if tokenValue == "" {
 			continue
 		}
-		if tokenType == SkipToken {
+		if IsTrivia(tokenType) && !t.emitTrivia {
 			return t.GetNextToken()
 		}
 		return &Token{TokenType: tokenType, Value: tokenValue}, nil
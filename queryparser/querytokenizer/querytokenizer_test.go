@@ -363,4 +363,27 @@ func TestGetNextToken(t *testing.T) {
 			})
 		}
 	})
-}
\ No newline at end of file
+}
+
+func TestEmitTrivia(t *testing.T) {
+	t.Run("given EmitTrivia, return whitespace as tokens", func(t *testing.T) {
+		tokenizer := New(Props{Text: "eq(a, 1)", EmitTrivia: true})
+		tokens := make([]*Token, 0)
+		for {
+			token, err := tokenizer.GetNextToken()
+			if err != nil {
+				break
+			}
+			tokens = append(tokens, token)
+		}
+		assert.Equal(t, []*Token{
+			{TokenType: RelationalOperator, Value: "eq"},
+			{TokenType: OpenParentheses, Value: "("},
+			{TokenType: Identifier, Value: "a"},
+			{TokenType: Comma, Value: ","},
+			{TokenType: WhitespaceToken, Value: " "},
+			{TokenType: NumberToken, Value: "1"},
+			{TokenType: CloseParentheses, Value: ")"},
+		}, tokens)
+	})
+}
@@ -0,0 +1,228 @@
+package repl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/evaluator"
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/dlanell/go-rdparser/parser/tokenizer"
+)
+
+type REPL struct {
+	in          *bufio.Scanner
+	out         io.Writer
+	evaluator   *evaluator.Evaluator
+	mode        string
+	history     []string
+	historyFile string
+}
+
+type Props struct {
+	In          io.Reader
+	Out         io.Writer
+	HistoryFile string
+	Limits      evaluator.Props
+}
+
+const (
+	EvalMode string = ":eval"
+	ASTMode         = ":ast"
+)
+
+const (
+	Prompt             string = "> "
+	ContinuationPrompt        = "... "
+	HistoryCommand            = ":history"
+	QuitCommand               = ":quit"
+	HelpCommand               = ":help"
+	RepeatLastCommand         = "!!"
+)
+
+const help = `:eval      print the evaluated result of each input (default)
+:ast       print the syntax tree of each input
+:history   list previous inputs, re-run one with !<number> or !!
+:quit      exit
+`
+
+func New(props Props) *REPL {
+	return &REPL{
+		in:          bufio.NewScanner(props.In),
+		out:         props.Out,
+		evaluator:   evaluator.New(props.Limits),
+		mode:        EvalMode,
+		history:     loadHistory(props.HistoryFile),
+		historyFile: props.HistoryFile,
+	}
+}
+
+// Run reads inputs until the reader is exhausted or :quit is entered.
+// Inputs with unbalanced braces, brackets or parentheses continue on the next line.
+func (r *REPL) Run() error {
+	for {
+		input, ok := r.read()
+		if !ok {
+			return r.in.Err()
+		}
+
+		switch {
+		case input == "":
+			continue
+		case input == QuitCommand:
+			return nil
+		case input == EvalMode || input == ASTMode:
+			r.mode = input
+			continue
+		case input == HelpCommand:
+			fmt.Fprint(r.out, help)
+			continue
+		case input == HistoryCommand:
+			r.printHistory()
+			continue
+		case strings.HasPrefix(input, "!"):
+			recalled, err := r.recall(input)
+			if err != nil {
+				fmt.Fprintf(r.out, "error: %s\n", err)
+				continue
+			}
+			fmt.Fprintln(r.out, recalled)
+			input = recalled
+		}
+
+		r.remember(input)
+		r.execute(input)
+	}
+}
+
+func (r *REPL) read() (string, bool) {
+	lines := make([]string, 0)
+	prompt := Prompt
+	for {
+		fmt.Fprint(r.out, prompt)
+		if !r.in.Scan() {
+			return "", false
+		}
+		lines = append(lines, r.in.Text())
+
+		input := strings.TrimSpace(strings.Join(lines, "\n"))
+		if isBalanced(input) {
+			return input, true
+		}
+		prompt = ContinuationPrompt
+	}
+}
+
+func (r *REPL) execute(input string) {
+	program, err := parser.New(parser.Props{Text: input}).Run()
+	if err != nil {
+		fmt.Fprintf(r.out, "error: %s\n", strings.TrimSpace(err.Error()))
+		return
+	}
+
+	if r.mode == ASTMode {
+		ast, _ := json.MarshalIndent(program, "", "  ")
+		fmt.Fprintln(r.out, string(ast))
+		return
+	}
+
+	value, err := r.evaluator.Run(context.Background(), program)
+	if err != nil {
+		fmt.Fprintf(r.out, "error: %s\n", err)
+		return
+	}
+	fmt.Fprintln(r.out, format(value))
+}
+
+func (r *REPL) remember(input string) {
+	r.history = append(r.history, input)
+	if r.historyFile == "" {
+		return
+	}
+
+	file, err := os.OpenFile(r.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, strconv.Quote(input))
+}
+
+func (r *REPL) recall(input string) (string, error) {
+	if input == RepeatLastCommand {
+		if len(r.history) == 0 {
+			return "", fmt.Errorf("history is empty")
+		}
+		return r.history[len(r.history)-1], nil
+	}
+
+	index, err := strconv.Atoi(input[1:])
+	if err != nil || index < 1 || index > len(r.history) {
+		return "", fmt.Errorf("no history entry: %s", input[1:])
+	}
+	return r.history[index-1], nil
+}
+
+func (r *REPL) printHistory() {
+	for index, input := range r.history {
+		fmt.Fprintf(r.out, "%4d  %s\n", index+1, input)
+	}
+}
+
+func loadHistory(path string) []string {
+	history := make([]string, 0)
+	if path == "" {
+		return history
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return history
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		input, err := strconv.Unquote(scanner.Text())
+		if err != nil {
+			continue
+		}
+		history = append(history, input)
+	}
+	return history
+}
+
+// isBalanced reports whether every brace, bracket and parenthesis in the input is
+// closed.
+// Input the tokenizer cannot read is considered balanced so the parser can report it.
+func isBalanced(input string) bool {
+	depth := 0
+	t := tokenizer.New(tokenizer.Props{Text: input})
+	for {
+		token, err := t.GetNextToken()
+		if err != nil {
+			return depth <= 0
+		}
+		switch token.TokenType {
+		case tokenizer.OpenCurlyBrace, tokenizer.OpenParentheses, tokenizer.OpenBracket:
+			depth++
+		case tokenizer.CloseCurlyBrace, tokenizer.CloseParentheses, tokenizer.CloseBracket:
+			depth--
+		}
+	}
+}
+
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,99 @@
+package repl
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	input          string
+	expectedOutput string
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given expression": {
+			input:          "2 + 2;\n",
+			expectedOutput: "> 4\n> ",
+		},
+		"given string expression": {
+			input:          "'sith';\n",
+			expectedOutput: "> \"sith\"\n> ",
+		},
+		"given variables declared across inputs": {
+			input:          "let x = 40;\nx + 2;\n",
+			expectedOutput: "> null\n> 42\n> ",
+		},
+		"given multi-line block": {
+			input:          "let x = 1;\nif (x) {\nx = 'one';\n}\n",
+			expectedOutput: "> null\n> ... ... \"one\"\n> ",
+		},
+		"given multi-line array": {
+			input:          "let a = [\n1,\n2\n];\na[1];\n",
+			expectedOutput: "> ... ... ... null\n> 2\n> ",
+		},
+		"given parse error": {
+			input:          "let;\n",
+			expectedOutput: "> error: Unexpected token: ;, expected: IDENTIFIER\n> ",
+		},
+		"given evaluation error": {
+			input:          "y;\n",
+			expectedOutput: "> error: y is not defined\n> ",
+		},
+		"given ast mode": {
+			input: ":ast\n42;\n:eval\n42;\n",
+			expectedOutput: "> > " + `{
+  "NodeType": "Program",
+  "Body": [
+    {
+      "NodeType": "ExpressionStatement",
+      "Body": {
+        "NodeType": "NumericLiteral",
+        "Body": {
+          "Value": 42
+        }
+      }
+    }
+  ]
+}
+` + "> > 42\n> ",
+		},
+		"given history": {
+			input:          "1;\n2;\n:history\n!1\n!!\n!9\n",
+			expectedOutput: "> 1\n> 2\n>    1  1;\n   2  2;\n> 1;\n1\n> 1;\n1\n> error: no history entry: 9\n> ",
+		},
+		"given quit": {
+			input:          ":quit\n1;\n",
+			expectedOutput: "> ",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := New(Props{In: strings.NewReader(tc.input), Out: out}).Run()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, out.String())
+		})
+	}
+
+	t.Run("given history file, persist inputs across sessions", func(t *testing.T) {
+		historyFile := filepath.Join(t.TempDir(), "history")
+
+		err := New(Props{In: strings.NewReader("let x = {\n};\n"), Out: &bytes.Buffer{}, HistoryFile: historyFile}).Run()
+		assert.NoError(t, err)
+
+		out := &bytes.Buffer{}
+		err = New(Props{In: strings.NewReader(":history\n"), Out: out, HistoryFile: historyFile}).Run()
+		assert.NoError(t, err)
+		assert.Equal(t, ">    1  let x = {\n};\n> ", out.String())
+
+		_, statErr := os.Stat(historyFile)
+		assert.NoError(t, statErr)
+	})
+}
@@ -0,0 +1,298 @@
+package semantic
+
+import (
+	"fmt"
+
+	"github.com/dlanell/go-rdparser/parser"
+)
+
+// Analyzer
+// breakable counts the enclosing statements a break may leave, functions the enclosing
+// functions a return may leave and labels holds the labels of the enclosing statements.
+type Analyzer struct {
+	scope     *scope
+	breakable int
+	functions int
+	labels    []string
+	errors    []*Error
+}
+
+type Props struct{}
+
+// Error is a program that parses but breaks a rule of the language,
+// Loc is only set when the program was parsed with Locations.
+type Error struct {
+	Message string
+	Loc     *parser.Location
+}
+
+// scope maps the names declared in it to their declaration kind, function marks the
+// scope var declarations belong to.
+type scope struct {
+	bindings map[string]string
+	function bool
+	parent   *scope
+}
+
+func New(props Props) *Analyzer {
+	return &Analyzer{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Run checks the program and returns every error found, in source order.
+func (a *Analyzer) Run(program *parser.Program) []*Error {
+	a.errors = make([]*Error, 0)
+	a.scope = &scope{bindings: map[string]string{}, function: true}
+	a.breakable = 0
+	a.functions = 0
+	a.labels = nil
+
+	a.declareVars(program.Body)
+	a.statementList(program.Body)
+	return a.errors
+}
+
+func (a *Analyzer) statementList(statements []*parser.Node) {
+	a.declareLexical(statements)
+	for _, statement := range statements {
+		a.visit(statement)
+	}
+}
+
+func (a *Analyzer) visit(node *parser.Node) {
+	switch node.NodeType {
+	case parser.BlockStatement:
+		a.scope = &scope{bindings: map[string]string{}, parent: a.scope}
+		a.statementList(node.Body.([]*parser.Node))
+		a.scope = a.scope.parent
+	case parser.SwitchStatement:
+		a.switchStatement(node.Body.(*parser.SwitchStatementValue))
+	case parser.LabeledStatement:
+		a.labeledStatement(node.Body.(*parser.LabeledStatementValue))
+	case parser.BreakStatement:
+		if label, ok := node.Body.(*parser.Node); ok {
+			a.checkLabel(label)
+		} else if a.breakable == 0 {
+			a.report(node, "illegal break statement")
+		}
+	case parser.ReturnStatement:
+		if a.functions == 0 {
+			a.report(node, "illegal return statement")
+		}
+		for _, child := range parser.Children(node) {
+			a.visit(child)
+		}
+	case parser.FunctionExpression, parser.ArrowFunctionExpression:
+		a.function(node.Body.(*parser.FunctionValue))
+	case parser.CatchClause:
+		value := node.Body.(*parser.CatchClauseValue)
+		a.scope = &scope{bindings: map[string]string{}, parent: a.scope}
+		if value.Param != nil {
+			a.scope.bindings[value.Param.Body.(*parser.StringLiteralValue).Value] = parser.KindLet
+		}
+		a.visit(value.Body)
+		a.scope = a.scope.parent
+	case parser.AssignmentExpression:
+		a.assignmentExpression(node.Body.(*parser.BinaryExpressionNode))
+	case parser.UpdateExpression:
+		a.checkAssignable(node.Body.(*parser.UpdateExpressionValue).Argument)
+	default:
+		for _, child := range parser.Children(node) {
+			a.visit(child)
+		}
+	}
+}
+
+// switchStatement checks the cases in one scope shared by all of them.
+func (a *Analyzer) switchStatement(node *parser.SwitchStatementValue) {
+	a.visit(node.Discriminant)
+
+	a.scope = &scope{bindings: map[string]string{}, parent: a.scope}
+	a.breakable++
+	for _, clause := range node.Cases {
+		a.declareLexical(clause.Body.(*parser.SwitchCaseValue).Consequent)
+	}
+	for _, clause := range node.Cases {
+		for _, child := range parser.Children(clause) {
+			a.visit(child)
+		}
+	}
+	a.breakable--
+	a.scope = a.scope.parent
+}
+
+// labeledStatement checks the body with the label in scope, a label may not be
+// declared again inside its own statement.
+func (a *Analyzer) labeledStatement(node *parser.LabeledStatementValue) {
+	name := node.Label.Body.(*parser.StringLiteralValue).Value
+	if a.hasLabel(name) {
+		a.report(node.Label, fmt.Sprintf("label has already been declared: %s", name))
+	}
+	a.labels = append(a.labels, name)
+	a.visit(node.Body)
+	a.labels = a.labels[:len(a.labels)-1]
+}
+
+// checkLabel reports a label of a break statement that no enclosing statement
+// declares.
+func (a *Analyzer) checkLabel(label *parser.Node) {
+	name := label.Body.(*parser.StringLiteralValue).Value
+	if !a.hasLabel(name) {
+		a.report(label, fmt.Sprintf("undefined label: %s", name))
+	}
+}
+
+func (a *Analyzer) hasLabel(name string) bool {
+	for _, label := range a.labels {
+		if label == name {
+			return true
+		}
+	}
+	return false
+}
+
+// function checks the body in a new function scope holding the name of a function
+// expression and the parameters, a break cannot leave the function nor refer to its
+// labels.
+func (a *Analyzer) function(node *parser.FunctionValue) {
+	a.scope = &scope{bindings: map[string]string{}, function: true, parent: a.scope}
+	if node.Id != nil {
+		a.scope.bindings[node.Id.Body.(*parser.StringLiteralValue).Value] = parser.KindConst
+	}
+	for _, param := range node.Params {
+		for _, identifier := range parser.BindingIdentifiers(param) {
+			a.scope.bindings[identifier.Body.(*parser.StringLiteralValue).Value] = parser.KindLet
+		}
+	}
+	breakable, labels := a.breakable, a.labels
+	a.breakable = 0
+	a.labels = nil
+	a.functions++
+
+	if node.Expression {
+		a.visit(node.Body)
+	} else {
+		statements := node.Body.Body.([]*parser.Node)
+		a.declareVars(statements)
+		a.statementList(statements)
+	}
+
+	a.functions--
+	a.breakable = breakable
+	a.labels = labels
+	a.scope = a.scope.parent
+}
+
+func (a *Analyzer) assignmentExpression(node *parser.BinaryExpressionNode) {
+	a.checkAssignable(node.Left.(*parser.Node))
+	a.visit(node.Right.(*parser.Node))
+}
+
+// checkAssignable reports every constant the target, an Identifier or a pattern,
+// assigns to and checks the defaults of the pattern.
+func (a *Analyzer) checkAssignable(target *parser.Node) {
+	for _, identifier := range parser.BindingIdentifiers(target) {
+		name := identifier.Body.(*parser.StringLiteralValue).Value
+		if a.scope.lookup(name) == parser.KindConst {
+			a.report(identifier, fmt.Sprintf("assignment to constant variable: %s", name))
+		}
+	}
+	a.visitDefaults(target)
+}
+
+// visitDefaults checks the default values of a pattern.
+func (a *Analyzer) visitDefaults(target *parser.Node) {
+	parser.Walk(target, func(node *parser.Node) bool {
+		switch node.NodeType {
+		case parser.AssignmentPattern:
+			value := node.Body.(*parser.AssignmentPatternValue)
+			a.visitDefaults(value.Left)
+			a.visit(value.Right)
+			return false
+		case parser.Property:
+			value := node.Body.(*parser.PropertyValue)
+			a.visitDefaults(value.Value)
+			return false
+		}
+		return true
+	})
+}
+
+// declareLexical declares the let and const bindings and the imports of a statement
+// list, they are visible throughout the block they are declared in.
+func (a *Analyzer) declareLexical(statements []*parser.Node) {
+	for _, statement := range statements {
+		if statement.NodeType == parser.ImportDeclaration {
+			for _, specifier := range statement.Body.(*parser.ImportDeclarationValue).Specifiers {
+				local := specifier.Body.(*parser.ImportSpecifierValue).Local
+				a.scope.bindings[local.Body.(*parser.StringLiteralValue).Value] = parser.KindConst
+			}
+			continue
+		}
+		if statement.NodeType == parser.ExportDeclaration {
+			statement = statement.Body.(*parser.Node)
+		}
+		if statement.NodeType != parser.VariableStatement {
+			continue
+		}
+		value := statement.Body.(*parser.VariableStatementValue)
+		if value.Kind == parser.KindVar {
+			continue
+		}
+		for _, declaration := range value.Declarations {
+			for _, name := range declarationNames(declaration) {
+				a.scope.bindings[name] = value.Kind
+			}
+		}
+	}
+}
+
+// declareVars declares every var of the statements in the function scope, leaving out
+// those of nested functions.
+func (a *Analyzer) declareVars(statements []*parser.Node) {
+	for _, statement := range statements {
+		parser.Walk(statement, func(node *parser.Node) bool {
+			if node.NodeType == parser.FunctionExpression || node.NodeType == parser.ArrowFunctionExpression {
+				return false
+			}
+			if node.NodeType != parser.VariableStatement {
+				return true
+			}
+			value := node.Body.(*parser.VariableStatementValue)
+			if value.Kind == parser.KindVar {
+				for _, declaration := range value.Declarations {
+					for _, name := range declarationNames(declaration) {
+						a.scope.bindings[name] = value.Kind
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+func (a *Analyzer) report(node *parser.Node, message string) {
+	a.errors = append(a.errors, &Error{Message: message, Loc: node.Loc})
+}
+
+// lookup returns the kind of the declaration the name resolves to, or "" when it is
+// not declared.
+func (s *scope) lookup(name string) string {
+	for current := s; current != nil; current = current.parent {
+		if kind, ok := current.bindings[name]; ok {
+			return kind
+		}
+	}
+	return ""
+}
+
+func declarationNames(declaration *parser.Node) []string {
+	names := make([]string, 0)
+	for _, identifier := range parser.BindingIdentifiers(declaration.Body.(*parser.VariableDeclarationValue).Id) {
+		names = append(names, identifier.Body.(*parser.StringLiteralValue).Value)
+	}
+	return names
+}
@@ -0,0 +1,135 @@
+package semantic
+
+import (
+	"testing"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	text           string
+	expectedErrors []*Error
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given assignment to let": {
+			text:           `let x = 1; x = 2;`,
+			expectedErrors: []*Error{},
+		},
+		"given assignment to const": {
+			text: `const x = 1; x = 2;`,
+			expectedErrors: []*Error{
+				{Message: "assignment to constant variable: x", Loc: &parser.Location{Start: 13, End: 14}},
+			},
+		},
+		"given compound assignment to const in nested expression": {
+			text: `const x = 1; let y; y = (x += 2);`,
+			expectedErrors: []*Error{
+				{Message: "assignment to constant variable: x", Loc: &parser.Location{Start: 25, End: 26}},
+			},
+		},
+		"given assignment to const before its declaration in block": {
+			text: `let x; { x = 1; const x = 2; }`,
+			expectedErrors: []*Error{
+				{Message: "assignment to constant variable: x", Loc: &parser.Location{Start: 9, End: 10}},
+			},
+		},
+		"given assignment to let shadowing const": {
+			text:           `const x = 1; { let x; x = 2; }`,
+			expectedErrors: []*Error{},
+		},
+		"given assignment to const outside its block": {
+			text:           `let x; { const x = 1; } x = 2;`,
+			expectedErrors: []*Error{},
+		},
+		"given assignment to var declared in block": {
+			text:           `const y = 1; { var x = 1; } x = 2;`,
+			expectedErrors: []*Error{},
+		},
+		"given assignments to const in if and conditional expression": {
+			text: `const x = 1; if (x) x = 2; else true ? 1 : x = 3;`,
+			expectedErrors: []*Error{
+				{Message: "assignment to constant variable: x", Loc: &parser.Location{Start: 20, End: 21}},
+				{Message: "assignment to constant variable: x", Loc: &parser.Location{Start: 43, End: 44}},
+			},
+		},
+		"given update of const": {
+			text: `const x = 1; let y = 1; y++; --x;`,
+			expectedErrors: []*Error{
+				{Message: "assignment to constant variable: x", Loc: &parser.Location{Start: 31, End: 32}},
+			},
+		},
+		"given break outside of switch": {
+			text: `switch (x) { case 1: break; } { break; }`,
+			expectedErrors: []*Error{
+				{Message: "illegal break statement", Loc: &parser.Location{Start: 32, End: 38}},
+			},
+		},
+		"given labeled break": {
+			text:           `outer: { inner: if (x) { switch (x) { case 1: break outer; } break inner; } }`,
+			expectedErrors: []*Error{},
+		},
+		"given undefined and redeclared labels": {
+			text: `a: { break b; } a: { a: ; (() => { break a; })(); }`,
+			expectedErrors: []*Error{
+				{Message: "undefined label: b", Loc: &parser.Location{Start: 11, End: 12}},
+				{Message: "label has already been declared: a", Loc: &parser.Location{Start: 21, End: 22}},
+				{Message: "undefined label: a", Loc: &parser.Location{Start: 41, End: 42}},
+			},
+		},
+		"given assignment to const declared in switch": {
+			text: `switch (x) { case 1: y = 2; break; default: const y = 1; }`,
+			expectedErrors: []*Error{
+				{Message: "assignment to constant variable: y", Loc: &parser.Location{Start: 21, End: 22}},
+			},
+		},
+		"given assignment to import": {
+			text: `import { a as b } from "./lib"; export let c = b; b = 2;`,
+			expectedErrors: []*Error{
+				{Message: "assignment to constant variable: b", Loc: &parser.Location{Start: 50, End: 51}},
+			},
+		},
+		"given return and break in functions": {
+			text: `return; switch (x) { case 1: (() => { break; return; })(); }`,
+			expectedErrors: []*Error{
+				{Message: "illegal return statement", Loc: &parser.Location{Start: 0, End: 7}},
+				{Message: "illegal break statement", Loc: &parser.Location{Start: 38, End: 44}},
+			},
+		},
+		"given assignment to parameter shadowing const": {
+			text:           `const x = 1; let f = x => { x = 2; var y = 1; }; const y = 2;`,
+			expectedErrors: []*Error{},
+		},
+		"given destructuring assignment to const": {
+			text: `const { a, b: [c] } = o; let d; [d, { e: a = c }] = o; ({ c = 1 } = o);`,
+			expectedErrors: []*Error{
+				{Message: "assignment to constant variable: a", Loc: &parser.Location{Start: 41, End: 42}},
+				{Message: "assignment to constant variable: c", Loc: &parser.Location{Start: 58, End: 59}},
+			},
+		},
+		"given assignment to rest parameter shadowing const": {
+			text:           `const rest = 1; let f = (...rest) => { rest = []; };`,
+			expectedErrors: []*Error{},
+		},
+		"given rest assignment to const": {
+			text: `const rest = 1; let a; [a, ...rest] = [];`,
+			expectedErrors: []*Error{
+				{Message: "assignment to constant variable: rest", Loc: &parser.Location{Start: 30, End: 34}},
+			},
+		},
+		"given assignment to catch parameter shadowing const": {
+			text:           `const e = 1; try { e; } catch (e) { e = 2; }`,
+			expectedErrors: []*Error{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			program, err := parser.New(parser.Props{Text: tc.text, Locations: true}).Run()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedErrors, New(Props{}).Run(program))
+		})
+	}
+}
@@ -0,0 +1,534 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dlanell/go-rdparser/parser"
+)
+
+// Checker
+// variables are keyed by the identifier declaring them so they outlive the scopes of
+// a pass, changed is set when a pass widened the type of a variable and reporting for
+// the final pass once the types are stable.
+type Checker struct {
+	scope     *scope
+	variables map[*parser.Node]*variable
+	types     map[*parser.Node]Type
+	changed   bool
+	reporting bool
+	errors    []*Error
+}
+
+type Props struct{}
+
+// Error is an operation or assignment failing for every value of the types involved,
+// Loc is only set when the program was parsed with Locations.
+type Error struct {
+	Message string
+	Loc     *parser.Location
+}
+
+// variable
+// declared is the type of the annotation, 0 without one, and inferred the union of
+// the values assigned to a variable without annotation.
+type variable struct {
+	declared Type
+	inferred Type
+}
+
+type scope struct {
+	names  map[string]*variable
+	parent *scope
+}
+
+func New(props Props) *Checker {
+	return &Checker{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Run infers the type of every expression of the program and returns the errors in
+// source order. The type of a variable is its annotation or else the union of every
+// value assigned to it anywhere, parameters and undeclared names are Unknown.
+func (c *Checker) Run(program *parser.Program) []*Error {
+	c.variables = map[*parser.Node]*variable{}
+	c.types = map[*parser.Node]Type{}
+	for c.changed = true; c.changed; {
+		c.changed = false
+		c.program(program)
+	}
+
+	c.errors = make([]*Error, 0)
+	c.reporting = true
+	c.program(program)
+	c.reporting = false
+
+	sort.SliceStable(c.errors, func(i, j int) bool {
+		return c.errors[i].Loc != nil && c.errors[j].Loc != nil && c.errors[i].Loc.Start < c.errors[j].Loc.Start
+	})
+	return c.errors
+}
+
+// TypeOf returns the type the last Run inferred for the expression, Unknown for any
+// other node.
+func (c *Checker) TypeOf(node *parser.Node) Type {
+	if t, ok := c.types[node]; ok {
+		return t
+	}
+	return Unknown
+}
+
+func (c *Checker) program(program *parser.Program) {
+	c.scope = &scope{names: map[string]*variable{}}
+	c.declareVars(program.Body)
+	c.statementList(program.Body)
+}
+
+func (c *Checker) statementList(statements []*parser.Node) {
+	c.declareLexical(statements)
+	for _, statement := range statements {
+		c.statement(statement)
+	}
+}
+
+func (c *Checker) statement(node *parser.Node) {
+	switch node.NodeType {
+	case parser.BlockStatement:
+		c.scope = &scope{names: map[string]*variable{}, parent: c.scope}
+		c.statementList(node.Body.([]*parser.Node))
+		c.scope = c.scope.parent
+	case parser.VariableStatement:
+		for _, declaration := range parser.Children(node) {
+			c.variableDeclaration(declaration.Body.(*parser.VariableDeclarationValue))
+		}
+	case parser.ExportDeclaration:
+		c.statement(node.Body.(*parser.Node))
+	case parser.ExpressionStatement:
+		c.expression(node.Body.(*parser.Node))
+	case parser.IfStatement:
+		value := node.Body.(*parser.IfStatementValue)
+		c.expression(value.Test)
+		c.statement(value.Consequent)
+		if value.Alternate != nil {
+			c.statement(value.Alternate)
+		}
+	case parser.SwitchStatement:
+		value := node.Body.(*parser.SwitchStatementValue)
+		c.expression(value.Discriminant)
+		c.scope = &scope{names: map[string]*variable{}, parent: c.scope}
+		for _, clause := range value.Cases {
+			c.declareLexical(clause.Body.(*parser.SwitchCaseValue).Consequent)
+		}
+		for _, clause := range value.Cases {
+			clauseValue := clause.Body.(*parser.SwitchCaseValue)
+			if clauseValue.Test != nil {
+				c.expression(clauseValue.Test)
+			}
+			for _, statement := range clauseValue.Consequent {
+				c.statement(statement)
+			}
+		}
+		c.scope = c.scope.parent
+	case parser.TryStatement:
+		value := node.Body.(*parser.TryStatementValue)
+		c.statement(value.Block)
+		if value.Handler != nil {
+			handler := value.Handler.Body.(*parser.CatchClauseValue)
+			c.scope = &scope{names: map[string]*variable{}, parent: c.scope}
+			if handler.Param != nil {
+				c.declare(handler.Param, Unknown)
+			}
+			c.statement(handler.Body)
+			c.scope = c.scope.parent
+		}
+		if value.Finalizer != nil {
+			c.statement(value.Finalizer)
+		}
+	case parser.ReturnStatement, parser.ThrowStatement:
+		if argument, ok := node.Body.(*parser.Node); ok {
+			c.expression(argument)
+		}
+	case parser.LabeledStatement:
+		c.statement(node.Body.(*parser.LabeledStatementValue).Body)
+	}
+}
+
+// variableDeclaration assigns the initializer to the target after checking the names
+// of the annotation. A variable declared without one holds null, which its annotation
+// need not include as it may be assigned before it is read.
+func (c *Checker) variableDeclaration(node *parser.VariableDeclarationValue) {
+	declared := Type(0)
+	if node.Type != nil {
+		declared = c.annotation(node.Type)
+	}
+	if node.Init == nil {
+		if v := c.lookup(node.Id); v != nil && v.declared == 0 {
+			c.assign(v, Null, node.Id)
+		}
+		return
+	}
+
+	t := c.expression(node.Init)
+	if node.Id.NodeType == parser.Identifier {
+		c.assign(c.lookup(node.Id), t, node.Init)
+		return
+	}
+	if declared != 0 && c.reporting && !t.AssignableTo(declared) {
+		c.report(node.Init, fmt.Sprintf("type %s is not assignable to type %s", t, declared))
+	}
+	c.assignPattern(node.Id)
+}
+
+func (c *Checker) expression(node *parser.Node) Type {
+	t := c.infer(node)
+	c.types[node] = t
+	return t
+}
+
+func (c *Checker) infer(node *parser.Node) Type {
+	switch node.NodeType {
+	case parser.NumericLiteral:
+		return Number
+	case parser.StringLiteral:
+		return String
+	case parser.BooleanLiteral:
+		return Boolean
+	case parser.NullLiteral:
+		return Null
+	case parser.RegExpLiteral:
+		return Object
+	case parser.Identifier:
+		if v := c.lookup(node); v != nil {
+			return v.current()
+		}
+		return Unknown
+	case parser.ArrayExpression, parser.ObjectExpression:
+		for _, element := range node.Body.([]*parser.Node) {
+			if element == nil {
+				continue
+			}
+			switch element.NodeType {
+			case parser.SpreadElement:
+				c.expression(element.Body.(*parser.Node))
+			case parser.Property:
+				c.expression(element.Body.(*parser.PropertyValue).Value)
+			default:
+				c.expression(element)
+			}
+		}
+		return Object
+	case parser.FunctionExpression, parser.ArrowFunctionExpression:
+		c.function(node.Body.(*parser.FunctionValue))
+		return Function
+	case parser.CallExpression:
+		value := node.Body.(*parser.CallExpressionValue)
+		c.expression(value.Callee)
+		for _, argument := range value.Arguments {
+			if argument.NodeType == parser.SpreadElement {
+				argument = argument.Body.(*parser.Node)
+			}
+			c.expression(argument)
+		}
+		return Unknown
+	case parser.MemberExpression:
+		value := node.Body.(*parser.MemberExpressionValue)
+		c.expression(value.Object)
+		if value.Computed {
+			c.expression(value.Property)
+		}
+		return Unknown
+	case parser.ChainExpression:
+		c.expression(node.Body.(*parser.Node))
+		return Unknown
+	case parser.ConditionalExpression:
+		value := node.Body.(*parser.ConditionalExpressionValue)
+		c.expression(value.Test)
+		return c.expression(value.Consequent).Union(c.expression(value.Alternate))
+	case parser.BinaryExpression:
+		return c.binaryExpression(node)
+	case parser.AssignmentExpression:
+		return c.assignmentExpression(node)
+	case parser.UnaryExpression:
+		value := node.Body.(*parser.UnaryExpressionValue)
+		c.checkNumber(node, value.Operator, c.expression(value.Argument))
+		return Number
+	case parser.UpdateExpression:
+		value := node.Body.(*parser.UpdateExpressionValue)
+		// the variable already holds numbers unless the update always fails
+		c.checkNumber(node, value.Operator, c.expression(value.Argument))
+		return Number
+	}
+	return Unknown
+}
+
+// binaryExpression follows the evaluator, equality holds for any operands while the
+// other operators fail unless both are numbers, or strings for + and the comparisons.
+func (c *Checker) binaryExpression(node *parser.Node) Type {
+	value := node.Body.(*parser.BinaryExpressionNode)
+	left, right := c.expression(value.Left.(*parser.Node)), c.expression(value.Right.(*parser.Node))
+
+	switch value.Operator {
+	case "&&", "AND", "||", "OR":
+		return left.Union(right)
+	case "??":
+		return left.Without(Null).Union(right)
+	case "==", "===", "!=", "!==":
+		return Boolean
+	case ">", ">=", "<", "<=":
+		if !(left.Includes(Number) && right.Includes(Number)) && !(left.Includes(String) && right.Includes(String)) {
+			c.invalidOperands(node, value.Operator, left, right)
+		}
+		return Boolean
+	}
+	return c.arithmetic(node, value.Operator, left, right)
+}
+
+// assignmentExpression checks the value against the variable assigned, a compound
+// assignment assigns the result of its operator.
+func (c *Checker) assignmentExpression(node *parser.Node) Type {
+	value := node.Body.(*parser.BinaryExpressionNode)
+	left, right := value.Left.(*parser.Node), value.Right.(*parser.Node)
+
+	if left.NodeType != parser.Identifier {
+		t := c.expression(right)
+		c.assignPattern(left)
+		return t
+	}
+
+	switch value.Operator {
+	case "=":
+		t := c.expression(right)
+		c.assign(c.lookup(left), t, right)
+		return t
+	case "??=":
+		current, t := c.expression(left), c.expression(right)
+		c.assign(c.lookup(left), t, right)
+		return current.Without(Null).Union(t)
+	}
+	t := c.arithmetic(node, strings.TrimSuffix(value.Operator, "="), c.expression(left), c.expression(right))
+	c.assign(c.lookup(left), t, node)
+	return t
+}
+
+func (c *Checker) arithmetic(node *parser.Node, operator string, left Type, right Type) Type {
+	switch operator {
+	case "+":
+		t := Type(0)
+		if left.Includes(String) || right.Includes(String) {
+			t = String
+		}
+		if left.Includes(Number) && right.Includes(Number) {
+			t |= Number
+		}
+		if t == 0 {
+			c.invalidOperands(node, operator, left, right)
+			return Unknown
+		}
+		return t
+	case "-", "*", "/", "%", "**", "&", "|", "^", "<<", ">>", ">>>":
+		if !left.Includes(Number) || !right.Includes(Number) {
+			c.invalidOperands(node, operator, left, right)
+		}
+		return Number
+	}
+	return Unknown
+}
+
+func (c *Checker) checkNumber(node *parser.Node, operator string, t Type) {
+	if c.reporting && !t.Includes(Number) {
+		c.report(node, fmt.Sprintf("invalid operand for %s: %s", operator, t))
+	}
+}
+
+func (c *Checker) invalidOperands(node *parser.Node, operator string, left Type, right Type) {
+	if c.reporting {
+		c.report(node, fmt.Sprintf("invalid operands for %s: %s, %s", operator, left, right))
+	}
+}
+
+// assign checks the type against the annotation of the variable or widens its
+// inferred type, node is where a mismatch is reported.
+func (c *Checker) assign(v *variable, t Type, node *parser.Node) {
+	switch {
+	case v == nil:
+	case v.declared != 0:
+		if c.reporting && !t.AssignableTo(v.declared) {
+			c.report(node, fmt.Sprintf("type %s is not assignable to type %s", t, v.declared))
+		}
+	case v.inferred.Union(t) != v.inferred:
+		v.inferred = v.inferred.Union(t)
+		c.changed = true
+	}
+}
+
+// assignPattern assigns Unknown to the identifiers of the pattern, only the defaults
+// are typed.
+func (c *Checker) assignPattern(target *parser.Node) {
+	for _, identifier := range parser.BindingIdentifiers(target) {
+		c.assign(c.lookup(identifier), Unknown, identifier)
+	}
+	c.visitDefaults(target)
+}
+
+// function types the body in a new function scope holding the name of a function
+// expression and the parameters.
+func (c *Checker) function(node *parser.FunctionValue) {
+	c.scope = &scope{names: map[string]*variable{}, parent: c.scope}
+	if node.Id != nil {
+		c.declare(node.Id, Function)
+	}
+	for _, param := range node.Params {
+		for _, identifier := range parser.BindingIdentifiers(param) {
+			c.declare(identifier, Unknown)
+		}
+		c.visitDefaults(param)
+	}
+
+	if node.Expression {
+		c.expression(node.Body)
+	} else {
+		statements := node.Body.Body.([]*parser.Node)
+		c.declareVars(statements)
+		c.statementList(statements)
+	}
+	c.scope = c.scope.parent
+}
+
+// visitDefaults types the default values of a binding target.
+func (c *Checker) visitDefaults(target *parser.Node) {
+	parser.Walk(target, func(node *parser.Node) bool {
+		switch node.NodeType {
+		case parser.AssignmentPattern:
+			value := node.Body.(*parser.AssignmentPatternValue)
+			c.visitDefaults(value.Left)
+			c.expression(value.Right)
+			return false
+		case parser.Property:
+			c.visitDefaults(node.Body.(*parser.PropertyValue).Value)
+			return false
+		}
+		return true
+	})
+}
+
+// annotation returns the union an annotation names, reporting the names that are not
+// types.
+func (c *Checker) annotation(node *parser.Node) Type {
+	for _, name := range node.Body.(*parser.TypeAnnotationValue).Types {
+		if _, ok := Parse(name); !ok && c.reporting {
+			c.report(node, fmt.Sprintf("unknown type: %s", name))
+		}
+	}
+	return annotated(node)
+}
+
+// declareLexical declares the let and const bindings and the imports of a statement
+// list.
+func (c *Checker) declareLexical(statements []*parser.Node) {
+	for _, statement := range statements {
+		if statement.NodeType == parser.ImportDeclaration {
+			for _, specifier := range statement.Body.(*parser.ImportDeclarationValue).Specifiers {
+				c.declare(specifier.Body.(*parser.ImportSpecifierValue).Local, Unknown)
+			}
+			continue
+		}
+		if statement.NodeType == parser.ExportDeclaration {
+			statement = statement.Body.(*parser.Node)
+		}
+		if statement.NodeType == parser.VariableStatement && statement.Body.(*parser.VariableStatementValue).Kind != parser.KindVar {
+			c.declareVariables(statement)
+		}
+	}
+}
+
+// declareVars declares every var of the statements in the function scope, leaving out
+// those of nested functions.
+func (c *Checker) declareVars(statements []*parser.Node) {
+	for _, statement := range statements {
+		parser.Walk(statement, func(node *parser.Node) bool {
+			if node.NodeType == parser.FunctionExpression || node.NodeType == parser.ArrowFunctionExpression {
+				return false
+			}
+			if node.NodeType == parser.VariableStatement && node.Body.(*parser.VariableStatementValue).Kind == parser.KindVar {
+				c.declareVariables(node)
+			}
+			return true
+		})
+	}
+}
+
+// declareVariables declares the identifiers of the declarations, the annotation of an
+// Identifier target being the type of its variable. A var declared twice is one
+// variable.
+func (c *Checker) declareVariables(statement *parser.Node) {
+	for _, declaration := range parser.Children(statement) {
+		value := declaration.Body.(*parser.VariableDeclarationValue)
+		for _, identifier := range parser.BindingIdentifiers(value.Id) {
+			name := identifier.Body.(*parser.StringLiteralValue).Value
+			if existing, ok := c.scope.names[name]; ok {
+				c.variables[identifier] = existing
+				continue
+			}
+			v := c.declare(identifier, 0)
+			if value.Type != nil && value.Id == identifier {
+				v.declared = annotated(value.Type)
+			}
+		}
+	}
+}
+
+// declare binds the identifier in the current scope to its variable, created with
+// the inferred type on the first pass.
+func (c *Checker) declare(identifier *parser.Node, inferred Type) *variable {
+	v, ok := c.variables[identifier]
+	if !ok {
+		v = &variable{inferred: inferred}
+		c.variables[identifier] = v
+	}
+	c.scope.names[identifier.Body.(*parser.StringLiteralValue).Value] = v
+	return v
+}
+
+// lookup returns the variable the identifier refers to, nil when it is not declared.
+func (c *Checker) lookup(identifier *parser.Node) *variable {
+	if identifier.NodeType != parser.Identifier {
+		return nil
+	}
+	name := identifier.Body.(*parser.StringLiteralValue).Value
+	for current := c.scope; current != nil; current = current.parent {
+		if v, ok := current.names[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c *Checker) report(node *parser.Node, message string) {
+	c.errors = append(c.errors, &Error{Message: message, Loc: node.Loc})
+}
+
+// current returns the type of the variable, its annotation when it has one.
+func (v *variable) current() Type {
+	if v.declared != 0 {
+		return v.declared
+	}
+	return v.inferred
+}
+
+// annotated returns the union an annotation names, Unknown for the names that are
+// not types.
+func annotated(node *parser.Node) Type {
+	t := Type(0)
+	for _, name := range node.Body.(*parser.TypeAnnotationValue).Types {
+		kind, ok := Parse(name)
+		if !ok {
+			kind = Unknown
+		}
+		t = t.Union(kind)
+	}
+	return t
+}
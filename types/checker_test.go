@@ -0,0 +1,122 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/dlanell/go-rdparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+type test struct {
+	text           string
+	expectedErrors []*Error
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]test{
+		"given well typed script": {
+			text:           `let x: number = 1; let s = "a" + x; let y = x * 2; if (s < "b" && y >= 0) { x = -y; }`,
+			expectedErrors: []*Error{},
+		},
+		"given string multiplied by number": {
+			text: `"a" * 2;`,
+			expectedErrors: []*Error{
+				{Message: "invalid operands for *: string, number", Loc: &parser.Location{Start: 0, End: 7}},
+			},
+		},
+		"given boolean compared with number": {
+			text: `let done = true; done < 1;`,
+			expectedErrors: []*Error{
+				{Message: "invalid operands for <: boolean, number", Loc: &parser.Location{Start: 17, End: 25}},
+			},
+		},
+		"given strings compared": {
+			text:           `"a" < "b";`,
+			expectedErrors: []*Error{},
+		},
+		"given invalid unary and update operands": {
+			text: `let b = false; b++; -"a";`,
+			expectedErrors: []*Error{
+				{Message: "invalid operand for ++: boolean", Loc: &parser.Location{Start: 15, End: 18}},
+				{Message: "invalid operand for -: string", Loc: &parser.Location{Start: 20, End: 24}},
+			},
+		},
+		"given variable assigned values of several types": {
+			text: `let x = 1; x = "a"; x * 2; let y = c ? true : null; y - 1;`,
+			expectedErrors: []*Error{
+				{Message: "invalid operands for -: boolean | null, number", Loc: &parser.Location{Start: 52, End: 57}},
+			},
+		},
+		"given variable assigned after its use": {
+			text:           `let f = () => x - 1; let x = 1; x = true;`,
+			expectedErrors: []*Error{},
+		},
+		"given concatenation used as number": {
+			text: `let t = "a" + 1; t - 1;`,
+			expectedErrors: []*Error{
+				{Message: "invalid operands for -: string, number", Loc: &parser.Location{Start: 17, End: 22}},
+			},
+		},
+		"given annotation mismatches": {
+			text: `let x: number = 1; x = "s"; let n: number = c ? 1 : null; let m: number | null = null; x += "a";`,
+			expectedErrors: []*Error{
+				{Message: "type string is not assignable to type number", Loc: &parser.Location{Start: 23, End: 26}},
+				{Message: "type number | null is not assignable to type number", Loc: &parser.Location{Start: 44, End: 56}},
+				{Message: "type string is not assignable to type number", Loc: &parser.Location{Start: 87, End: 95}},
+			},
+		},
+		"given annotation without initializer": {
+			text: `let x: string; x = "a"; x = null;`,
+			expectedErrors: []*Error{
+				{Message: "type null is not assignable to type string", Loc: &parser.Location{Start: 28, End: 32}},
+			},
+		},
+		"given annotation on pattern": {
+			text: `let { a }: number = { a: 1 }; a * 2;`,
+			expectedErrors: []*Error{
+				{Message: "type object is not assignable to type number", Loc: &parser.Location{Start: 20, End: 28}},
+			},
+		},
+		"given unknown type name": {
+			text: `let s: strin = "";`,
+			expectedErrors: []*Error{
+				{Message: "unknown type: strin", Loc: &parser.Location{Start: 5, End: 12}},
+			},
+		},
+		"given parameters, calls and members": {
+			text:           `let f = (a, b) => a * b; f(1).length * 2; let o = { n: 1 }; o.n - 1;`,
+			expectedErrors: []*Error{},
+		},
+		"given nullish coalescing": {
+			text: `let v: string | null = null; let w = v ?? 1; w < true; let n = null ?? 2; n - 1;`,
+			expectedErrors: []*Error{
+				{Message: "invalid operands for <: number | string, boolean", Loc: &parser.Location{Start: 45, End: 53}},
+			},
+		},
+		"given variable shadowed in block": {
+			text: `let x = "a"; { let x = 1; x * 2; } x * 2;`,
+			expectedErrors: []*Error{
+				{Message: "invalid operands for *: string, number", Loc: &parser.Location{Start: 35, End: 40}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			program, err := parser.New(parser.Props{Text: tc.text, Locations: true}).Run()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedErrors, New(Props{}).Run(program))
+		})
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	program, err := parser.New(parser.Props{Text: `let x = c ? 1 : "a"; let y = x; f(y);`}).Run()
+	assert.NoError(t, err)
+	checker := New(Props{})
+	assert.Equal(t, []*Error{}, checker.Run(program))
+
+	declarations := program.Body[1].Body.(*parser.VariableStatementValue).Declarations
+	assert.Equal(t, Number|String, checker.TypeOf(declarations[0].Body.(*parser.VariableDeclarationValue).Init))
+	assert.Equal(t, Unknown, checker.TypeOf(program.Body[2]))
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"strings"
+)
+
+// Type is the union of the kinds of values an expression may evaluate to, records,
+// lists and regular expressions being objects. Unknown absorbs every other kind, the
+// checker knows nothing of the value.
+type Type int
+
+const (
+	Number Type = 1 << iota
+	String
+	Boolean
+	Null
+	Object
+	Function
+	Unknown
+)
+
+var names = []struct {
+	kind Type
+	name string
+}{
+	{Number, "number"},
+	{String, "string"},
+	{Boolean, "boolean"},
+	{Null, "null"},
+	{Object, "object"},
+	{Function, "function"},
+	{Unknown, "unknown"},
+}
+
+// Parse returns the kind a type annotation names.
+func Parse(name string) (Type, bool) {
+	for _, entry := range names {
+		if entry.name == name {
+			return entry.kind, true
+		}
+	}
+	return 0, false
+}
+
+func (t Type) String() string {
+	if t&Unknown != 0 {
+		return "unknown"
+	}
+	if t == 0 {
+		return "never"
+	}
+	parts := make([]string, 0)
+	for _, entry := range names {
+		if t&entry.kind != 0 {
+			parts = append(parts, entry.name)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (t Type) Union(other Type) Type {
+	if (t|other)&Unknown != 0 {
+		return Unknown
+	}
+	return t | other
+}
+
+// Without removes the kind from the union, Unknown stays unknown.
+func (t Type) Without(kind Type) Type {
+	if t&Unknown != 0 {
+		return Unknown
+	}
+	return t &^ kind
+}
+
+// Includes reports whether a value of the kind may be of the type.
+func (t Type) Includes(kind Type) bool {
+	return t&Unknown != 0 || t&kind != 0
+}
+
+// AssignableTo reports whether every value of the type is one of other, a type is
+// assignable to and from Unknown.
+func (t Type) AssignableTo(other Type) bool {
+	return (t|other)&Unknown != 0 || t&^other == 0
+}
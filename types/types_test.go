@@ -0,0 +1,37 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestType(t *testing.T) {
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, "number | null", (Null | Number).String())
+		assert.Equal(t, "unknown", Number.Union(Unknown).String())
+		assert.Equal(t, "never", Type(0).String())
+	})
+
+	t.Run("Parse", func(t *testing.T) {
+		kind, ok := Parse("boolean")
+		assert.True(t, ok)
+		assert.Equal(t, Boolean, kind)
+		_, ok = Parse("int")
+		assert.False(t, ok)
+	})
+
+	t.Run("AssignableTo", func(t *testing.T) {
+		assert.True(t, Number.AssignableTo(Number|Null))
+		assert.False(t, (Number | Null).AssignableTo(Number))
+		assert.True(t, Unknown.AssignableTo(String))
+		assert.True(t, String.AssignableTo(Unknown))
+	})
+
+	t.Run("Without", func(t *testing.T) {
+		assert.Equal(t, Number, (Number | Null).Without(Null))
+		assert.Equal(t, Unknown, Unknown.Without(Null))
+		assert.True(t, Unknown.Includes(Function))
+		assert.False(t, Object.Includes(Function))
+	})
+}